@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultRegion is the region of an account created with the fixed strategy when Spec.Region is unset
+	DefaultRegion = "AWS_US_WEST_2"
+	// DefaultEdition is the edition of an account whose Spec.Edition is unset
+	DefaultEdition = EditionEnterprise
+	// DefaultMaxProvisioningDuration is how long provisioning is retried when Spec.MaxProvisioningDuration is unset
+	DefaultMaxProvisioningDuration = 6 * time.Hour
+)
+
+// Default sets the defaults the operator would otherwise apply when reconciling, so that they are
+// visible on the stored object. Spec.Duration is left unset, as an account only expires when asked to.
+func (in *SnowflakeAccount) Default() {
+	in.DefaultTo("", "")
+}
+
+// DefaultTo sets the defaults like Default, using the given edition and region, which fall back to
+// DefaultEdition and DefaultRegion when empty
+func (in *SnowflakeAccount) DefaultTo(edition Edition, region string) {
+	if edition == "" {
+		edition = DefaultEdition
+	}
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	if in.Spec.Edition == "" {
+		in.Spec.Edition = edition
+	}
+	if in.Spec.MaxProvisioningDuration == nil {
+		in.Spec.MaxProvisioningDuration = &metav1.Duration{Duration: DefaultMaxProvisioningDuration}
+	}
+	if in.Spec.DeletionPolicy == "" {
+		in.Spec.DeletionPolicy = DefaultDeletionPolicy(in.Spec.AdoptExisting)
+	}
+	// Other strategies pick the region from the operator's allowed regions when the account is created
+	strategy := in.Spec.RegionSelectionStrategy
+	if in.Spec.Region == "" && (strategy == "" || strategy == RegionSelectionFixed) {
+		in.Spec.Region = region
+	}
+}
+
+// DefaultDeletionPolicy returns the deletion policy of an account whose Spec.DeletionPolicy is unset:
+// an adopted account existed before the resource, so it is retained rather than dropped
+func DefaultDeletionPolicy(adopted bool) DeletionPolicy {
+	if adopted {
+		return DeletionPolicyRetain
+	}
+	return DeletionPolicyDelete
+}
@@ -17,12 +17,104 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// DeletionPolicy describes what happens to the Snowflake account when the SnowflakeAccount is deleted
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete drops the Snowflake account when the resource is deleted
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the Snowflake account when the resource is deleted
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// Phase is a machine-readable summary of where the account is in its lifecycle
+// +kubebuilder:validation:Enum=Pending;Provisioning;Ready;Expiring;Deleting;Failed
+type Phase string
+
+const (
+	// PhasePending means the account has not been created yet and creation has not started
+	PhasePending Phase = "Pending"
+	// PhaseProvisioning means the account is being created, restored or adopted
+	PhaseProvisioning Phase = "Provisioning"
+	// PhaseReady means the account exists and is managed by the operator
+	PhaseReady Phase = "Ready"
+	// PhaseExpiring means the account's duration has expired and its deletion has been requested
+	PhaseExpiring Phase = "Expiring"
+	// PhaseDeleting means the resource is being deleted and the account is being dropped or released
+	PhaseDeleting Phase = "Deleting"
+	// PhaseFailed means provisioning failed; the message describes why
+	PhaseFailed Phase = "Failed"
+)
+
+// CredentialsSecretType is the Kubernetes type of the credentials secret
+// +kubebuilder:validation:Enum=Opaque;kubernetes.io/basic-auth
+type CredentialsSecretType string
+
+const (
+	// CredentialsSecretTypeOpaque stores the credentials under Snowflake-specific keys only
+	CredentialsSecretTypeOpaque CredentialsSecretType = "Opaque"
+	// CredentialsSecretTypeBasicAuth additionally stores the admin credentials under the standard
+	// username and password keys
+	CredentialsSecretTypeBasicAuth CredentialsSecretType = "kubernetes.io/basic-auth"
+)
+
+// CredentialsSinkType selects where the details of a provisioned account are stored
+// +kubebuilder:validation:Enum=Kubernetes
+type CredentialsSinkType string
+
+const (
+	// CredentialsSinkKubernetes stores the details in the credentials secret
+	CredentialsSinkKubernetes CredentialsSinkType = "Kubernetes"
+)
+
+// Edition is the Snowflake edition of an account
+// +kubebuilder:validation:Enum=STANDARD;ENTERPRISE;BUSINESS_CRITICAL
+type Edition string
+
+const (
+	// EditionStandard is the Standard edition
+	EditionStandard Edition = "STANDARD"
+	// EditionEnterprise is the Enterprise edition
+	EditionEnterprise Edition = "ENTERPRISE"
+	// EditionBusinessCritical is the Business Critical edition
+	EditionBusinessCritical Edition = "BUSINESS_CRITICAL"
+)
+
+// RegionSelectionStrategy selects how the region of a new account is chosen
+// +kubebuilder:validation:Enum=fixed;round-robin;random
+type RegionSelectionStrategy string
+
+const (
+	// RegionSelectionFixed uses Spec.Region
+	RegionSelectionFixed RegionSelectionStrategy = "fixed"
+	// RegionSelectionRoundRobin cycles through the regions allowed by the operator
+	RegionSelectionRoundRobin RegionSelectionStrategy = "round-robin"
+	// RegionSelectionRandom picks a random region allowed by the operator
+	RegionSelectionRandom RegionSelectionStrategy = "random"
+)
+
+// AccountNameCharset selects the characters used for generated account names
+// +kubebuilder:validation:Enum=alnum;alpha;upper
+type AccountNameCharset string
+
+const (
+	// AccountNameCharsetAlnum uses uppercase letters and digits
+	AccountNameCharsetAlnum AccountNameCharset = "alnum"
+	// AccountNameCharsetAlpha uses letters only. Snowflake stores unquoted account names in uppercase, so
+	// generated names only use uppercase letters and this is the same as AccountNameCharsetUpper.
+	AccountNameCharsetAlpha AccountNameCharset = "alpha"
+	// AccountNameCharsetUpper uses uppercase letters only
+	AccountNameCharsetUpper AccountNameCharset = "upper"
+)
+
 // SnowflakeAccountSpec defines the desired state of SnowflakeAccount
 type SnowflakeAccountSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -32,10 +124,410 @@ type SnowflakeAccountSpec struct {
 
 	// Duration is the duration after which the account will be automatically deleted
 	// Format: duration string (e.g., "2m", "1h30m")
-	// Default: "2m" (2 minutes)
+	// When unset the account never expires and must be deleted explicitly.
 	// +optional
-	// +kubebuilder:default="2m"
 	Duration string `json:"duration,omitempty"`
+
+	// MaxProvisioningDuration is how long the operator keeps trying to create the account, measured
+	// from the first CREATE ACCOUNT attempt. Once exceeded it gives up with a ProvisioningTimeout Failed
+	// condition until the speck.dataverse.redhat.com/retry-provisioning annotation is changed. Zero retries
+	// forever. Defaults to 6h.
+	// +optional
+	MaxProvisioningDuration *metav1.Duration `json:"maxProvisioningDuration,omitempty"`
+
+	// Tags are Snowflake object tags applied to the account when it is created
+	// Keys must be fully qualified tag names (e.g., "governance.tags.cost_center")
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// DesiredAccountName is the name the Snowflake account should have
+	// If unset, a random name is generated on creation. Changing it after creation
+	// renames the existing account in place.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z][A-Za-z0-9_]*$`
+	DesiredAccountName string `json:"desiredAccountName,omitempty"`
+
+	// Region is the Snowflake region the account is created in (e.g. AWS_US_WEST_2)
+	// Used with the fixed region selection strategy; defaults to the operator's --default-region,
+	// AWS_US_WEST_2 unless configured.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	Region string `json:"region,omitempty"`
+
+	// Edition is the Snowflake edition the account is created with; defaults to the operator's
+	// --default-edition, ENTERPRISE unless configured
+	// +optional
+	Edition Edition `json:"edition,omitempty"`
+
+	// BillingEntity is the consumption billing entity the account's usage is billed to, set with
+	// CONSUMPTION_BILLING_ENTITY when the account is created. It must be one of the entities configured on
+	// the operator with --allowed-billing-entities. Changing it after creation has no effect.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	BillingEntity string `json:"billingEntity,omitempty"`
+
+	// Comment is the comment set on the account when it is created
+	// Snowflake accepts at most 256 characters; a longer comment fails provisioning unless TruncateComment is set.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+
+	// TruncateComment cuts a Comment longer than Snowflake accepts down to 256 characters instead of failing
+	// +optional
+	TruncateComment bool `json:"truncateComment,omitempty"`
+
+	// RegionSelectionStrategy selects how the account's region is chosen. With round-robin or
+	// random, the region is picked from the regions configured on the operator with --allowed-regions.
+	// +optional
+	// +kubebuilder:default=fixed
+	RegionSelectionStrategy RegionSelectionStrategy `json:"regionSelectionStrategy,omitempty"`
+
+	// FallbackRegions are tried in order when Snowflake reports the selected region as unavailable,
+	// for example during maintenance. Without them, creation is retried in the same region.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z0-9_]+$`
+	FallbackRegions []string `json:"fallbackRegions,omitempty"`
+
+	// AccountNameLength is the length of a generated account name, including its "SF" prefix
+	// +optional
+	// +kubebuilder:default=8
+	// +kubebuilder:validation:Minimum=4
+	// +kubebuilder:validation:Maximum=255
+	AccountNameLength int `json:"accountNameLength,omitempty"`
+
+	// AccountNameCharset selects the characters used after the "SF" prefix of a generated account name
+	// +optional
+	// +kubebuilder:default=alnum
+	AccountNameCharset AccountNameCharset `json:"accountNameCharset,omitempty"`
+
+	// DeterministicName derives the account name from a hash of the resource UID instead of generating a
+	// random one, so a create retried after a crash reuses the same name rather than leaving an orphaned
+	// account behind. The name is "SF" followed by 25 base36 characters; AccountNameLength and
+	// AccountNameCharset do not apply. Ignored when DesiredAccountName is set.
+	// +optional
+	DeterministicName bool `json:"deterministicName,omitempty"`
+
+	// OrgCredentialsSecretRef references a secret in the same namespace holding the
+	// organization credentials (SNOWFLAKE_ORG_USERNAME, SNOWFLAKE_ORG_PASSWORD,
+	// SNOWFLAKE_ORG_ACCOUNT and optionally SNOWFLAKE_ORG_ROLE, SNOWFLAKE_ORG_HOST, SNOWFLAKE_ORG_REGION,
+	// SNOWFLAKE_ORG_NAME and SNOWFLAKE_ORG_DSN_PARAMS)
+	// If unset, the operator's environment variables are used.
+	// +optional
+	OrgCredentialsSecretRef *corev1.LocalObjectReference `json:"orgCredentialsSecretRef,omitempty"`
+
+	// CredentialProfile selects a named set of organization credentials configured on the operator
+	// with --credential-profiles. It is ignored when OrgCredentialsSecretRef is set.
+	// +optional
+	CredentialProfile string `json:"credentialProfile,omitempty"`
+
+	// WaitForDNS delays marking the account as created until its hostname resolves in DNS
+	// +optional
+	WaitForDNS bool `json:"waitForDNS,omitempty"`
+
+	// DeletionPolicy controls whether the Snowflake account is dropped when this resource is deleted
+	// With "Retain", only the credentials secret is removed and the account is orphaned: it keeps
+	// running (and billing) in Snowflake and must be dropped manually. Defaults to "Delete" for accounts
+	// the operator creates and to "Retain" for adopted accounts, which existed before the resource.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// GracePeriodInDays is how long a dropped account can still be restored with UNDROP ACCOUNT.
+	// Defaults to 3, the shortest grace period Snowflake accepts, as accounts cannot be dropped
+	// immediately. The operator clamps it to its --min-grace-period-days and --max-grace-period-days.
+	// +optional
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=90
+	GracePeriodInDays int `json:"gracePeriodInDays,omitempty"`
+
+	// Suspended suspends the created Snowflake account, stopping its billing without dropping it, and
+	// resumes it once cleared. The outcome is reported in the Suspended condition. Duration still applies
+	// while the account is suspended, and post-provisioning steps wait until it is resumed.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// AdoptExisting manages the existing account named by ExistingAccountName instead of creating one
+	// The account is then managed like a created one, including Duration and DeletionPolicy, and its
+	// duration counts from the time it was adopted. It is only dropped on deletion with an explicit
+	// "Delete" DeletionPolicy, and the post-provisioning steps are only run with BootstrapAdopted.
+	// +optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// BootstrapAdopted runs the post-provisioning steps requested by the spec, such as PostCreateSQL or
+	// NetworkPolicy, against an adopted account. They are skipped by default, as an adopted account is
+	// already configured.
+	// +optional
+	BootstrapAdopted bool `json:"bootstrapAdopted,omitempty"`
+
+	// ExistingAccountName is the name of the Snowflake account to adopt when AdoptExisting is set
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z][A-Za-z0-9_]*$`
+	ExistingAccountName string `json:"existingAccountName,omitempty"`
+
+	// ExistingAdminName is the admin user of the adopted account, stored in the credentials secret
+	// Together with AdminPasswordSecretRef it gives the operator the admin's credentials.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	ExistingAdminName string `json:"existingAdminName,omitempty"`
+
+	// ResetAdminPassword replaces the password of the adopted account's admin with a generated one,
+	// which is stored in the credentials secret. Requires ExistingAdminName and AdminPasswordSecretRef
+	// holding the current password.
+	// +optional
+	ResetAdminPassword bool `json:"resetAdminPassword,omitempty"`
+
+	// ManageAccount selects whether the operator manages the Snowflake account itself. When false, the
+	// account named by ExistingAccountName was created outside the operator: it is never created, changed
+	// or dropped and no SQL is run against it. The operator only maintains the credentials secret, from
+	// ExistingAdminName and AdminPasswordSecretRef, and the status, reporting an expired Duration in the
+	// Expired condition instead of deleting the resource. Cannot be changed once the account exists.
+	// +optional
+	// +kubebuilder:default=true
+	ManageAccount *bool `json:"manageAccount,omitempty"`
+
+	// AdminPasswordSecretRef selects a key of a secret in the same namespace holding the admin password
+	// When set, the password is used instead of a generated one and is not copied into the credentials secret.
+	// +optional
+	AdminPasswordSecretRef *corev1.SecretKeySelector `json:"adminPasswordSecretRef,omitempty"`
+
+	// AutoCompletePasswordChange logs in to the account after provisioning and replaces the initial
+	// admin password, which must be changed on first login, with a new one stored in the credentials
+	// secret. The outcome is reported in the PasswordChanged condition. Requires a generated password:
+	// AdminPasswordSecretRef must be unset. Until the initial password has been changed, the post-provisioning
+	// steps that log in as the admin, such as PostCreateSQL or NetworkPolicy, are skipped.
+	// +optional
+	AutoCompletePasswordChange bool `json:"autoCompletePasswordChange,omitempty"`
+
+	// VerifyLogin logs in to the account after provisioning with the admin credentials from the
+	// credentials secret and runs SELECT CURRENT_ACCOUNT(), reporting the outcome in the Verified
+	// condition. An admin that must still change the initial password cannot run queries, so the
+	// check is skipped while the PasswordChangePending condition is true; enable
+	// AutoCompletePasswordChange to verify the login right after provisioning.
+	// +optional
+	VerifyLogin bool `json:"verifyLogin,omitempty"`
+
+	// AdminPublicKey is an RSA public key (PEM or base64) set on the admin user for key-pair authentication
+	// When set, no password is generated; the admin only gets a password if AdminPasswordSecretRef is also set.
+	// The key, not a password, is stored in the credentials secret.
+	// +optional
+	AdminPublicKey string `json:"adminPublicKey,omitempty"`
+
+	// AdminDefaultRole is set as the admin user's default role after provisioning, so tooling that relies
+	// on the session's role works on first login. The outcome is reported in the AdminDefaultsApplied condition.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	AdminDefaultRole string `json:"adminDefaultRole,omitempty"`
+
+	// AdminDefaultWarehouse is set as the admin user's default warehouse after provisioning. The warehouse
+	// must exist in the account, e.g. created by PostCreateSQL; until it does, the AdminDefaultsApplied
+	// condition reports the failure and the change is retried.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	AdminDefaultWarehouse string `json:"adminDefaultWarehouse,omitempty"`
+
+	// Timezone is set as the account's TIMEZONE parameter after provisioning, e.g. "Europe/Berlin". It must
+	// be a name from the tz database. Snowflake's default, America/Los_Angeles, is kept when unset. The
+	// outcome is reported in the AccountParametersApplied condition.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	Timezone string `json:"timezone,omitempty"`
+
+	// DefaultCollation is set after provisioning as the account's DEFAULT_DDL_COLLATION parameter, the collation
+	// that orders and compares the text columns of new tables: a locale optionally followed by specifiers, e.g.
+	// "de" or "en-ci". The binary default is kept when unset. The outcome is reported in the
+	// AccountParametersApplied condition.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z]{2,3}(_[A-Z]{2})?|utf8)(-[a-z]+)*$`
+	// +kubebuilder:validation:MaxLength=64
+	DefaultCollation string `json:"defaultCollation,omitempty"`
+
+	// CredentialsSink selects where the account details, including the admin credentials, are stored after
+	// provisioning. Kubernetes, the only sink built in, stores them in the credentials secret.
+	// +optional
+	// +kubebuilder:default=Kubernetes
+	CredentialsSink CredentialsSinkType `json:"credentialsSink,omitempty"`
+
+	// SecretNamespace is the namespace the credentials secret is created in
+	// If unset, the secret is created in the namespace of this resource. Another namespace must be listed
+	// in the operator's --allowed-secret-namespaces. A secret in another namespace cannot be owned by this
+	// resource, so the operator deletes it on finalization.
+	// +optional
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+
+	// SecretType is the type of the credentials secret
+	// A kubernetes.io/basic-auth secret also holds the admin credentials under the username and password
+	// keys, so it requires a generated password: AdminPublicKey and AdminPasswordSecretRef must be unset.
+	// +optional
+	// +kubebuilder:default=Opaque
+	SecretType CredentialsSecretType `json:"secretType,omitempty"`
+
+	// ImmutableSecret marks the credentials secret immutable so it cannot be edited accidentally
+	// The operator replaces an immutable secret whenever it has to change its data, e.g. after a rename or
+	// a password change: the secret is deleted and recreated, so consumers see a new UID and must not hold
+	// on to a stale copy.
+	// +optional
+	ImmutableSecret bool `json:"immutableSecret,omitempty"`
+
+	// SplitCredentials keeps only the admin credentials and the account name in the credentials secret,
+	// and writes the non-sensitive connection details (accountURL, region, edition, email, accountLocator)
+	// to a {accountName}-conninfo ConfigMap next to it, so they can be shared more broadly than the secret
+	// +optional
+	SplitCredentials bool `json:"splitCredentials,omitempty"`
+
+	// SecretLabels are added to the credentials secret's labels
+	// Labels managed by the operator take precedence and cannot be overridden.
+	// +optional
+	SecretLabels map[string]string `json:"secretLabels,omitempty"`
+
+	// SecretAnnotations are added to the credentials secret's annotations
+	// +optional
+	SecretAnnotations map[string]string `json:"secretAnnotations,omitempty"`
+
+	// AuthenticationPolicy is created in the account after provisioning and assigned to the admin user
+	// A failure to apply it is reported in the AuthenticationPolicyApplied condition and does not
+	// fail the account creation.
+	// +optional
+	AuthenticationPolicy *AuthenticationPolicy `json:"authenticationPolicy,omitempty"`
+
+	// NetworkPolicy is created in the account after provisioning and set as the account's network policy
+	// A failure to apply it is reported in the NetworkPolicyApplied condition and does not fail the
+	// account creation. The operator's own egress IPs must be allowed for later changes to succeed.
+	// +optional
+	NetworkPolicy *NetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// ConsumerAccount sets the account up after provisioning to consume a data share
+	// A failure to mount the share is reported in the ShareMounted condition and does not fail the
+	// account creation. The provider must have added the account to the share.
+	// +optional
+	ConsumerAccount *ConsumerAccount `json:"consumerAccount,omitempty"`
+
+	// GrantOrgAdmin enables the ORGADMIN role in the account after provisioning (ALTER ACCOUNT ...
+	// SET IS_ORG_ADMIN = TRUE) and grants it to the admin user, so the account can itself create and drop
+	// accounts of the organization. WARNING: this hands organization-wide privileges to anyone holding the
+	// admin credentials, including over accounts the operator manages; only enable it for trusted users.
+	// Requires the operator to run with --allow-org-admin-grants. The outcome is reported in the
+	// OrgAdminGranted condition. Clearing the field does not revoke the role.
+	// +optional
+	GrantOrgAdmin bool `json:"grantOrgAdmin,omitempty"`
+
+	// MonitoringUser is created in the account after provisioning as a read-only user for collecting usage
+	// and billing data, with a generated password stored in the {accountName}-monitoring secret next to the
+	// credentials secret. A failure to create it is reported in the MonitoringUserCreated condition and does
+	// not fail the account creation. Clearing the field does not drop the user.
+	// +optional
+	MonitoringUser *MonitoringUser `json:"monitoringUser,omitempty"`
+
+	// PostCreateSQL are statements run in order in the account as its admin after provisioning, e.g.
+	// to create roles and grants. Execution stops at the first failing statement, which is reported in
+	// the PostCreateSQLApplied condition without failing the account creation. The statements are run
+	// again after every spec change, so they should be idempotent (CREATE ... IF NOT EXISTS, GRANT).
+	// +optional
+	PostCreateSQL []string `json:"postCreateSQL,omitempty"`
+
+	// PreDeleteSQL are statements run in order in the account as its admin right before it is dropped,
+	// e.g. to revoke grants or drop outbound shares that would otherwise be left dangling. A failing
+	// statement is logged and the rest still run; the account is dropped regardless. The statements run
+	// again if the drop is retried, so they should be idempotent (DROP ... IF EXISTS, REVOKE).
+	// +optional
+	PreDeleteSQL []string `json:"preDeleteSQL,omitempty"`
+
+	// TrackCredits periodically sums the credits used by the account, from its
+	// SNOWFLAKE.ACCOUNT_USAGE.METERING_HISTORY view queried as the admin, into Status.CreditsUsed. The view
+	// lags by up to 3 hours, so the figure is an estimate. Disabled unless the operator is run with a
+	// --credit-sync-interval.
+	// +optional
+	TrackCredits bool `json:"trackCredits,omitempty"`
+}
+
+// ConsumerAccount describes a share the account consumes by mounting it as a database
+type ConsumerAccount struct {
+	// Share is the share to consume, as <provider_account>.<share_name> or
+	// <organization>.<provider_account>.<share_name>
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*){1,2}$`
+	Share string `json:"share"`
+
+	// Database is the name of the database created from the share; defaults to the share name
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Database string `json:"database,omitempty"`
+}
+
+// MonitoringUser describes a read-only user for monitoring the account
+type MonitoringUser struct {
+	// Name is the name of the user
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// Role is the role created for the user, granted MONITOR USAGE on the account and IMPORTED
+	// PRIVILEGES on the SNOWFLAKE database; defaults to MONITORING
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Role string `json:"role,omitempty"`
+}
+
+// NetworkPolicy describes a Snowflake network policy restricting the IPs that can log in to the account
+type NetworkPolicy struct {
+	// Name is the name of the network policy
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// AllowedIPList is the list of IPv4 addresses or CIDR blocks allowed to log in
+	// +kubebuilder:validation:MinItems=1
+	AllowedIPList []string `json:"allowedIPList"`
+
+	// BlockedIPList is the list of IPv4 addresses or CIDR blocks denied access, taking precedence over AllowedIPList
+	// +optional
+	BlockedIPList []string `json:"blockedIPList,omitempty"`
+}
+
+// AuthenticationPolicy describes a Snowflake authentication policy for the account's admin user
+type AuthenticationPolicy struct {
+	// Name is the name of the authentication policy
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// Database is the database the policy is created in; it is created if it does not exist
+	// +optional
+	// +kubebuilder:default=SECURITY
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	Database string `json:"database,omitempty"`
+
+	// Schema is the schema the policy is created in; it is created if it does not exist
+	// +optional
+	// +kubebuilder:default=POLICIES
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_$]*$`
+	Schema string `json:"schema,omitempty"`
+
+	// MFAEnrollment controls whether users must enroll in multi-factor authentication
+	// +optional
+	// +kubebuilder:validation:Enum=REQUIRED;OPTIONAL
+	MFAEnrollment string `json:"mfaEnrollment,omitempty"`
+
+	// AuthenticationMethods restricts the authentication methods users can log in with
+	// +optional
+	// +kubebuilder:validation:items:Enum=ALL;SAML;PASSWORD;OAUTH;KEYPAIR
+	AuthenticationMethods []string `json:"authenticationMethods,omitempty"`
+}
+
+// CredentialsSecretReference identifies a version of the credentials secret
+type CredentialsSecretReference struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the secret
+	Namespace string `json:"namespace"`
+
+	// ResourceVersion is the resourceVersion of the secret after the operator last wrote it
+	// +optional
+	ResourceVersion string `json:"resourceVersion,omitempty"`
 }
 
 // SnowflakeAccountStatus defines the observed state of SnowflakeAccount.
@@ -64,10 +556,54 @@ type SnowflakeAccountStatus struct {
 	// +optional
 	AccountCreated bool `json:"accountCreated,omitempty"`
 
+	// Unmanaged is set once the operator stored the credentials of an account created outside of it, with
+	// Spec.ManageAccount false. The operator never drops or manages such an account, even if
+	// Spec.ManageAccount is changed later.
+	// +optional
+	Unmanaged bool `json:"unmanaged,omitempty"`
+
+	// AccountName is the name of the created Snowflake account
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// AccountLocator is Snowflake's locator for the account, as shown in the ACCOUNT_USAGE views and billing
+	// +optional
+	AccountLocator string `json:"accountLocator,omitempty"`
+
+	// AdminName is the admin user of the account, recorded so a deleted credentials secret can be recreated
+	// +optional
+	AdminName string `json:"adminName,omitempty"`
+
+	// Region is the Snowflake region the account was created in
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// BillingEntity is the consumption billing entity the account was created with
+	// +optional
+	BillingEntity string `json:"billingEntity,omitempty"`
+
+	// Phase summarizes where the account is in its lifecycle
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// SharedDatabase is the database mounted from Spec.ConsumerAccount's share
+	// +optional
+	SharedDatabase string `json:"sharedDatabase,omitempty"`
+
+	// SnowflakeAccountName is the name of the account in Snowflake, recorded as soon as
+	// CREATE ACCOUNT succeeds so the account can be dropped even if later steps fail
+	// +optional
+	SnowflakeAccountName string `json:"snowflakeAccountName,omitempty"`
+
 	// AccountURL is the URL of the created Snowflake account
 	// +optional
 	AccountURL string `json:"accountURL,omitempty"`
 
+	// CredentialsSecret is the credentials secret as last written by the operator. Its resourceVersion
+	// changes whenever the operator updates the credentials, e.g. after a password change.
+	// +optional
+	CredentialsSecret *CredentialsSecretReference `json:"credentialsSecret,omitempty"`
+
 	// Message provides additional information about the current state
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -76,12 +612,76 @@ type SnowflakeAccountStatus struct {
 	// This is used to track duration for automatic deletion
 	// +optional
 	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+
+	// ExpiresAt is when the account's duration expires and it is deleted, CreationTime plus Spec.Duration.
+	// It is unset while the account never expires, without a valid duration.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// ProvisioningDuration is how long Snowflake took to provision the account,
+	// measured from the start of the create until the account became active
+	// +optional
+	ProvisioningDuration *metav1.Duration `json:"provisioningDuration,omitempty"`
+
+	// Tags are the Snowflake object tags applied to the account
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// OrgAccount is the Snowflake organization account the account was created under
+	// +optional
+	OrgAccount string `json:"orgAccount,omitempty"`
+
+	// OrgRole is the role used in the organization account to create the account
+	// +optional
+	OrgRole string `json:"orgRole,omitempty"`
+
+	// RecoverableUntil is when the grace period of the dropped Snowflake account ends; until then
+	// the account can be restored with UNDROP ACCOUNT
+	// +optional
+	RecoverableUntil *metav1.Time `json:"recoverableUntil,omitempty"`
+
+	// FailureCount is the number of consecutive failures to create or drop the Snowflake account.
+	// It is reset once the operation succeeds and delays the next attempt increasingly.
+	// +optional
+	FailureCount int `json:"failureCount,omitempty"`
+
+	// LastFailureTime is when the latest consecutive failure counted in FailureCount happened
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// ProvisioningStartTime is when the operator first tried to create the account; Spec.MaxProvisioningDuration
+	// is measured from it
+	// +optional
+	ProvisioningStartTime *metav1.Time `json:"provisioningStartTime,omitempty"`
+
+	// ProvisioningRetry is the value of the retry-provisioning annotation that last restarted provisioning
+	// +optional
+	ProvisioningRetry string `json:"provisioningRetry,omitempty"`
+
+	// UnavailableRegions are the regions Snowflake reported as unavailable while creating the account.
+	// They are skipped in favor of Spec.FallbackRegions and cleared once the account is created.
+	// +optional
+	UnavailableRegions []string `json:"unavailableRegions,omitempty"`
+
+	// CreditsUsed is the estimated number of credits the account has used since it was created, as of
+	// LastCostSync. Usage of the last 3 hours before the sync may be missing.
+	// +optional
+	CreditsUsed string `json:"creditsUsed,omitempty"`
+
+	// LastCostSync is when CreditsUsed was last synced from the account's usage views
+	// +optional
+	LastCostSync *metav1.Time `json:"lastCostSync,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=sfa;sfacct,categories=all
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.accountCreated",description="Whether the account has been created"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The lifecycle phase of the account"
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.accountURL",description="The URL of the created account"
+// +kubebuilder:printcolumn:name="Duration",type="string",JSONPath=".spec.duration",description="How long the account lives before it is deleted"
+// +kubebuilder:printcolumn:name="Expires",type="string",JSONPath=".status.expiresAt",description="When the duration of the account expires"
+// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.accountCreated",description="Whether the account has been created",priority=1
+// +kubebuilder:printcolumn:name="Credits",type="string",JSONPath=".status.creditsUsed",description="The estimated credits used by the account",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // SnowflakeAccount is the Schema for the snowflakeaccounts API
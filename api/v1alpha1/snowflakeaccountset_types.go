@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnowflakeAccountSetSpec defines the desired state of SnowflakeAccountSet
+type SnowflakeAccountSetSpec struct {
+	// Replicas is the number of accounts provisioned from Template. Each is a SnowflakeAccount named
+	// {set name}-{index}, owned by the set; lowering it deletes the accounts with the highest indexes.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Replicas int32 `json:"replicas"`
+
+	// Template is the spec of every SnowflakeAccount of the set. Each account gets its own generated name
+	// and credentials secret, so the template must not name an account. It must not set a duration either:
+	// the set would recreate an expired account right away. Changes only apply to accounts created afterwards.
+	Template SnowflakeAccountSpec `json:"template"`
+}
+
+// SnowflakeAccountSetMember is a SnowflakeAccount of a set as reported in its status
+type SnowflakeAccountSetMember struct {
+	// Name is the name of the SnowflakeAccount resource
+	Name string `json:"name"`
+
+	// AccountName is the name of the Snowflake account, once created
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// Phase is the lifecycle phase of the account
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+}
+
+// SnowflakeAccountSetStatus defines the observed state of SnowflakeAccountSet
+type SnowflakeAccountSetStatus struct {
+	// Replicas is the number of SnowflakeAccounts of the set
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of SnowflakeAccounts of the set in the Ready phase
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Accounts are the SnowflakeAccounts of the set, ordered by name
+	// +optional
+	Accounts []SnowflakeAccountSetMember `json:"accounts,omitempty"`
+
+	// conditions represent the current state of the SnowflakeAccountSet resource.
+	// The Ready condition is true once every account of the set is ready.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=sfas,categories=all
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="The number of accounts requested"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description="The number of ready accounts"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SnowflakeAccountSet provisions a number of identical SnowflakeAccounts from one spec
+type SnowflakeAccountSet struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of SnowflakeAccountSet
+	// +required
+	Spec SnowflakeAccountSetSpec `json:"spec"`
+
+	// status defines the observed state of SnowflakeAccountSet
+	// +optional
+	Status SnowflakeAccountSetStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnowflakeAccountSetList contains a list of SnowflakeAccountSet
+type SnowflakeAccountSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []SnowflakeAccountSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnowflakeAccountSet{}, &SnowflakeAccountSetList{})
+}
@@ -21,16 +21,107 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthenticationPolicy) DeepCopyInto(out *AuthenticationPolicy) {
+	*out = *in
+	if in.AuthenticationMethods != nil {
+		in, out := &in.AuthenticationMethods, &out.AuthenticationMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthenticationPolicy.
+func (in *AuthenticationPolicy) DeepCopy() *AuthenticationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthenticationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerAccount) DeepCopyInto(out *ConsumerAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerAccount.
+func (in *ConsumerAccount) DeepCopy() *ConsumerAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsSecretReference) DeepCopyInto(out *CredentialsSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsSecretReference.
+func (in *CredentialsSecretReference) DeepCopy() *CredentialsSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringUser) DeepCopyInto(out *MonitoringUser) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringUser.
+func (in *MonitoringUser) DeepCopy() *MonitoringUser {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	if in.AllowedIPList != nil {
+		in, out := &in.AllowedIPList, &out.AllowedIPList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockedIPList != nil {
+		in, out := &in.BlockedIPList, &out.BlockedIPList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnowflakeAccount) DeepCopyInto(out *SnowflakeAccount) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -84,9 +175,202 @@ func (in *SnowflakeAccountList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeAccountSet) DeepCopyInto(out *SnowflakeAccountSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSet.
+func (in *SnowflakeAccountSet) DeepCopy() *SnowflakeAccountSet {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeAccountSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnowflakeAccountSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeAccountSetList) DeepCopyInto(out *SnowflakeAccountSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SnowflakeAccountSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSetList.
+func (in *SnowflakeAccountSetList) DeepCopy() *SnowflakeAccountSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeAccountSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnowflakeAccountSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeAccountSetMember) DeepCopyInto(out *SnowflakeAccountSetMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSetMember.
+func (in *SnowflakeAccountSetMember) DeepCopy() *SnowflakeAccountSetMember {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeAccountSetMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeAccountSetSpec) DeepCopyInto(out *SnowflakeAccountSetSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSetSpec.
+func (in *SnowflakeAccountSetSpec) DeepCopy() *SnowflakeAccountSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeAccountSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeAccountSetStatus) DeepCopyInto(out *SnowflakeAccountSetStatus) {
+	*out = *in
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]SnowflakeAccountSetMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSetStatus.
+func (in *SnowflakeAccountSetStatus) DeepCopy() *SnowflakeAccountSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeAccountSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SnowflakeAccountSpec) DeepCopyInto(out *SnowflakeAccountSpec) {
 	*out = *in
+	if in.MaxProvisioningDuration != nil {
+		in, out := &in.MaxProvisioningDuration, &out.MaxProvisioningDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FallbackRegions != nil {
+		in, out := &in.FallbackRegions, &out.FallbackRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrgCredentialsSecretRef != nil {
+		in, out := &in.OrgCredentialsSecretRef, &out.OrgCredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ManageAccount != nil {
+		in, out := &in.ManageAccount, &out.ManageAccount
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdminPasswordSecretRef != nil {
+		in, out := &in.AdminPasswordSecretRef, &out.AdminPasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretLabels != nil {
+		in, out := &in.SecretLabels, &out.SecretLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretAnnotations != nil {
+		in, out := &in.SecretAnnotations, &out.SecretAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AuthenticationPolicy != nil {
+		in, out := &in.AuthenticationPolicy, &out.AuthenticationPolicy
+		*out = new(AuthenticationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsumerAccount != nil {
+		in, out := &in.ConsumerAccount, &out.ConsumerAccount
+		*out = new(ConsumerAccount)
+		**out = **in
+	}
+	if in.MonitoringUser != nil {
+		in, out := &in.MonitoringUser, &out.MonitoringUser
+		*out = new(MonitoringUser)
+		**out = **in
+	}
+	if in.PostCreateSQL != nil {
+		in, out := &in.PostCreateSQL, &out.PostCreateSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreDeleteSQL != nil {
+		in, out := &in.PreDeleteSQL, &out.PreDeleteSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountSpec.
@@ -109,10 +393,52 @@ func (in *SnowflakeAccountStatus) DeepCopyInto(out *SnowflakeAccountStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(CredentialsSecretReference)
+		**out = **in
+	}
 	if in.CreationTime != nil {
 		in, out := &in.CreationTime, &out.CreationTime
 		*out = (*in).DeepCopy()
 	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ProvisioningDuration != nil {
+		in, out := &in.ProvisioningDuration, &out.ProvisioningDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RecoverableUntil != nil {
+		in, out := &in.RecoverableUntil, &out.RecoverableUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ProvisioningStartTime != nil {
+		in, out := &in.ProvisioningStartTime, &out.ProvisioningStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UnavailableRegions != nil {
+		in, out := &in.UnavailableRegions, &out.UnavailableRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCostSync != nil {
+		in, out := &in.LastCostSync, &out.LastCostSync
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeAccountStatus.
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,12 +39,16 @@ import (
 
 	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
 	"github.com/redhat-data-and-ai/speck/internal/controller"
+	webhookoperatorv1alpha1 "github.com/redhat-data-and-ai/speck/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// version is the operator version, set at build time with -ldflags "-X main.version=..."
+	version = "dev"
 )
 
 func init() {
@@ -62,6 +67,38 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var maxRequeueInterval time.Duration
+	var requeueJitter float64
+	var maxAccountDuration time.Duration
+	var credentialProfiles string
+	var discoveryLabelKeys string
+	var maxAccountsPerNamespace int
+	var allowedRegions string
+	var defaultEdition string
+	var defaultRegion string
+	var expirySweepInterval time.Duration
+	var orphanAuditInterval time.Duration
+	var connectivityCheckInterval time.Duration
+	var connectivityCheckTimeout time.Duration
+	var connectivityReadiness bool
+	var snowflakeMaxOpenConns int
+	var snowflakeMaxIdleConns int
+	var snowflakeConnMaxLifetime time.Duration
+	var passwordChangeCheckInterval time.Duration
+	var creditSyncInterval time.Duration
+	var disableAccountDeletion bool
+	var minGracePeriodDays int
+	var maxGracePeriodDays int
+	var maxFinalizeAttempts int
+	var finalizerName string
+	var allowOrgAdminGrants bool
+	var applicationName string
+	var kubernetesTagSchema string
+	var allowedBillingEntities string
+	var allowedSecretNamespaces string
+	var billingEntityTag string
+	var createAccountTemplate string
+	var nameCollisionRetries int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -80,6 +117,99 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&maxRequeueInterval, "max-requeue-interval", 5*time.Minute,
+		"The maximum time to wait before re-checking a created account's duration. Set to 0 to disable the cap.")
+	flag.Float64Var(&requeueJitter, "requeue-jitter", 0.1,
+		"The largest fraction by which a duration re-check is randomly delayed, so that accounts created together "+
+			"do not all re-check at once. Set to 0 to disable the jitter.")
+	flag.DurationVar(&maxAccountDuration, "max-account-duration", 365*24*time.Hour,
+		"The longest account duration accepted. Accounts with longer durations are not deleted automatically. "+
+			"Set to 0 to disable the ceiling.")
+	flag.StringVar(&credentialProfiles, "credential-profiles", "",
+		"Comma-separated named organization credential profiles as name=namespace/secret, "+
+			"selected by a SnowflakeAccount's spec.credentialProfile.")
+	flag.StringVar(&discoveryLabelKeys, "discovery-label-keys", "",
+		"Comma-separated label keys of a SnowflakeAccount that are copied onto its credentials secret and must "+
+			"match when looking up a secret not yet recorded in the status, so resources sharing a name can be told apart.")
+	flag.IntVar(&maxAccountsPerNamespace, "max-accounts-per-namespace", 0,
+		"The maximum number of created Snowflake accounts per namespace. Set to 0 for no limit.")
+	flag.StringVar(&allowedRegions, "allowed-regions", "",
+		"Comma-separated Snowflake regions used by the round-robin and random region selection strategies.")
+	flag.StringVar(&defaultEdition, "default-edition", string(operatorv1alpha1.DefaultEdition),
+		"The Snowflake edition of accounts that do not set spec.edition.")
+	flag.StringVar(&defaultRegion, "default-region", operatorv1alpha1.DefaultRegion,
+		"The Snowflake region of accounts that do not set spec.region and use the fixed region selection strategy.")
+	flag.DurationVar(&expirySweepInterval, "expiry-sweep-interval", 10*time.Minute,
+		"How often all accounts are checked for expiry, so durations are enforced even when a scheduled "+
+			"re-check was lost to a restart. Set to 0 to disable the sweep.")
+	flag.DurationVar(&orphanAuditInterval, "orphan-audit-interval", time.Hour,
+		"How often the organizations' accounts are checked for accounts created by the operator that no "+
+			"SnowflakeAccount owns; they are logged and counted in the speck_orphaned_accounts metric. "+
+			"Accounts are recognized by their K8S_UID tag, so the audit only runs with --kubernetes-tag-schema. "+
+			"Set to 0 to disable the audit.")
+	flag.DurationVar(&connectivityCheckInterval, "connectivity-check-interval", time.Minute,
+		"How often the connection to the Snowflake organization from the operator's environment is checked. "+
+			"The outcome is reported in the speck_snowflake_reachable metric. Set to 0 to disable the check.")
+	flag.DurationVar(&connectivityCheckTimeout, "connectivity-check-timeout", 10*time.Second,
+		"The time a single Snowflake connectivity check may take. Set to 0 for no timeout.")
+	flag.BoolVar(&connectivityReadiness, "connectivity-readiness", false,
+		"If set, the pod reports not ready until a connectivity check has succeeded and while the latest one failed. "+
+			"Requires --connectivity-check-interval.")
+	flag.IntVar(&snowflakeMaxOpenConns, "snowflake-max-open-conns", 10,
+		"The most connections, each a Snowflake session, open at once per set of Snowflake credentials. "+
+			"Statements beyond it wait for a free connection. Set to 0 for no limit.")
+	flag.IntVar(&snowflakeMaxIdleConns, "snowflake-max-idle-conns", 2,
+		"The most idle connections kept per set of Snowflake credentials for reuse. "+
+			"Set to 0 to keep the database/sql default of 2.")
+	flag.DurationVar(&snowflakeConnMaxLifetime, "snowflake-conn-max-lifetime", time.Hour,
+		"How long a Snowflake connection is reused before it is closed and reopened. "+
+			"Set to 0 to reuse connections indefinitely.")
+	flag.DurationVar(&passwordChangeCheckInterval, "password-change-check-interval", 0,
+		"How often to log in to accounts whose admin must still change the initial password, to detect that it "+
+			"was changed. Set to 0 to disable the check and the logins it makes.")
+	flag.DurationVar(&creditSyncInterval, "credit-sync-interval", 0,
+		"How often to sync the credits used by accounts with spec.trackCredits from their ACCOUNT_USAGE views, "+
+			"which lag by up to 3 hours. Set to 0 to disable credit tracking and the logins it makes.")
+	flag.BoolVar(&disableAccountDeletion, "disable-account-deletion", false,
+		"If set, the operator never drops Snowflake accounts: deleted resources leave their account behind "+
+			"and expired durations are only reported.")
+	flag.IntVar(&minGracePeriodDays, "min-grace-period-days", 0,
+		"The shortest grace period, in days, Snowflake accounts are dropped with, which also rules out immediate drops. "+
+			"Set to 0 for no minimum.")
+	flag.IntVar(&maxGracePeriodDays, "max-grace-period-days", 0,
+		"The longest grace period, in days, Snowflake accounts are dropped with, capping spec.gracePeriodInDays. "+
+			"Set to 0 for no maximum.")
+	flag.IntVar(&maxFinalizeAttempts, "max-finalize-attempts", 5,
+		"After how many consecutive failures to finalize a deleted SnowflakeAccount it gets the FinalizeFailed "+
+			"condition and is retried on a slower cadence. Set to 0 to never give up.")
+	flag.BoolVar(&allowOrgAdminGrants, "allow-org-admin-grants", false,
+		"If set, SnowflakeAccounts may set spec.grantOrgAdmin to have ORGADMIN granted to the admin of their account, "+
+			"which can then manage every account of the organization.")
+	flag.StringVar(&finalizerName, "finalizer-name", controller.DefaultFinalizerName,
+		"The finalizer added to SnowflakeAccounts. Give each operator instance its own finalizer when several "+
+			"run against the same cluster, e.g. during a migration: each instance manages the resources it added its "+
+			"finalizer to and skips those carrying another instance's finalizer.")
+	flag.StringVar(&applicationName, "snowflake-application", "speck-operator/"+version,
+		"The application name the operator's Snowflake connections report, shown in "+
+			"QUERY_HISTORY.CLIENT_APPLICATION_ID.")
+	flag.StringVar(&createAccountTemplate, "create-account-template", "",
+		"Path to a Go text/template rendering the CREATE ACCOUNT statement, typically mounted from a ConfigMap. "+
+			"Leave empty to use the built-in statement.")
+	flag.StringVar(&kubernetesTagSchema, "kubernetes-tag-schema", "",
+		"The database.schema of the K8S_NAMESPACE, K8S_NAME and K8S_UID tags applied to new accounts to identify "+
+			"the owning SnowflakeAccount. The tags must already exist in the organization account. Leave empty to disable.")
+	flag.StringVar(&allowedBillingEntities, "allowed-billing-entities", "",
+		"Comma-separated consumption billing entities accounts may be created with through spec.billingEntity. "+
+			"Accounts naming any other entity are rejected. Leave empty to reject every billing entity.")
+	flag.StringVar(&allowedSecretNamespaces, "allowed-secret-namespaces", "",
+		"Comma-separated namespaces a SnowflakeAccount may write its secrets to through spec.secretNamespace, "+
+			"besides its own. Leave empty to keep every secret in the namespace of its resource.")
+	flag.StringVar(&billingEntityTag, "billing-entity-tag", "",
+		"The database.schema.tag_name of a tag set to the billing entity of accounts created with one. "+
+			"The tag must already exist in the organization account. Leave empty to disable.")
+	flag.IntVar(&nameCollisionRetries, "name-collision-retries", 3,
+		"How many times CREATE ACCOUNT is retried right away with a new generated name when the name is already taken. "+
+			"Other failures are retried with a backoff.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -179,14 +309,146 @@ func main() {
 		os.Exit(1)
 	}
 
+	profiles, err := controller.ParseCredentialProfiles(credentialProfiles)
+	if err != nil {
+		setupLog.Error(err, "invalid --credential-profiles")
+		os.Exit(1)
+	}
+
+	labelKeys, err := controller.ParseDiscoveryLabelKeys(discoveryLabelKeys)
+	if err != nil {
+		setupLog.Error(err, "invalid --discovery-label-keys")
+		os.Exit(1)
+	}
+
+	secretNamespaces, err := controller.ParseSecretNamespaces(allowedSecretNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid --allowed-secret-namespaces")
+		os.Exit(1)
+	}
+
+	edition, err := controller.ParseDefaultEdition(defaultEdition)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-edition")
+		os.Exit(1)
+	}
+
+	region, err := controller.ParseDefaultRegion(defaultRegion)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-region")
+		os.Exit(1)
+	}
+
+	createAccountTmpl, err := controller.LoadCreateAccountTemplate(createAccountTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid --create-account-template")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateRequeueJitter(requeueJitter); err != nil {
+		setupLog.Error(err, "invalid --requeue-jitter")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateConnectionPool(snowflakeMaxOpenConns, snowflakeMaxIdleConns, snowflakeConnMaxLifetime); err != nil {
+		setupLog.Error(err, "invalid --snowflake-max-open-conns, --snowflake-max-idle-conns or --snowflake-conn-max-lifetime")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateBillingEntityTag(billingEntityTag); err != nil {
+		setupLog.Error(err, "invalid --billing-entity-tag")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateFinalizerName(finalizerName); err != nil {
+		setupLog.Error(err, "invalid --finalizer-name")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateGracePeriodBounds(minGracePeriodDays, maxGracePeriodDays); err != nil {
+		setupLog.Error(err, "invalid --min-grace-period-days or --max-grace-period-days")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateConnectivityReadiness(connectivityReadiness, connectivityCheckInterval); err != nil {
+		setupLog.Error(err, "invalid --connectivity-readiness")
+		os.Exit(1)
+	}
+
+	if disableAccountDeletion {
+		setupLog.Info("Account deletion is disabled: Snowflake accounts will never be dropped by the operator")
+	}
+	if orphanAuditInterval > 0 && kubernetesTagSchema == "" {
+		setupLog.Info("The orphan audit is disabled: it requires --kubernetes-tag-schema to recognize operator-created accounts")
+	}
+
 	if err := (&controller.SnowflakeAccountReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 		Clock:  clock.RealClock{},
+
+		Recorder: mgr.GetEventRecorderFor("snowflakeaccount-controller"),
+
+		MaxRequeueInterval:  maxRequeueInterval,
+		RequeueJitter:       requeueJitter,
+		MaxAccountDuration:  maxAccountDuration,
+		ExpirySweepInterval: expirySweepInterval,
+		OrphanAuditInterval: orphanAuditInterval,
+		CredentialProfiles:  profiles,
+		DiscoveryLabelKeys:  labelKeys,
+
+		ConnectivityCheckInterval: connectivityCheckInterval,
+		ConnectivityCheckTimeout:  connectivityCheckTimeout,
+		ConnectivityReadiness:     connectivityReadiness,
+
+		SnowflakeMaxOpenConns:    snowflakeMaxOpenConns,
+		SnowflakeMaxIdleConns:    snowflakeMaxIdleConns,
+		SnowflakeConnMaxLifetime: snowflakeConnMaxLifetime,
+
+		PasswordChangeCheckInterval: passwordChangeCheckInterval,
+		CreditSyncInterval:          creditSyncInterval,
+
+		DisableAccountDeletion: disableAccountDeletion,
+		AllowOrgAdminGrants:    allowOrgAdminGrants,
+		MinGracePeriodDays:     minGracePeriodDays,
+		MaxGracePeriodDays:     maxGracePeriodDays,
+		MaxFinalizeAttempts:    maxFinalizeAttempts,
+		FinalizerName:          finalizerName,
+		ApplicationName:        applicationName,
+
+		MaxAccountsPerNamespace: maxAccountsPerNamespace,
+		AllowedRegions:          controller.ParseRegions(allowedRegions),
+		DefaultEdition:          edition,
+		DefaultRegion:           region,
+		KubernetesTagSchema:     kubernetesTagSchema,
+		AllowedBillingEntities:  controller.ParseBillingEntities(allowedBillingEntities),
+		AllowedSecretNamespaces: secretNamespaces,
+		BillingEntityTag:        billingEntityTag,
+		CreateAccountTemplate:   createAccountTmpl,
+		NameCollisionRetries:    nameCollisionRetries,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SnowflakeAccount")
 		os.Exit(1)
 	}
+	if err := (&controller.SnowflakeAccountSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SnowflakeAccountSet")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookoperatorv1alpha1.SetupSnowflakeAccountWebhookWithManager(mgr, &webhookoperatorv1alpha1.SnowflakeAccountCustomDefaulter{
+			DefaultEdition: edition,
+			DefaultRegion:  region,
+		}, &webhookoperatorv1alpha1.SnowflakeAccountCustomValidator{
+			AllowedSecretNamespaces: secretNamespaces,
+		}); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SnowflakeAccount")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
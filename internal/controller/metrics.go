@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// orphanedAccounts is the number of operator-created Snowflake accounts found by the last orphan audit
+// that no SnowflakeAccount resource owns
+var orphanedAccounts = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "speck_orphaned_accounts",
+	Help: "Number of Snowflake accounts created by the operator that no SnowflakeAccount resource owns",
+})
+
+// snowflakeReachable is whether the latest connectivity check reached the organization configured in the
+// operator's environment
+var snowflakeReachable = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "speck_snowflake_reachable",
+	Help: "Whether the latest connectivity check reached the Snowflake organization (1) or failed (0)",
+})
+
+func init() {
+	metrics.Registry.MustRegister(orphanedAccounts, snowflakeReachable)
+}
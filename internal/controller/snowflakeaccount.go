@@ -2,16 +2,32 @@ package controller
 
 import (
 	"context"
+	"crypto/rsa"
+	"crypto/x509"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
-	_ "github.com/snowflakedb/gosnowflake"
+	"github.com/snowflakedb/gosnowflake"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -22,6 +38,24 @@ type snowflakeCredentials struct {
 	password string
 	account  string
 	role     string
+	host     string
+	// region is the region of an account locator that does not include it, e.g. "eu-central-1" or
+	// "us-east-1.privatelink"; a custom host takes precedence
+	region string
+	// orgName is the name of the Snowflake organization, used to address its accounts as
+	// {orgName}-{accountName}; if empty it is taken from an org-account form account
+	orgName string
+	// oauthToken, when set, is used instead of the password
+	oauthToken string
+	// profile is the name of the credential profile the credentials were read from, if any
+	profile string
+	// dsnParams are extra gosnowflake DSN parameters from SNOWFLAKE_ORG_DSN_PARAMS
+	dsnParams url.Values
+	// application is reported as the client application of the connection; an application in
+	// dsnParams takes precedence
+	application string
+	// childAccount marks credentials of an account of the organization, whose connections are not cached
+	childAccount bool
 }
 
 // accountDetails holds the details of a created Snowflake account
@@ -29,28 +63,284 @@ type accountDetails struct {
 	accountName   string
 	adminName     string
 	adminPassword string
-	email         string
-	region        string
-	edition       string
+	// adminPublicKey is the RSA public key set on the admin user, if any
+	adminPublicKey string
+	email          string
+	region         string
+	edition        string
+	// billingEntity is the consumption billing entity the account was created with, if any
+	billingEntity string
+	accountURL    string
+	tags          map[string]string
+	// accountLocator is Snowflake's locator for the account, empty if it could not be looked up
+	accountLocator string
+	// provisioningDuration is how long the CREATE ACCOUNT took to complete
+	provisioningDuration time.Duration
+	// passwordFromSecretRef is true when the admin password was supplied by the user and must not be stored
+	passwordFromSecretRef bool
+	// orgAccount and orgRole identify the organization account and role the account was created with
+	orgAccount string
+	orgRole    string
+}
+
+// dropGracePeriodDays is how long a dropped account can still be restored with UNDROP ACCOUNT
+const dropGracePeriodDays = 3
+
+// Bounds Snowflake accepts for GRACE_PERIOD_IN_DAYS
+const (
+	minDropGracePeriodDays = 3
+	maxDropGracePeriodDays = 90
+)
+
+// ValidateGracePeriodBounds checks the operator's grace period bounds; zero disables a bound
+func ValidateGracePeriodBounds(minDays, maxDays int) error {
+	for _, days := range []int{minDays, maxDays} {
+		if days != 0 && (days < minDropGracePeriodDays || days > maxDropGracePeriodDays) {
+			return fmt.Errorf("grace period of %d days must be between %d and %d", days, minDropGracePeriodDays, maxDropGracePeriodDays)
+		}
+	}
+	if minDays != 0 && maxDays != 0 && minDays > maxDays {
+		return fmt.Errorf("minimum grace period of %d days exceeds the maximum of %d days", minDays, maxDays)
+	}
+	return nil
+}
+
+// dropGracePeriod returns the grace period in days the account is dropped with, Spec.GracePeriodInDays
+// clamped to the operator's MinGracePeriodDays and MaxGracePeriodDays
+func (r *SnowflakeAccountReconciler) dropGracePeriod(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) int {
+	requested := dropGracePeriodDays
+	if account.Spec.GracePeriodInDays > 0 {
+		requested = account.Spec.GracePeriodInDays
+	}
+
+	days := requested
+	if r.MinGracePeriodDays > 0 && days < r.MinGracePeriodDays {
+		days = r.MinGracePeriodDays
+	}
+	if r.MaxGracePeriodDays > 0 && days > r.MaxGracePeriodDays {
+		days = r.MaxGracePeriodDays
+	}
+	if days != requested {
+		logf.FromContext(ctx).Info("Grace period clamped by the operator's bounds", "requestedDays", requested,
+			"gracePeriodDays", days, "minGracePeriodDays", r.MinGracePeriodDays, "maxGracePeriodDays", r.MaxGracePeriodDays)
+	}
+	return days
+}
+
+// defaultSnowflakeDomain is the domain used for account URLs when no custom host is configured
+const defaultSnowflakeDomain = "snowflakecomputing.com"
+
+// defaultAccountComment is the comment of an account whose Spec.Comment is unset
+const defaultAccountComment = "Created by Kubernetes Operator"
+
+// maxCommentLength is the longest comment, in characters, Snowflake accepts on an account
+const maxCommentLength = 256
+
+// qualifiedTagNamePattern matches a fully qualified tag name: database.schema.tag_name
+var qualifiedTagNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*\.[A-Za-z_][A-Za-z0-9_$]*\.[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// validateTags ensures every tag key is a fully qualified tag name
+func validateTags(tags map[string]string) error {
+	for key := range tags {
+		if !qualifiedTagNamePattern.MatchString(key) {
+			return fmt.Errorf("invalid tag name %q: must be a fully qualified identifier (database.schema.tag_name)", key)
+		}
+	}
+	return nil
+}
+
+// accountComment returns the comment of a new account. An over-long Spec.Comment is truncated if
+// Spec.TruncateComment is set and rejected otherwise, rather than failing CREATE ACCOUNT in Snowflake.
+func accountComment(account *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	comment := account.Spec.Comment
+	if comment == "" {
+		return defaultAccountComment, nil
+	}
+
+	runes := []rune(comment)
+	if len(runes) <= maxCommentLength {
+		return comment, nil
+	}
+	if !account.Spec.TruncateComment {
+		return "", fmt.Errorf("comment is %d characters long, but Snowflake allows at most %d; "+
+			"shorten it or set truncateComment", len(runes), maxCommentLength)
+	}
+	return string(runes[:maxCommentLength]), nil
+}
+
+// accountTags returns the tags to apply to a new account: the user's tags plus, when
+// KubernetesTagSchema is set, tags identifying the owning Kubernetes object and, when BillingEntityTag
+// is set, the account's billing entity. The operator's tags take precedence.
+func (r *SnowflakeAccountReconciler) accountTags(account *operatorv1alpha1.SnowflakeAccount, billingEntity string) map[string]string {
+	tagBillingEntity := r.BillingEntityTag != "" && billingEntity != ""
+	if r.KubernetesTagSchema == "" && !tagBillingEntity {
+		return account.Spec.Tags
+	}
+
+	tags := make(map[string]string, len(account.Spec.Tags)+4)
+	for key, value := range account.Spec.Tags {
+		tags[key] = value
+	}
+	if r.KubernetesTagSchema != "" {
+		tags[r.KubernetesTagSchema+".K8S_NAMESPACE"] = account.Namespace
+		tags[r.KubernetesTagSchema+".K8S_NAME"] = account.Name
+		tags[r.KubernetesTagSchema+".K8S_UID"] = string(account.UID)
+	}
+	if tagBillingEntity {
+		tags[r.BillingEntityTag] = billingEntity
+	}
+	return tags
+}
+
+// buildTagClause renders the WITH TAG clause for the given tags, or an empty string if there are none
+func buildTagClause(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	// Sort keys so the generated SQL is deterministic
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	assignments := make([]string, 0, len(keys))
+	for _, key := range keys {
+		assignments = append(assignments, fmt.Sprintf("%s = '%s'", key, escapeSQLString(tags[key])))
+	}
+	return fmt.Sprintf("WITH TAG (%s)", strings.Join(assignments, ", "))
+}
+
+// normalizeRSAPublicKey validates an RSA public key given in PEM or bare base64 form and returns
+// the base64-encoded key without PEM armor or line breaks, as expected by ADMIN_RSA_PUBLIC_KEY
+func normalizeRSAPublicKey(key string) (string, error) {
+	if strings.TrimSpace(key) == "" {
+		return "", nil
+	}
+
+	var body strings.Builder
+	for _, line := range strings.Split(key, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-----") {
+			continue
+		}
+		body.WriteString(line)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		return "", fmt.Errorf("admin public key is not valid base64: %w", err)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", fmt.Errorf("admin public key is not a valid public key: %w", err)
+	}
+	if _, ok := publicKey.(*rsa.PublicKey); !ok {
+		return "", fmt.Errorf("admin public key must be an RSA key")
+	}
+
+	return body.String(), nil
+}
+
+// buildAdminAuthClause returns the CREATE ACCOUNT properties that set the admin's credentials.
+// A password must be changed on first login; a key-only admin has no password at all.
+func buildAdminAuthClause(adminPassword, adminPublicKey string) string {
+	var properties []string
+	if adminPassword != "" {
+		properties = append(properties,
+			fmt.Sprintf("ADMIN_PASSWORD = '%s'", escapeSQLString(adminPassword)),
+			"MUST_CHANGE_PASSWORD = TRUE")
+	}
+	if adminPublicKey != "" {
+		properties = append(properties, fmt.Sprintf("ADMIN_RSA_PUBLIC_KEY = '%s'", adminPublicKey))
+	}
+	return strings.Join(properties, "\n            ")
+}
+
+// getAdminPasswordFromSecretRef reads the admin password from Spec.AdminPasswordSecretRef and validates it
+func (r *SnowflakeAccountReconciler) getAdminPasswordFromSecretRef(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	ref := account.Spec.AdminPasswordSecretRef
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: account.Namespace, Name: ref.Name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get admin password secret %s/%s: %w", account.Namespace, ref.Name, err)
+	}
+
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in admin password secret %s/%s", ref.Key, account.Namespace, ref.Name)
+	}
+
+	if err := validatePasswordComplexity(string(password)); err != nil {
+		return "", fmt.Errorf("admin password in secret %s/%s: %w", account.Namespace, ref.Name, err)
+	}
+
+	return string(password), nil
+}
+
+// validatePasswordComplexity checks a password against Snowflake's default password policy:
+// 8 to 256 characters with at least one uppercase letter, one lowercase letter and one digit
+func validatePasswordComplexity(password string) error {
+	if len(password) < 8 || len(password) > 256 {
+		return fmt.Errorf("password must be between 8 and 256 characters")
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, ch := range password {
+		switch {
+		case ch >= 'A' && ch <= 'Z':
+			hasUpper = true
+		case ch >= 'a' && ch <= 'z':
+			hasLower = true
+		case ch >= '0' && ch <= '9':
+			hasDigit = true
+		}
+	}
+
+	if !hasUpper || !hasLower || !hasDigit {
+		return fmt.Errorf("password must contain at least one uppercase letter, one lowercase letter and one digit")
+	}
+	return nil
 }
 
 // getSnowflakeCredentialsFromEnv fetches and validates organization credentials from environment variables
 func getSnowflakeCredentialsFromEnv() (*snowflakeCredentials, error) {
-	// Read credentials from environment variables
-	orgUsername := os.Getenv("SNOWFLAKE_ORG_USERNAME")
-	orgPassword := os.Getenv("SNOWFLAKE_ORG_PASSWORD")
-	orgAccount := os.Getenv("SNOWFLAKE_ORG_ACCOUNT")
-	orgRole := os.Getenv("SNOWFLAKE_ORG_ROLE")
+	return parseSnowflakeCredentials(os.Getenv, func(key string) string {
+		return fmt.Sprintf("environment variable %s", key)
+	})
+}
 
-	// Validate required fields
-	if orgUsername == "" {
-		return nil, fmt.Errorf("environment variable SNOWFLAKE_ORG_USERNAME is required but not set")
+// parseSnowflakeCredentials reads and validates organization credentials using the given lookup function.
+// describe names the source of a key for error messages.
+func parseSnowflakeCredentials(lookup func(string) string, describe func(string) string) (*snowflakeCredentials, error) {
+	orgUsername := lookup("SNOWFLAKE_ORG_USERNAME")
+	orgPassword := lookup("SNOWFLAKE_ORG_PASSWORD")
+	orgAccount := lookup("SNOWFLAKE_ORG_ACCOUNT")
+	orgRole := lookup("SNOWFLAKE_ORG_ROLE")
+	orgHost := lookup("SNOWFLAKE_ORG_HOST")
+	orgRegion := lookup("SNOWFLAKE_ORG_REGION")
+	orgName := lookup("SNOWFLAKE_ORG_NAME")
+	orgOAuthToken := lookup("SNOWFLAKE_ORG_OAUTH_TOKEN")
+
+	dsnParams, err := parseDSNParams(lookup("SNOWFLAKE_ORG_DSN_PARAMS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", describe("SNOWFLAKE_ORG_DSN_PARAMS"), err)
 	}
-	if orgPassword == "" {
-		return nil, fmt.Errorf("environment variable SNOWFLAKE_ORG_PASSWORD is required but not set")
+
+	// Validate required fields; a username and password are not needed with an OAuth token
+	if orgOAuthToken == "" && orgUsername == "" {
+		return nil, fmt.Errorf("%s is required but not set", describe("SNOWFLAKE_ORG_USERNAME"))
+	}
+	if orgOAuthToken == "" && orgPassword == "" {
+		return nil, fmt.Errorf("%s is required but not set", describe("SNOWFLAKE_ORG_PASSWORD"))
 	}
 	if orgAccount == "" {
-		return nil, fmt.Errorf("environment variable SNOWFLAKE_ORG_ACCOUNT is required but not set")
+		return nil, fmt.Errorf("%s is required but not set", describe("SNOWFLAKE_ORG_ACCOUNT"))
+	}
+	if orgName != "" && !orgNamePattern.MatchString(orgName) {
+		return nil, fmt.Errorf("invalid %s %q: must start with a letter and contain only letters and digits",
+			describe("SNOWFLAKE_ORG_NAME"), orgName)
 	}
 
 	// Default role if not specified
@@ -63,124 +353,613 @@ func getSnowflakeCredentialsFromEnv() (*snowflakeCredentials, error) {
 		password: orgPassword,
 		account:  orgAccount,
 		role:     orgRole,
+		host:     orgHost,
+		region:   orgRegion,
+		orgName:  orgName,
+
+		oauthToken: orgOAuthToken,
+		dsnParams:  dsnParams,
 	}, nil
 }
 
+// allowedDSNParams are the gosnowflake DSN parameters SNOWFLAKE_ORG_DSN_PARAMS may set. Parameters
+// derived from the credentials, such as account, role, region or authenticator, cannot be overridden.
+var allowedDSNParams = map[string]bool{
+	"application":               true,
+	"clientTimeout":             true,
+	"jwtClientTimeout":          true,
+	"loginTimeout":              true,
+	"requestTimeout":            true,
+	"maxRetryCount":             true,
+	"ocspFailOpen":              true,
+	"includeRetryReason":        true,
+	"tracing":                   true,
+	"client_session_keep_alive": true,
+	"client_session_keep_alive_heartbeat_frequency": true,
+	"query_tag": true,
+}
+
+// parseDSNParams parses comma-separated key=value gosnowflake DSN parameters, rejecting parameters
+// that are not in allowedDSNParams
+func parseDSNParams(value string) (url.Values, error) {
+	var params url.Values
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		key, paramValue, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("parameter %q must be in the form key=value", entry)
+		}
+		if !allowedDSNParams[key] {
+			return nil, fmt.Errorf("parameter %q may not be set", key)
+		}
+		if params == nil {
+			params = url.Values{}
+		}
+		params.Set(key, strings.TrimSpace(paramValue))
+	}
+	return params, nil
+}
+
+// getSnowflakeCredentials returns the organization credentials for the account.
+// Credentials are read from Spec.OrgCredentialsSecretRef when set, falling back to environment variables.
+func (r *SnowflakeAccountReconciler) getSnowflakeCredentials(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*snowflakeCredentials, error) {
+	if ref := account.Spec.OrgCredentialsSecretRef; ref != nil && ref.Name != "" {
+		return r.getSnowflakeCredentialsFromSecret(ctx, client.ObjectKey{Namespace: account.Namespace, Name: ref.Name})
+	}
+
+	if profile := account.Spec.CredentialProfile; profile != "" {
+		key, ok := r.CredentialProfiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("credential profile %q is not configured on the operator", profile)
+		}
+		creds, err := r.getSnowflakeCredentialsFromSecret(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("credential profile %q: %w", profile, err)
+		}
+		creds.profile = profile
+		return creds, nil
+	}
+
+	return getSnowflakeCredentialsFromEnv()
+}
+
+// getSnowflakeCredentialsFromSecret reads organization credentials from the given secret
+func (r *SnowflakeAccountReconciler) getSnowflakeCredentialsFromSecret(ctx context.Context, key client.ObjectKey) (*snowflakeCredentials, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get organization credentials secret %s: %w", key, err)
+	}
+
+	return parseSnowflakeCredentials(func(name string) string {
+		return string(secret.Data[name])
+	}, func(name string) string {
+		return fmt.Sprintf("key %s in secret %s", name, key)
+	})
+}
+
+// accountDomain returns the domain that account URLs are built under.
+// When a custom host is configured (e.g. org.privatelink.snowflakecomputing.com),
+// everything after the first label is used so child accounts share the same suffix.
+func (c *snowflakeCredentials) accountDomain() string {
+	host := c.host
+	if i := strings.Index(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.Index(host, "."); i >= 0 && i < len(host)-1 {
+		return host[i+1:]
+	}
+	return defaultSnowflakeDomain
+}
+
 // connectToSnowflake establishes a connection to Snowflake using the provided credentials
 func connectToSnowflake(creds *snowflakeCredentials) (*sql.DB, error) {
-	// Build the DSN (Data Source Name)
-	// Format: username:password@account?role=ORGADMIN
-	dsn := fmt.Sprintf("%s:%s@%s?role=%s",
-		creds.username,
-		creds.password,
-		creds.account,
-		creds.role)
-
-	// Open connection to Snowflake
+	dsn, err := buildDSN(creds)
+	if err != nil {
+		return nil, creds.redactError(fmt.Errorf("invalid connection settings for account %s: %w", creds.account, err))
+	}
+
+	// Open connection to Snowflake; the error may quote the DSN, so scrub the secrets from it
 	db, err := sql.Open("snowflake", dsn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open connection: %w", err)
+		return nil, creds.redactError(fmt.Errorf("failed to open connection to %s: %w", redactDSN(dsn), err))
 	}
 
 	return db, nil
 }
 
+// buildDSN builds the gosnowflake DSN for the credentials. It embeds the password or OAuth token,
+// so it must never be logged or returned in an error; use redactDSN when it has to be shown.
+//
+// The account is passed on its own and the host is derived from the region when one is set (e.g.
+// "us-east-1.privatelink"), so the account identifier does not have to embed it. gosnowflake escapes
+// the user and password, which may contain any character.
+func buildDSN(creds *snowflakeCredentials) (string, error) {
+	cfg := &gosnowflake.Config{
+		Account:  creds.account,
+		User:     creds.username,
+		Password: creds.password,
+		Role:     creds.role,
+		Region:   creds.region,
+
+		Application: creds.application,
+	}
+
+	// When a custom host is configured, connect to it directly, on port 443 unless it names one.
+	// The host already routes to the region, and gosnowflake would otherwise insert the region into it.
+	if creds.host != "" {
+		cfg.Region = ""
+		cfg.Host = creds.host
+		if host, port, err := net.SplitHostPort(creds.host); err == nil {
+			cfg.Host = host
+			if cfg.Port, err = strconv.Atoi(port); err != nil {
+				return "", fmt.Errorf("invalid port in host %q: %w", creds.host, err)
+			}
+		}
+	}
+
+	// Authenticate with an OAuth token when one is configured, otherwise with the password.
+	// The token is only ever placed in the DSN and must never be logged.
+	if creds.oauthToken != "" {
+		cfg.Password = ""
+		cfg.Authenticator = gosnowflake.AuthTypeOAuth
+		cfg.Token = creds.oauthToken
+	}
+
+	dsn, err := gosnowflake.DSN(cfg)
+	if err != nil || len(creds.dsnParams) == 0 {
+		return dsn, err
+	}
+	return withDSNParams(dsn, creds.dsnParams)
+}
+
+// withDSNParams sets the extra parameters on the DSN, replacing the defaults gosnowflake wrote for
+// them, and checks that gosnowflake accepts the result
+func withDSNParams(dsn string, params url.Values) (string, error) {
+	// The user and password are escaped, so the first '?' starts the parameters
+	base, rawQuery, _ := strings.Cut(dsn, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", err
+	}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	dsn = base + "?" + query.Encode()
+	if _, err := gosnowflake.ParseDSN(dsn); err != nil {
+		return "", fmt.Errorf("invalid SNOWFLAKE_ORG_DSN_PARAMS: %w", err)
+	}
+	return dsn, nil
+}
+
+// ParseDefaultEdition validates an operator-wide default edition, accepting the editions of Spec.Edition
+func ParseDefaultEdition(value string) (operatorv1alpha1.Edition, error) {
+	edition := operatorv1alpha1.Edition(strings.ToUpper(value))
+	switch edition {
+	case "", operatorv1alpha1.EditionStandard, operatorv1alpha1.EditionEnterprise, operatorv1alpha1.EditionBusinessCritical:
+		return edition, nil
+	}
+	return "", fmt.Errorf("invalid edition %q: must be one of %s, %s or %s", value,
+		operatorv1alpha1.EditionStandard, operatorv1alpha1.EditionEnterprise, operatorv1alpha1.EditionBusinessCritical)
+}
+
+// defaultEdition returns the edition of accounts created without Spec.Edition
+func (r *SnowflakeAccountReconciler) defaultEdition() operatorv1alpha1.Edition {
+	if r.DefaultEdition != "" {
+		return r.DefaultEdition
+	}
+	return operatorv1alpha1.DefaultEdition
+}
+
 // createSnowflakeAccount creates a new Snowflake account
 // Returns the account details and any error
 func (r *SnowflakeAccountReconciler) createSnowflakeAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*accountDetails, error) {
 	log := logf.FromContext(ctx)
+	provisioningStart := time.Now()
+
+	// Get Snowflake organization credentials for the account
+	creds, err := r.getSnowflakeCredentials(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	// A CREATE ACCOUNT that did not finish in time is retried for the same account and admin, so that if
+	// it still succeeded the retry fails with a name collision instead of creating a second account
+	accountName := account.Status.SnowflakeAccountName
+	retrying := accountName != ""
 
-	// Get Snowflake credentials from environment variables
-	creds, err := getSnowflakeCredentialsFromEnv()
+	// Generate all account details, honoring a user-chosen account name if provided
+	if !retrying {
+		accountName = strings.ToUpper(account.Spec.DesiredAccountName)
+	}
+	if accountName == "" && account.Spec.DeterministicName {
+		accountName, err = deterministicAccountName(account.UID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	generatedName := accountName == ""
+	if generatedName {
+		accountName, err = r.generateAccountName(account)
+		if err != nil {
+			return nil, err
+		}
+	}
+	adminName := account.Status.AdminName
+	if !retrying || adminName == "" {
+		adminName, err = r.generateAdminName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Bootstrap the admin with a public key when one is given, in which case a password is only
+	// set if one is referenced
+	adminPublicKey, err := normalizeRSAPublicKey(account.Spec.AdminPublicKey)
 	if err != nil {
 		return nil, err
 	}
+	adminPassword := ""
 
-	// Generate all account details
-	accountName := generateRandomAccountName()
-	adminName := generateRandomUsername()
-	adminPassword := generateRandomPassword()
+	// Use the user-supplied admin password if one is referenced
+	passwordFromSecretRef := account.Spec.AdminPasswordSecretRef != nil
+	if passwordFromSecretRef {
+		adminPassword, err = r.getAdminPasswordFromSecretRef(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A generated password is stored before each attempt, so the attempt being retried is retried with it
+	generatedPassword := adminPublicKey == "" && !passwordFromSecretRef
+	if generatedPassword {
+		adminPassword = r.generator().Password()
+		if retrying {
+			pending, err := r.loadPendingCreate(ctx, account, accountName)
+			if err != nil {
+				return nil, err
+			}
+			if pending != nil {
+				adminPassword = pending.adminPassword
+			}
+		}
+	}
 	firstName := "Admin"
 	lastName := "User"
 	email := fmt.Sprintf("%s@example.com", adminName) // Generate email from admin name
-	region := "AWS_US_WEST_2"
-	edition := "ENTERPRISE"
-	comment := "Created by Kubernetes Operator"
+	selectedRegion, err := r.selectRegion(account)
+	if err != nil {
+		return nil, err
+	}
+	region := availableRegion(account, selectedRegion)
+	if region != selectedRegion {
+		log.Info("Selected region is unavailable, using fallback region", "region", selectedRegion, "fallbackRegion", region)
+	}
+	edition := string(r.defaultEdition())
+	if account.Spec.Edition != "" {
+		edition = string(account.Spec.Edition)
+	}
+	comment, err := accountComment(account)
+	if err != nil {
+		return nil, err
+	}
+	billingEntity, err := r.billingEntity(account)
+	if err != nil {
+		return nil, err
+	}
+	tags := r.accountTags(account, billingEntity)
+
+	// Validate tags and the secret type before talking to Snowflake
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	if err := validateSecretType(account); err != nil {
+		return nil, err
+	}
 
 	// Log account creation (without sensitive credentials)
 	log.Info("Creating Snowflake account",
 		"accountName", accountName,
 		"region", region,
 		"edition", edition,
+		"billingEntity", billingEntity,
 		"resourceName", account.Name,
 		"namespace", account.Namespace)
 
-	// Connect to Snowflake
-	db, err := connectToSnowflake(creds)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if closeErr := db.Close(); closeErr != nil {
-			log.Error(closeErr, "Failed to close database connection")
-		}
-	}()
-
 	// Set a timeout for the operation
 	createCtx, cancel := context.WithTimeout(ctx, 120*time.Second)
 	defer cancel()
 
-	// Build CREATE ACCOUNT SQL
-	createAccountSQL := fmt.Sprintf(`
-        CREATE ACCOUNT %s
-            ADMIN_NAME = '%s'
-            ADMIN_PASSWORD = '%s'
-            ADMIN_USER_TYPE = PERSON
-            FIRST_NAME = '%s'
-            LAST_NAME = '%s'
-            EMAIL = '%s'
-            MUST_CHANGE_PASSWORD = TRUE
-            EDITION = %s
-            REGION = '%s'
-            COMMENT = '%s'
-    `,
-		accountName,
-		adminName,
-		adminPassword,
-		firstName,
-		lastName,
-		email,
-		edition,
-		region,
-		comment)
-
-	log.Info("Executing CREATE ACCOUNT SQL")
-
-	// Execute the CREATE ACCOUNT statement
-	_, err = db.ExecContext(createCtx, createAccountSQL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute CREATE ACCOUNT: %w", err)
+	// details returns the details of the account as last attempted
+	details := func(accountLocator string) *accountDetails {
+		return &accountDetails{
+			accountName:    accountName,
+			accountLocator: accountLocator,
+			adminName:      adminName,
+			adminPassword:  adminPassword,
+			adminPublicKey: adminPublicKey,
+			email:          email,
+			region:         region,
+			edition:        edition,
+			billingEntity:  billingEntity,
+			accountURL:     buildAccountURL(accountName, creds),
+			tags:           tags,
+
+			provisioningDuration:  time.Since(provisioningStart),
+			passwordFromSecretRef: passwordFromSecretRef,
+			orgAccount:            creds.account,
+			orgRole:               creds.role,
+		}
+	}
+
+	// A generated name that is already taken is replaced and retried right away, up to
+	// NameCollisionRetries times; other failures are retried by the caller with a backoff
+	var queryID string
+	for attempt := 0; ; attempt++ {
+		// Render the CREATE ACCOUNT statement from the configured template
+		createAccountSQL, err := r.renderCreateAccountSQL(&createAccountData{
+			AccountName:    accountName,
+			AdminName:      adminName,
+			AdminPassword:  adminPassword,
+			AdminPublicKey: adminPublicKey,
+			FirstName:      firstName,
+			LastName:       lastName,
+			Email:          email,
+			Edition:        edition,
+			Region:         region,
+			BillingEntity:  billingEntity,
+			Comment:        comment,
+			Tags:           tags,
+			Spec:           account.Spec,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if generatedPassword {
+			pending := &pendingCreate{accountName: accountName, adminName: adminName, adminPassword: adminPassword}
+			if err := r.storePendingCreate(ctx, account, pending); err != nil {
+				return nil, err
+			}
+		}
+
+		log.Info("Executing CREATE ACCOUNT SQL")
+
+		// Execute the CREATE ACCOUNT statement
+		queryID, err = r.snowflake().ExecAccount(createCtx, creds, createAccountSQL)
+		if err == nil {
+			break
+		}
+		log.Info("CREATE ACCOUNT failed", "accountName", accountName, "region", region, "queryID", queryID)
+		err = classifySnowflakeError(err)
+
+		// The statement was abandoned rather than rejected, so Snowflake may still have created the account.
+		// Record its name so the next reconcile looks it up instead of creating a second account; a generated
+		// password was stored before the attempt.
+		if isOutcomeUnknown(createCtx, err) {
+			account.Status.SnowflakeAccountName = accountName
+			account.Status.AdminName = adminName
+			return nil, fmt.Errorf("CREATE ACCOUNT %s did not finish in time%s; whether it was created is checked before retrying: %w",
+				accountName, queryIDSuffix(queryID), err)
+		}
+
+		if isNameCollision(err) && generatedName && attempt < r.NameCollisionRetries {
+			takenName := accountName
+			accountName, err = r.generateAccountName(account)
+			if err != nil {
+				return nil, err
+			}
+			log.Info("Account name is already taken, retrying with a new name",
+				"accountName", takenName, "newAccountName", accountName, "attempt", attempt+1)
+			continue
+		}
+
+		if isRegionUnavailable(err) {
+			account.Status.UnavailableRegions = append(account.Status.UnavailableRegions, region)
+		}
+		return nil, fmt.Errorf("failed to execute CREATE ACCOUNT %s%s: %w", accountName, queryIDSuffix(queryID), err)
 	}
 
-	log.Info("Snowflake account created successfully", "accountName", accountName)
+	log.Info("Snowflake account created successfully", "accountName", accountName, "queryID", queryID)
+
+	// The account already exists, so a failed lookup must not fail the creation
+	accountLocator, err := r.lookupAccountLocator(createCtx, creds, accountName)
+	if err != nil {
+		log.Error(err, "Failed to look up account locator", "accountName", accountName)
+	}
 
 	// Return account details for secret creation
+	return details(accountLocator), nil
+}
+
+// lookupAccountLocator returns the locator of an account in the organization from SHOW ACCOUNTS
+func (r *SnowflakeAccountReconciler) lookupAccountLocator(ctx context.Context, creds *snowflakeCredentials, accountName string) (string, error) {
+	rows, err := r.snowflake().ShowAccounts(ctx, creds, accountName)
+	if err != nil {
+		return "", fmt.Errorf("failed to show account %s: %w", accountName, classifySnowflakeError(err))
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("account %s not found in SHOW ACCOUNTS", accountName)
+	}
+	return rows[0]["account_locator"], nil
+}
+
+// accountDetailsFromRow builds the details of an existing account from its SHOW ACCOUNTS row
+func accountDetailsFromRow(accountName string, row map[string]string, creds *snowflakeCredentials) *accountDetails {
+	accountURL := row["account_url"]
+	if accountURL != "" && !strings.Contains(accountURL, "://") {
+		accountURL = "https://" + accountURL
+	}
+	if accountURL == "" {
+		accountURL = buildAccountURL(accountName, creds)
+	}
+
 	return &accountDetails{
-		accountName:   accountName,
-		adminName:     adminName,
-		adminPassword: adminPassword,
-		email:         email,
-		region:        region,
-		edition:       edition,
-	}, nil
+		accountName:    accountName,
+		accountLocator: row["account_locator"],
+		region:         row["snowflake_region"],
+		edition:        row["edition"],
+		accountURL:     accountURL,
+		orgAccount:     creds.account,
+		orgRole:        creds.role,
+	}
+}
+
+// ParseDiscoveryLabelKeys parses a comma-separated list of label keys, ignoring empty entries
+func ParseDiscoveryLabelKeys(value string) ([]string, error) {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParseSecretNamespaces parses a comma-separated list of namespaces, failing on a name that is not a valid namespace
+func ParseSecretNamespaces(value string) ([]string, error) {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, nil
+}
+
+// validateSecretNamespace checks that Spec.SecretNamespace is the namespace of the resource or one of
+// AllowedSecretNamespaces, so a user cannot write secrets into namespaces they have no access to
+func (r *SnowflakeAccountReconciler) validateSecretNamespace(account *operatorv1alpha1.SnowflakeAccount) error {
+	namespace := credentialsSecretNamespace(account)
+	if namespace == account.Namespace || slices.Contains(r.AllowedSecretNamespaces, namespace) {
+		return nil
+	}
+	if len(r.AllowedSecretNamespaces) == 0 {
+		return fmt.Errorf("secret namespace %s requires the operator to be configured with --allowed-secret-namespaces", namespace)
+	}
+	return fmt.Errorf("secret namespace %s is not one of the allowed secret namespaces %s",
+		namespace, strings.Join(r.AllowedSecretNamespaces, ", "))
+}
+
+// credentialsSecretLabels merges Spec.SecretLabels with the labels the operator manages on the
+// credentials secret. Managed labels win so the secret can always be found and attributed.
+func (r *SnowflakeAccountReconciler) credentialsSecretLabels(account *operatorv1alpha1.SnowflakeAccount) map[string]string {
+	labels := make(map[string]string, len(account.Spec.SecretLabels)+3+len(r.DiscoveryLabelKeys))
+	for key, value := range account.Spec.SecretLabels {
+		labels[key] = value
+	}
+	labels["app.kubernetes.io/name"] = "snowflake-account"
+	labels["app.kubernetes.io/managed-by"] = "snowflake-operator"
+	for key, value := range r.discoveryLabels(account) {
+		labels[key] = value
+	}
+	if credentialsSecretNamespace(account) != account.Namespace {
+		labels[ownerUIDLabel] = string(account.UID)
+	}
+	return labels
+}
+
+// discoveryLabels returns the labels identifying the credentials secret of the account: its name, the
+// owner namespace for secrets in another namespace and the values of DiscoveryLabelKeys on the resource,
+// which are empty when the resource does not have the label
+func (r *SnowflakeAccountReconciler) discoveryLabels(account *operatorv1alpha1.SnowflakeAccount) map[string]string {
+	labels := map[string]string{
+		"app.kubernetes.io/instance": account.Name,
+	}
+	if credentialsSecretNamespace(account) != account.Namespace {
+		labels[ownerNamespaceLabel] = account.Namespace
+	}
+	for _, key := range r.DiscoveryLabelKeys {
+		labels[key] = account.Labels[key]
+	}
+	return labels
+}
+
+// ownerNamespaceLabel records the namespace of the owning resource on credentials secrets
+// created in another namespace, where owner references cannot be used
+const ownerNamespaceLabel = "operator.dataverse.redhat.com/owner-namespace"
+
+// ownerUIDLabel records the UID of the owning resource on credentials secrets created in another
+// namespace, standing in for the controller owner reference
+const ownerUIDLabel = "operator.dataverse.redhat.com/owner-uid"
+
+// ownsCredentialsSecret reports whether the secret was written for this resource: it is controlled by the
+// resource or, in another namespace, labeled with its UID. Anyone who can create secrets can set the
+// discovery labels, so a secret with matching labels alone must not hand its account to the resource.
+func ownsCredentialsSecret(account *operatorv1alpha1.SnowflakeAccount, secret *corev1.Secret) bool {
+	if secret.Namespace != account.Namespace {
+		uid, ok := secret.Labels[ownerUIDLabel]
+		return ok && uid == string(account.UID)
+	}
+	owner := metav1.GetControllerOf(secret)
+	return owner != nil && owner.UID == account.UID
+}
+
+// credentialsSecretNamespace returns the namespace the credentials secret lives in
+func credentialsSecretNamespace(account *operatorv1alpha1.SnowflakeAccount) string {
+	if account.Spec.SecretNamespace != "" {
+		return account.Spec.SecretNamespace
+	}
+	return account.Namespace
+}
+
+// credentialsSecretAnnotations returns the annotations to set on the credentials secret
+func credentialsSecretAnnotations(account *operatorv1alpha1.SnowflakeAccount) map[string]string {
+	if len(account.Spec.SecretAnnotations) == 0 {
+		return nil
+	}
+	annotations := make(map[string]string, len(account.Spec.SecretAnnotations))
+	for key, value := range account.Spec.SecretAnnotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+// validateSecretType checks that the credentials for the requested secret type can be stored
+func validateSecretType(account *operatorv1alpha1.SnowflakeAccount) error {
+	if account.Spec.SecretType != operatorv1alpha1.CredentialsSecretTypeBasicAuth {
+		return nil
+	}
+	if account.Spec.AdminPublicKey != "" || account.Spec.AdminPasswordSecretRef != nil {
+		return fmt.Errorf("secret type %s requires a generated admin password; unset adminPublicKey and adminPasswordSecretRef",
+			account.Spec.SecretType)
+	}
+	return nil
+}
+
+// credentialsSecretType returns the type of the credentials secret, adding the keys the type requires to data.
+// Fails if a required key would be empty.
+func credentialsSecretType(account *operatorv1alpha1.SnowflakeAccount, data map[string][]byte) (corev1.SecretType, error) {
+	if account.Spec.SecretType != operatorv1alpha1.CredentialsSecretTypeBasicAuth {
+		return corev1.SecretTypeOpaque, nil
+	}
+
+	data[corev1.BasicAuthUsernameKey] = data["adminName"]
+	data[corev1.BasicAuthPasswordKey] = data["adminPassword"]
+	for _, key := range []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey} {
+		if len(data[key]) == 0 {
+			return "", fmt.Errorf("secret type %s requires the %s key", account.Spec.SecretType, key)
+		}
+	}
+	return corev1.SecretTypeBasicAuth, nil
 }
 
 // createCredentialsSecret creates a Kubernetes Secret to store the Snowflake account credentials
 func (r *SnowflakeAccountReconciler) createCredentialsSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, details *accountDetails) error {
 	log := logf.FromContext(ctx)
 
-	// Create secret name: {accountName}-creds (lowercase for Kubernetes naming requirements)
-	secretName := fmt.Sprintf("%s-creds", strings.ToLower(details.accountName))
+	secretName := credentialsSecretName(details.accountName)
 
 	// Prepare secret data
 	secretData := map[string][]byte{
@@ -190,40 +969,271 @@ func (r *SnowflakeAccountReconciler) createCredentialsSecret(ctx context.Context
 		"email":         []byte(details.email),
 		"region":        []byte(details.region),
 		"edition":       []byte(details.edition),
-		"accountURL":    []byte(fmt.Sprintf("https://%s.snowflakecomputing.com", details.accountName)),
+		"accountURL":    []byte(details.accountURL),
 	}
 
-	// Create the Secret object
-	secret := &corev1.Secret{
+	// Never copy a user-supplied password; it already lives in the referenced secret
+	if details.passwordFromSecretRef || details.adminPassword == "" {
+		delete(secretData, "adminPassword")
+	}
+	if details.adminPublicKey != "" {
+		secretData["adminPublicKey"] = []byte(details.adminPublicKey)
+	}
+	if details.accountLocator != "" {
+		secretData["accountLocator"] = []byte(details.accountLocator)
+	}
+
+	secretType, err := credentialsSecretType(account, secretData)
+	if err != nil {
+		return err
+	}
+
+	// Store the non-sensitive details separately first; the secret marks the account as created
+	if account.Spec.SplitCredentials {
+		if err := r.writeConnInfoConfigMap(ctx, account, splitConnInfo(secretData)); err != nil {
+			return err
+		}
+	}
+
+	// Apply the secret server-side, so the operator owns exactly the fields it sets and a secret
+	// written by someone else is reported as a conflict instead of being overwritten
+	secretNamespace := credentialsSecretNamespace(account)
+	secret := corev1ac.Secret(secretName, secretNamespace).
+		WithLabels(r.credentialsSecretLabels(account)).
+		WithAnnotations(credentialsSecretAnnotations(account)).
+		WithOwnerReferences(ownerReferenceConfigurations(credentialsOwnerReferences(account))...).
+		WithType(secretType).
+		WithData(secretData)
+	if immutable := immutableSecret(account); immutable != nil {
+		secret.WithImmutable(*immutable)
+	}
+
+	if err := r.Apply(ctx, secret, client.FieldOwner(fieldManager)); err != nil {
+		log.Error(err, "Failed to apply credentials secret", "secretName", secretName)
+		return fmt.Errorf("failed to apply secret: %w", err)
+	}
+
+	recordCredentialsSecret(account, &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
-			Namespace: account.Namespace,
-			Labels: map[string]string{
-				"app.kubernetes.io/name":       "snowflake-account",
-				"app.kubernetes.io/managed-by": "snowflake-operator",
-				"app.kubernetes.io/instance":   account.Name,
-			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion: account.APIVersion,
-					Kind:       account.Kind,
-					Name:       account.Name,
-					UID:        account.UID,
-					Controller: boolPtr(true),
-				},
-			},
+			Name:            secretName,
+			Namespace:       secretNamespace,
+			ResourceVersion: ptr.Deref(secret.ResourceVersion, ""),
 		},
-		Type: corev1.SecretTypeOpaque,
-		Data: secretData,
+	})
+	log.Info("Successfully created credentials secret", "secretName", secretName, "namespace", secretNamespace)
+	return nil
+}
+
+// credentialsOwnerReferences returns the owner references of the objects holding the account's credentials.
+// Owner references cannot cross namespaces; such objects are cleaned up by the finalizer.
+func credentialsOwnerReferences(account *operatorv1alpha1.SnowflakeAccount) []metav1.OwnerReference {
+	if credentialsSecretNamespace(account) != account.Namespace {
+		return nil
 	}
+	return []metav1.OwnerReference{
+		{
+			APIVersion: account.APIVersion,
+			Kind:       account.Kind,
+			Name:       account.Name,
+			UID:        account.UID,
+			Controller: boolPtr(true),
+		},
+	}
+}
 
-	// Create the secret in the cluster
-	if err := r.Create(ctx, secret); err != nil {
-		log.Error(err, "Failed to create credentials secret", "secretName", secretName)
-		return fmt.Errorf("failed to create secret: %w", err)
+// ownerReferenceConfigurations converts owner references for use in an apply configuration
+func ownerReferenceConfigurations(refs []metav1.OwnerReference) []*metav1ac.OwnerReferenceApplyConfiguration {
+	configurations := make([]*metav1ac.OwnerReferenceApplyConfiguration, 0, len(refs))
+	for _, ref := range refs {
+		configuration := metav1ac.OwnerReference().
+			WithAPIVersion(ref.APIVersion).
+			WithKind(ref.Kind).
+			WithName(ref.Name).
+			WithUID(ref.UID)
+		if ref.Controller != nil {
+			configuration.WithController(*ref.Controller)
+		}
+		configurations = append(configurations, configuration)
+	}
+	return configurations
+}
+
+// recordCredentialsSecret records the credentials secret the operator has just written in the status,
+// so consumers can detect changes from its resourceVersion. The caller is responsible for persisting
+// the status.
+func recordCredentialsSecret(account *operatorv1alpha1.SnowflakeAccount, secret *corev1.Secret) {
+	account.Status.CredentialsSecret = &operatorv1alpha1.CredentialsSecretReference{
+		Name:            secret.Name,
+		Namespace:       secret.Namespace,
+		ResourceVersion: secret.ResourceVersion,
 	}
+}
 
-	log.Info("Successfully created credentials secret", "secretName", secretName, "namespace", account.Namespace)
+// immutableSecret returns the Immutable flag of the account's credentials secret
+func immutableSecret(account *operatorv1alpha1.SnowflakeAccount) *bool {
+	if !account.Spec.ImmutableSecret {
+		return nil
+	}
+	return boolPtr(true)
+}
+
+// secretReplacementSuffix is appended to the name of an immutable credentials secret to name the secret
+// holding its replacement until the swap is complete
+const secretReplacementSuffix = "-replacement"
+
+// writeCredentialsSecret stores the changed data of an existing credentials secret. An immutable secret
+// cannot be updated, so it is replaced instead: the new data is first stored in a replacement secret, then
+// the secret is deleted and created again from it, so the secret is briefly missing in between. Should the
+// secret not be created again, restoreSecretReplacement finishes the swap from the replacement secret.
+func (r *SnowflakeAccountReconciler) writeCredentialsSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, secret *corev1.Secret) error {
+	log := logf.FromContext(ctx)
+
+	if secret.Immutable == nil || !*secret.Immutable {
+		secret.Immutable = immutableSecret(account)
+		return r.Update(ctx, secret)
+	}
+
+	// The replacement has no labels, so it is never mistaken for the credentials secret
+	staged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secret.Name + secretReplacementSuffix,
+			Namespace:       secret.Namespace,
+			Annotations:     secret.Annotations,
+			OwnerReferences: secret.OwnerReferences,
+		},
+		Type:      secret.Type,
+		Data:      secret.Data,
+		Immutable: boolPtr(true),
+	}
+	if err := r.Delete(ctx, staged); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete stale replacement secret: %w", err)
+	}
+	if err := r.Create(ctx, staged); err != nil {
+		return fmt.Errorf("failed to store replacement secret: %w", err)
+	}
+
+	// Only delete the version that was read, so concurrent changes are not lost
+	resourceVersion := secret.ResourceVersion
+	if err := r.Delete(ctx, secret, client.Preconditions{ResourceVersion: &resourceVersion}); client.IgnoreNotFound(err) != nil {
+		if deleteErr := r.Delete(ctx, staged); client.IgnoreNotFound(deleteErr) != nil {
+			log.Error(deleteErr, "Failed to delete replacement secret", "secretName", staged.Name, "namespace", staged.Namespace)
+		}
+		return fmt.Errorf("failed to delete immutable secret for replacement: %w", err)
+	}
+
+	replacement := secretFromReplacement(secret.Name, secret.Labels, staged)
+	if err := r.Create(ctx, replacement); err != nil {
+		log.Error(err, "Failed to recreate immutable credentials secret, will retry from the replacement secret",
+			"secretName", secret.Name, "namespace", secret.Namespace)
+		return fmt.Errorf("failed to recreate immutable secret: %w", err)
+	}
+	if err := r.Delete(ctx, staged); client.IgnoreNotFound(err) != nil {
+		// Deleted by restoreSecretReplacement once it finds the secret in place
+		log.Error(err, "Failed to delete replacement secret", "secretName", staged.Name, "namespace", staged.Namespace)
+	}
+
+	*secret = *replacement
+	log.Info("Replaced immutable credentials secret", "secretName", secret.Name, "namespace", secret.Namespace)
+	return nil
+}
+
+// secretFromReplacement returns the immutable credentials secret named name holding the data of a
+// replacement secret
+func secretFromReplacement(name string, labels map[string]string, staged *corev1.Secret) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       staged.Namespace,
+			Labels:          labels,
+			Annotations:     staged.Annotations,
+			OwnerReferences: staged.OwnerReferences,
+		},
+		Type:      staged.Type,
+		Data:      staged.Data,
+		Immutable: boolPtr(true),
+	}
+}
+
+// credentialsSecretKey returns the name and namespace of the credentials secret as recorded in the status,
+// or as derived from the account name if none was recorded yet
+func credentialsSecretKey(account *operatorv1alpha1.SnowflakeAccount) types.NamespacedName {
+	if ref := account.Status.CredentialsSecret; ref != nil {
+		return types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+	}
+	return types.NamespacedName{Name: credentialsSecretName(account.Status.AccountName), Namespace: credentialsSecretNamespace(account)}
+}
+
+// restoreSecretReplacement finishes replacing an immutable credentials secret that writeCredentialsSecret
+// deleted but failed to create again, returning whether it created the secret. The replacement secret is
+// deleted once the credentials secret exists; a secret that still or again exists is kept, since the
+// replacement was only stored ahead of a change that is retried from the secret's data.
+func (r *SnowflakeAccountReconciler) restoreSecretReplacement(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	key := credentialsSecretKey(account)
+	staged := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: key.Name + secretReplacementSuffix, Namespace: key.Namespace}, staged)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get replacement secret: %w", err)
+	}
+
+	restored := false
+	secret := secretFromReplacement(key.Name, r.credentialsSecretLabels(account), staged)
+	err = r.Create(ctx, secret)
+	switch {
+	case err == nil:
+		restored = true
+	case !errors.IsAlreadyExists(err):
+		return false, fmt.Errorf("failed to recreate immutable secret: %w", err)
+	}
+	if err := r.Delete(ctx, staged); client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("failed to delete replacement secret: %w", err)
+	}
+	if !restored {
+		return false, nil
+	}
+	recordCredentialsSecret(account, secret)
+
+	log.Info("Restored immutable credentials secret", "secretName", secret.Name, "namespace", secret.Namespace)
+	return true, r.updateStatus(ctx, account)
+}
+
+// credentialsSecretName returns the name of the credentials secret for a Snowflake account:
+// {accountName}-creds (lowercase for Kubernetes naming requirements)
+func credentialsSecretName(accountName string) string {
+	return fmt.Sprintf("%s-creds", strings.ToLower(accountName))
+}
+
+// ensureCredentialsSecret creates the credentials secret, confirms it exists and records the
+// outcome in the SecretReady condition. The caller is responsible for persisting the status.
+func (r *SnowflakeAccountReconciler) ensureCredentialsSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, details *accountDetails) error {
+	location := ""
+	sink, err := r.credentialsSink(account)
+	if err == nil {
+		location, err = sink.Write(ctx, account, details.sinkCredentials())
+	}
+
+	if err != nil {
+		meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+			Type:               conditionSecretReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "SecretCreationFailed",
+			Message:            err.Error(),
+			ObservedGeneration: account.Generation,
+		})
+		return err
+	}
+
+	meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+		Type:               conditionSecretReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SecretCreated",
+		Message:            fmt.Sprintf("Credentials stored in %s", location),
+		ObservedGeneration: account.Generation,
+	})
 	return nil
 }
 
@@ -237,11 +1247,14 @@ func boolPtr(b bool) *bool {
 func (r *SnowflakeAccountReconciler) deleteSnowflakeAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
 	log := logf.FromContext(ctx)
 
-	// Extract the account name from the status or from the secret
-	accountName := extractAccountNameFromURL(account.Status.AccountURL)
+	// Use the recorded account name, falling back to the URL and then the secret
+	accountName := account.Status.SnowflakeAccountName
 	if accountName == "" {
-		// Try to get it from the secret
-		accountName, err := r.getAccountNameFromSecret(ctx, account)
+		accountName = extractAccountNameFromURL(account.Status.AccountURL)
+	}
+	if accountName == "" {
+		var err error
+		accountName, err = r.getAccountNameFromSecret(ctx, account)
 		if err != nil {
 			log.Error(err, "Failed to get account name from secret")
 			log.Info("No account name found, skipping deletion")
@@ -253,8 +1266,8 @@ func (r *SnowflakeAccountReconciler) deleteSnowflakeAccount(ctx context.Context,
 		}
 	}
 
-	// Get Snowflake organization credentials from environment variables
-	creds, err := getSnowflakeCredentialsFromEnv()
+	// Get Snowflake organization credentials for the account
+	creds, err := r.getSnowflakeCredentials(ctx, account)
 	if err != nil {
 		return err
 	}
@@ -264,34 +1277,26 @@ func (r *SnowflakeAccountReconciler) deleteSnowflakeAccount(ctx context.Context,
 		"orgAccount", creds.account,
 		"orgRole", creds.role)
 
-	// Connect to Snowflake
-	db, err := connectToSnowflake(creds)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if closeErr := db.Close(); closeErr != nil {
-			log.Error(closeErr, "Failed to close database connection")
-		}
-	}()
-
 	// Set a timeout for the operation
 	deleteCtx, cancel := context.WithTimeout(ctx, 120*time.Second)
 	defer cancel()
 
-	// Build DROP ACCOUNT SQL with IF EXISTS and GRACE_PERIOD_IN_DAYS
-	// Using 3 days grace period by default
-	dropAccountSQL := fmt.Sprintf(`DROP ACCOUNT IF EXISTS %s GRACE_PERIOD_IN_DAYS = 3`, accountName)
+	// Build DROP ACCOUNT SQL with IF EXISTS and GRACE_PERIOD_IN_DAYS, which Snowflake requires
+	gracePeriodDays := r.dropGracePeriod(ctx, account)
+	dropAccountSQL := fmt.Sprintf(`DROP ACCOUNT IF EXISTS %s GRACE_PERIOD_IN_DAYS = %d`, accountName, gracePeriodDays)
 
 	log.Info("Executing DROP ACCOUNT", "sql", dropAccountSQL)
 
 	// Execute the DROP ACCOUNT statement
-	_, err = db.ExecContext(deleteCtx, dropAccountSQL)
+	queryID, err := r.snowflake().DropAccount(deleteCtx, creds, dropAccountSQL)
 	if err != nil {
-		return fmt.Errorf("failed to execute DROP ACCOUNT: %w", err)
+		return fmt.Errorf("failed to execute DROP ACCOUNT%s: %w", queryIDSuffix(queryID), classifySnowflakeError(err))
 	}
 
-	log.Info("Successfully executed DROP ACCOUNT", "accountName", accountName)
+	recoverableUntil := metav1.NewTime(r.Clock.Now().AddDate(0, 0, gracePeriodDays))
+	account.Status.RecoverableUntil = &recoverableUntil
+
+	log.Info("Successfully executed DROP ACCOUNT", "accountName", accountName, "queryID", queryID, "recoverableUntil", recoverableUntil)
 	return nil
 }
 
@@ -299,28 +1304,158 @@ func (r *SnowflakeAccountReconciler) deleteSnowflakeAccount(ctx context.Context,
 func (r *SnowflakeAccountReconciler) getAccountNameFromSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (string, error) {
 	log := logf.FromContext(ctx)
 
-	// List secrets in the namespace with our label
+	secret, err := r.getCredentialsSecret(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		log.Info("No credential secret found for account")
+		return "", nil
+	}
+
+	accountName := string(secret.Data["accountName"])
+
+	log.Info("Found account name from secret", "secretName", secret.Name, "accountName", accountName)
+	return accountName, nil
+}
+
+// getCredentialsSecret returns the credentials secret for the account, or nil if none exists
+func (r *SnowflakeAccountReconciler) getCredentialsSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*corev1.Secret, error) {
+	// The secret recorded in the status is found by name, so changing the labels of the resource does
+	// not lose track of it
+	if ref := account.Status.CredentialsSecret; ref != nil {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, secret)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		return secret, nil
+	}
+
+	// List secrets in the secret namespace with our label
+	secretNamespace := credentialsSecretNamespace(account)
+	matchingLabels := client.MatchingLabels(r.discoveryLabels(account))
+
 	secretList := &corev1.SecretList{}
 	listOpts := []client.ListOption{
-		client.InNamespace(account.Namespace),
-		client.MatchingLabels{
-			"app.kubernetes.io/instance": account.Name,
-		},
+		client.InNamespace(secretNamespace),
+		matchingLabels,
 	}
 
 	if err := r.List(ctx, secretList, listOpts...); err != nil {
-		return "", fmt.Errorf("failed to list secrets: %w", err)
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
 	}
 
-	if len(secretList.Items) == 0 {
-		log.Info("No credential secret found for account")
-		return "", nil
+	// Use the first matching secret that belongs to the resource
+	for i := range secretList.Items {
+		secret := &secretList.Items[i]
+		if ownsCredentialsSecret(account, secret) {
+			return secret, nil
+		}
+		logf.FromContext(ctx).Info("Ignoring labeled secret that does not belong to the resource",
+			"secretName", secret.Name, "namespace", secret.Namespace)
 	}
+	return nil, nil
+}
 
-	// Get the account name from the first matching secret
-	secret := secretList.Items[0]
-	accountName := string(secret.Data["accountName"])
+// deleteCredentialsSecret deletes the credentials secret and conninfo ConfigMap for the account if they exist
+func (r *SnowflakeAccountReconciler) deleteCredentialsSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
 
-	log.Info("Found account name from secret", "secretName", secret.Name, "accountName", accountName)
-	return accountName, nil
+	if err := r.deleteConnInfoConfigMap(ctx, account); err != nil {
+		return err
+	}
+	if err := r.deleteMonitoringSecret(ctx, account); err != nil {
+		return err
+	}
+
+	// A replacement secret left behind by writeCredentialsSecret is not garbage collected in another namespace
+	key := credentialsSecretKey(account)
+	staged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: key.Name + secretReplacementSuffix, Namespace: key.Namespace}}
+	if err := r.Delete(ctx, staged); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete replacement secret: %w", err)
+	}
+
+	secret, err := r.getCredentialsSecret(ctx, account)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		log.Info("No credential secret found for account, nothing to delete")
+		return nil
+	}
+
+	if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+
+	log.Info("Deleted credentials secret", "secretName", secret.Name, "namespace", secret.Namespace)
+	return nil
+}
+
+// renameSnowflakeAccount renames an existing Snowflake account using ALTER ACCOUNT ... RENAME TO
+// Returns the URL of the renamed account and any error
+func (r *SnowflakeAccountReconciler) renameSnowflakeAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, oldName, newName string) (string, error) {
+	log := logf.FromContext(ctx)
+
+	// Get Snowflake organization credentials for the account
+	creds, err := r.getSnowflakeCredentials(ctx, account)
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("Renaming Snowflake account", "oldName", oldName, "newName", newName)
+
+	// Set a timeout for the operation
+	renameCtx, cancel := context.WithTimeout(ctx, 120*time.Second)
+	defer cancel()
+
+	renameAccountSQL := fmt.Sprintf(`ALTER ACCOUNT %s RENAME TO %s`, oldName, newName)
+
+	log.Info("Executing ALTER ACCOUNT RENAME", "sql", renameAccountSQL)
+
+	// Execute the ALTER ACCOUNT statement
+	if err := r.snowflake().Exec(renameCtx, creds, renameAccountSQL); err != nil {
+		return "", fmt.Errorf("failed to execute ALTER ACCOUNT RENAME: %w", classifySnowflakeError(err))
+	}
+
+	log.Info("Successfully renamed Snowflake account", "oldName", oldName, "newName", newName)
+	return buildAccountURL(newName, creds), nil
+}
+
+// updateCredentialsSecretAccount updates the account name and URL stored in the credentials secret
+func (r *SnowflakeAccountReconciler) updateCredentialsSecretAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, accountName, accountURL string) error {
+	log := logf.FromContext(ctx)
+
+	secret, err := r.getCredentialsSecret(ctx, account)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		log.Info("No credential secret found for account, skipping secret update")
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["accountName"] = []byte(accountName)
+	if account.Spec.SplitCredentials {
+		if err := r.updateConnInfoAccount(ctx, account, accountName, accountURL); err != nil {
+			return err
+		}
+	} else {
+		secret.Data["accountURL"] = []byte(accountURL)
+	}
+
+	if err := r.writeCredentialsSecret(ctx, account, secret); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	recordCredentialsSecret(account, secret)
+
+	log.Info("Updated credentials secret with new account name", "secretName", secret.Name, "accountName", accountName)
+	return nil
 }
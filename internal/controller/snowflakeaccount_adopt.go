@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// errAdoptedAccountNotFound is returned when the account to adopt does not exist in the organization
+var errAdoptedAccountNotFound = errors.New("account to adopt does not exist")
+
+// reconcileAdoption takes over management of an existing Snowflake account instead of creating one,
+// populating the status and credentials secret from SHOW ACCOUNTS
+func (r *SnowflakeAccountReconciler) reconcileAdoption(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	accountName := strings.ToUpper(snowflakeAccount.Spec.ExistingAccountName)
+	if err := validateAdoption(snowflakeAccount); err != nil {
+		// Retrying cannot help until the spec is fixed, which triggers a new reconcile
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Cannot adopt account: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseProvisioning); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Adopting existing Snowflake account", "accountName", accountName)
+
+	details, err := r.adoptSnowflakeAccount(ctx, snowflakeAccount, accountName)
+	if err != nil {
+		if r.recordInterruption(ctx, snowflakeAccount, "Adopting the Snowflake account", err) {
+			return ctrl.Result{}, err
+		}
+		if errors.Is(err, errAdoptedAccountNotFound) {
+			log.Info("Snowflake account to adopt does not exist", "accountName", accountName)
+			snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+			snowflakeAccount.Status.Message = fmt.Sprintf("Cannot adopt account: %s does not exist in the organization", accountName)
+			return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+		}
+
+		log.Error(err, "Failed to adopt Snowflake account")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Failed to adopt account: %v", err)
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Record the account name right away so it is dropped on deletion even if a step below fails
+	if err := r.recordSnowflakeAccountName(ctx, snowflakeAccount, details.accountName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureCredentialsSecret(ctx, snowflakeAccount, details); err != nil {
+		log.Error(err, "Failed to create credentials secret")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Account adopted but failed to store credentials: %v", err)
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatusAfterCreation(ctx, snowflakeAccount, details); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	snowflakeAccount.Status.Message = fmt.Sprintf("Existing Snowflake account %s adopted", accountName)
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status after account adoption")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Successfully adopted Snowflake account", "accountName", accountName)
+	return r.expiryRequeue(ctx, snowflakeAccount), nil
+}
+
+// validateAdoption checks that the spec names the account to adopt and the credentials needed to reset its password
+func validateAdoption(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	spec := snowflakeAccount.Spec
+	if !accountNamePattern.MatchString(spec.ExistingAccountName) {
+		return fmt.Errorf("existingAccountName %q is not a valid Snowflake identifier", spec.ExistingAccountName)
+	}
+	if spec.ExistingAdminName != "" && !unquotedIdentifierPattern.MatchString(spec.ExistingAdminName) {
+		return fmt.Errorf("existingAdminName %q is not a valid Snowflake identifier", spec.ExistingAdminName)
+	}
+	if spec.ResetAdminPassword && (spec.ExistingAdminName == "" || spec.AdminPasswordSecretRef == nil) {
+		return fmt.Errorf("resetAdminPassword requires existingAdminName and adminPasswordSecretRef with the current password")
+	}
+	return nil
+}
+
+// adoptSnowflakeAccount verifies the account exists and returns its details, along with the admin
+// credentials from the spec. The admin password is replaced with a generated one if requested.
+func (r *SnowflakeAccountReconciler) adoptSnowflakeAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, accountName string) (*accountDetails, error) {
+	creds, err := r.getSnowflakeCredentials(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	showCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	rows, err := r.snowflake().ShowAccounts(showCtx, creds, accountName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show account %s: %w", accountName, classifySnowflakeError(err))
+	}
+	if len(rows) == 0 {
+		return nil, errAdoptedAccountNotFound
+	}
+
+	details := accountDetailsFromRow(accountName, rows[0], creds)
+	details.adminName = account.Spec.ExistingAdminName
+	if !account.Spec.ResetAdminPassword {
+		// A referenced password stays in its own secret and is read from there when needed
+		return details, nil
+	}
+
+	currentPassword, err := r.getAdminPasswordFromSecretRef(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	newPassword := r.generator().Password()
+	if err := r.resetAdminPassword(ctx, creds, accountName, details.adminName, currentPassword, newPassword); err != nil {
+		return nil, err
+	}
+	details.adminPassword = newPassword
+	return details, nil
+}
+
+// resetAdminPassword logs in to the account as its admin and replaces the admin's password,
+// requiring it to be changed on the next interactive login like the password of a created account
+func (r *SnowflakeAccountReconciler) resetAdminPassword(ctx context.Context, orgCreds *snowflakeCredentials, accountName, adminName, currentPassword, newPassword string) error {
+	log := logf.FromContext(ctx)
+
+	creds, err := childAccountCredentials(orgCreds, accountName, adminName, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	// The statement holds the new password, so it is not logged
+	log.Info("Resetting admin password of adopted account", "account", creds.account, "adminName", adminName)
+	statement := fmt.Sprintf("ALTER USER %s SET PASSWORD = '%s' MUST_CHANGE_PASSWORD = TRUE",
+		userIdentifier(adminName), escapeSQLString(newPassword))
+	if err := r.snowflake().Exec(execCtx, creds, statement); err != nil {
+		return fmt.Errorf("failed to reset admin password: %w", classifySnowflakeError(err))
+	}
+	return nil
+}
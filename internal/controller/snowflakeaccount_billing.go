@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// ParseBillingEntities parses a comma-separated list of consumption billing entities, ignoring empty entries
+func ParseBillingEntities(value string) []string {
+	var entities []string
+	for _, entity := range strings.Split(value, ",") {
+		if entity = strings.TrimSpace(entity); entity != "" {
+			entities = append(entities, strings.ToUpper(entity))
+		}
+	}
+	return entities
+}
+
+// ValidateBillingEntityTag ensures the billing entity tag, if set, is a fully qualified tag name
+func ValidateBillingEntityTag(tag string) error {
+	if tag != "" && !qualifiedTagNamePattern.MatchString(tag) {
+		return fmt.Errorf("invalid billing entity tag %q: must be a fully qualified identifier (database.schema.tag_name)", tag)
+	}
+	return nil
+}
+
+// billingEntity returns the consumption billing entity of a new account in upper case, or "" if
+// Spec.BillingEntity is unset. An entity that is not in AllowedBillingEntities is rejected, so a typo
+// fails provisioning instead of billing the account to the wrong cost center.
+func (r *SnowflakeAccountReconciler) billingEntity(account *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	if account.Spec.BillingEntity == "" {
+		return "", nil
+	}
+
+	entity := strings.ToUpper(account.Spec.BillingEntity)
+	if len(r.AllowedBillingEntities) == 0 {
+		return "", fmt.Errorf("billing entity %s requires the operator to be configured with --allowed-billing-entities", entity)
+	}
+	if !slices.Contains(r.AllowedBillingEntities, entity) {
+		return "", fmt.Errorf("billing entity %s is not one of the allowed billing entities %s",
+			entity, strings.Join(r.AllowedBillingEntities, ", "))
+	}
+	return entity, nil
+}
+
+// buildBillingEntityClause renders the CONSUMPTION_BILLING_ENTITY property of CREATE ACCOUNT, or an empty
+// string if the account has no billing entity
+func buildBillingEntityClause(entity string) string {
+	if entity == "" {
+		return ""
+	}
+	return fmt.Sprintf("CONSUMPTION_BILLING_ENTITY = %s", entity)
+}
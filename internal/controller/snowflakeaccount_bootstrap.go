@@ -0,0 +1,610 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	// Embed the tz database, so timezones are validated even where the operator's image has none
+	_ "time/tzdata"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// conditionAccountParametersApplied indicates whether Spec.Timezone and Spec.DefaultCollation have been set on the account
+	conditionAccountParametersApplied = "AccountParametersApplied"
+	// conditionAdminDefaultsApplied indicates whether Spec.AdminDefaultRole and Spec.AdminDefaultWarehouse have
+	// been set on the admin user
+	conditionAdminDefaultsApplied = "AdminDefaultsApplied"
+	// conditionAuthenticationPolicyApplied indicates whether Spec.AuthenticationPolicy has been applied to the account
+	conditionAuthenticationPolicyApplied = "AuthenticationPolicyApplied"
+	// conditionMonitoringUserCreated indicates whether the user of Spec.MonitoringUser has been created in the account
+	conditionMonitoringUserCreated = "MonitoringUserCreated"
+	// conditionNetworkPolicyApplied indicates whether Spec.NetworkPolicy has been applied to the account
+	conditionNetworkPolicyApplied = "NetworkPolicyApplied"
+	// conditionOrgAdminGranted indicates whether the ORGADMIN role has been enabled in the account and granted
+	// to its admin for Spec.GrantOrgAdmin
+	conditionOrgAdminGranted = "OrgAdminGranted"
+	// conditionPasswordChanged indicates whether the initial admin password has been replaced for
+	// Spec.AutoCompletePasswordChange
+	conditionPasswordChanged = "PasswordChanged"
+	// conditionPostCreateSQLApplied indicates whether the statements of Spec.PostCreateSQL have been run in the account
+	conditionPostCreateSQLApplied = "PostCreateSQLApplied"
+	// conditionShareMounted indicates whether the share of Spec.ConsumerAccount has been mounted as a database
+	conditionShareMounted = "ShareMounted"
+	// conditionVerified indicates whether the admin credentials could log in to the account for Spec.VerifyLogin
+	conditionVerified = "Verified"
+
+	// bootstrapRetryInterval is how long to wait before retrying a failed post-provisioning step
+	bootstrapRetryInterval = time.Minute
+	// bootstrapTimeout bounds the statements run by a single post-provisioning step
+	bootstrapTimeout = 120 * time.Second
+)
+
+// bootstrapStep is an optional configuration step run in a created account, tracked by its own condition
+type bootstrapStep struct {
+	// conditionType is the condition reporting the outcome of the step
+	conditionType string
+	// validate checks the spec before connecting to the account; failures are not retried
+	validate func() error
+	// orgCredentials is set for steps that connect with the organization credentials rather than as the
+	// admin, so they are not held back while the admin must change the initial password
+	orgCredentials bool
+	// apply runs the step against the account
+	apply func(context.Context, *operatorv1alpha1.SnowflakeAccount) error
+	// appliedMessage is reported in the condition once the step succeeds
+	appliedMessage string
+}
+
+// bootstrapSteps returns the post-provisioning steps requested by the spec, in the order they are applied
+func (r *SnowflakeAccountReconciler) bootstrapSteps(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) []bootstrapStep {
+	spec := snowflakeAccount.Spec
+	steps := []bootstrapStep{}
+
+	// Change the password first so the other steps log in with the final one
+	if spec.AutoCompletePasswordChange {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionPasswordChanged,
+			validate:       func() error { return r.validatePasswordChange(snowflakeAccount) },
+			orgCredentials: true,
+			apply:          r.completePasswordChange,
+			appliedMessage: "Initial admin password replaced; the credentials secret holds the current password",
+		})
+	}
+
+	if spec.VerifyLogin {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionVerified,
+			apply:          r.verifyLogin,
+			appliedMessage: "Logged in to the account with the admin credentials from the credentials secret",
+		})
+	}
+
+	if spec.Timezone != "" || spec.DefaultCollation != "" {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionAccountParametersApplied,
+			validate:       func() error { return validateAccountParameters(spec) },
+			apply:          r.applyAccountParameters,
+			appliedMessage: fmt.Sprintf("Account parameters set: %s", strings.Join(accountParameterProperties(spec), " ")),
+		})
+	}
+
+	if policy := spec.AuthenticationPolicy; policy != nil {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionAuthenticationPolicyApplied,
+			apply:          r.applyAuthenticationPolicy,
+			appliedMessage: fmt.Sprintf("Authentication policy %s assigned to the admin user", policy.Name),
+		})
+	}
+
+	if policy := spec.NetworkPolicy; policy != nil {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionNetworkPolicyApplied,
+			validate:       func() error { return validateNetworkPolicy(policy) },
+			apply:          r.applyNetworkPolicy,
+			appliedMessage: fmt.Sprintf("Network policy %s set on the account", policy.Name),
+		})
+	}
+
+	if consumer := spec.ConsumerAccount; consumer != nil {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionShareMounted,
+			apply:          r.mountShare,
+			appliedMessage: fmt.Sprintf("Share %s mounted as database %s", consumer.Share, sharedDatabaseName(consumer)),
+		})
+	}
+
+	if spec.GrantOrgAdmin {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionOrgAdminGranted,
+			validate:       r.validateOrgAdminGrant,
+			apply:          r.grantOrgAdmin,
+			appliedMessage: "ORGADMIN enabled in the account and granted to the admin user; the admin can manage all accounts of the organization",
+		})
+	}
+
+	if user := spec.MonitoringUser; user != nil {
+		steps = append(steps, bootstrapStep{
+			conditionType: conditionMonitoringUserCreated,
+			validate: func() error {
+				return validateMonitoringUser(user, snowflakeAccount.Status.AdminName, snowflakeAccount.Spec.ExistingAdminName)
+			},
+			apply: r.createMonitoringUser,
+			appliedMessage: fmt.Sprintf("Monitoring user %s created with role %s; its credentials are stored in secret %s",
+				user.Name, monitoringRole(user), monitoringSecretName(snowflakeAccount.Status.AccountName)),
+		})
+	}
+
+	// Run custom SQL after the built-in setup so it can build on everything set up above
+	if statements := spec.PostCreateSQL; len(statements) > 0 {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionPostCreateSQLApplied,
+			apply:          r.applyPostCreateSQL,
+			appliedMessage: fmt.Sprintf("%d post-create statements executed", len(statements)),
+		})
+	}
+
+	// Set the admin defaults last, as the custom SQL may create the default warehouse
+	if spec.AdminDefaultRole != "" || spec.AdminDefaultWarehouse != "" {
+		steps = append(steps, bootstrapStep{
+			conditionType:  conditionAdminDefaultsApplied,
+			apply:          r.applyAdminDefaults,
+			appliedMessage: fmt.Sprintf("Admin user defaults set: %s", strings.Join(adminDefaultProperties(spec), " ")),
+		})
+	}
+
+	return steps
+}
+
+// reconcileAccountBootstrap applies the optional post-provisioning configuration of a created account.
+// Failures are reported in each step's condition rather than returned, so they never invalidate the
+// account itself; the returned duration is non-zero when a failed step should be retried.
+func (r *SnowflakeAccountReconciler) reconcileAccountBootstrap(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	var requeueAfter time.Duration
+	statusChanged := false
+
+	for _, step := range r.bootstrapSteps(snowflakeAccount) {
+		if bootstrapStepApplied(snowflakeAccount, step.conditionType) {
+			continue
+		}
+
+		condition := metav1.Condition{
+			Type:               step.conditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Applied",
+			Message:            step.appliedMessage,
+			ObservedGeneration: snowflakeAccount.Generation,
+		}
+
+		if step.validate != nil {
+			if err := step.validate(); err != nil {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = "InvalidSpec"
+				condition.Message = err.Error()
+			}
+		}
+
+		if condition.Status == metav1.ConditionTrue {
+			reason := adoptedBootstrapBlocker(snowflakeAccount)
+			if reason == "" && !step.orgCredentials {
+				reason = adminLoginBlocker(snowflakeAccount)
+			}
+			// Skipped steps are tried again on the next reconciliation without a retry of their own
+			if reason != "" {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = "Skipped"
+				condition.Message = reason
+			}
+		}
+
+		if condition.Status == metav1.ConditionTrue {
+			if err := step.apply(ctx, snowflakeAccount); err != nil {
+				log.Error(err, "Failed to apply post-provisioning step, will retry", "condition", step.conditionType)
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = "ApplyFailed"
+				condition.Message = err.Error()
+				requeueAfter = bootstrapRetryInterval
+			}
+		}
+
+		if meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition) {
+			statusChanged = true
+		}
+	}
+
+	if statusChanged {
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// bootstrapStepApplied reports whether a post-provisioning step already succeeded for the current spec
+func bootstrapStepApplied(snowflakeAccount *operatorv1alpha1.SnowflakeAccount, conditionType string) bool {
+	condition := meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionType)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == snowflakeAccount.Generation
+}
+
+// applyAuthenticationPolicy creates the authentication policy in the account and assigns it to the admin user
+func (r *SnowflakeAccountReconciler) applyAuthenticationPolicy(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, adminName, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	return r.execChildAccount(ctx, creds, authenticationPolicyStatements(snowflakeAccount.Spec.AuthenticationPolicy, adminName))
+}
+
+// applyNetworkPolicy creates the network policy in the account and activates it for the whole account
+func (r *SnowflakeAccountReconciler) applyNetworkPolicy(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	return r.execChildAccount(ctx, creds, networkPolicyStatements(snowflakeAccount.Spec.NetworkPolicy))
+}
+
+// mountShare creates the database from the consumed share and records it in the status
+func (r *SnowflakeAccountReconciler) mountShare(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	consumer := snowflakeAccount.Spec.ConsumerAccount
+	if err := r.execChildAccount(ctx, creds, shareStatements(consumer)); err != nil {
+		return err
+	}
+	snowflakeAccount.Status.SharedDatabase = sharedDatabaseName(consumer)
+	return nil
+}
+
+// validateOrgAdminGrant checks that the operator allows granting ORGADMIN, which gives the admin control over
+// every account of the organization
+func (r *SnowflakeAccountReconciler) validateOrgAdminGrant() error {
+	if !r.AllowOrgAdminGrants {
+		return fmt.Errorf("granting ORGADMIN is disabled on the operator; it must run with --allow-org-admin-grants")
+	}
+	return nil
+}
+
+// grantOrgAdmin enables the ORGADMIN role in the account from the organization account and grants it to the
+// account's admin user
+func (r *SnowflakeAccountReconciler) grantOrgAdmin(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	orgCreds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+	creds, adminName, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	accountName := snowflakeAccount.Status.AccountName
+	log.Error(nil, "Granting ORGADMIN to the admin of the account; anyone with its credentials can manage every account of the organization",
+		"accountName", accountName, "adminName", adminName)
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "GrantingOrgAdmin",
+		"Granting ORGADMIN to admin user %s of account %s; its credentials can manage every account of the organization", adminName, accountName)
+
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	// Enabling the role is an organization-level change, made from the organization account
+	statement := fmt.Sprintf("ALTER ACCOUNT %s SET IS_ORG_ADMIN = TRUE", accountName)
+	log.Info("Executing statement in organization account", "sql", statement)
+	if err := r.snowflake().Exec(execCtx, orgCreds, statement); err != nil {
+		return fmt.Errorf("failed to execute %q: %w", statement, classifySnowflakeError(err))
+	}
+
+	return r.execChildAccount(ctx, creds, []string{fmt.Sprintf("GRANT ROLE ORGADMIN TO USER %s", userIdentifier(adminName))})
+}
+
+// applyPostCreateSQL runs the statements of Spec.PostCreateSQL in the account
+func (r *SnowflakeAccountReconciler) applyPostCreateSQL(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	return r.runPostCreateSQL(ctx, creds, snowflakeAccount.Spec.PostCreateSQL)
+}
+
+// runPostCreateSQL runs statements in order within a single timeout, reporting the position of the first one that fails
+func (r *SnowflakeAccountReconciler) runPostCreateSQL(ctx context.Context, creds *snowflakeCredentials, statements []string) error {
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	for i, statement := range statements {
+		if err := r.execChildAccount(execCtx, creds, []string{statement}); err != nil {
+			return fmt.Errorf("post-create statement %d of %d failed: %w", i+1, len(statements), err)
+		}
+	}
+	return nil
+}
+
+// applyAdminDefaults sets the default role and warehouse of the admin user. Snowflake accepts a default
+// warehouse that does not exist, so the warehouse is looked up first.
+func (r *SnowflakeAccountReconciler) applyAdminDefaults(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, adminName, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	if warehouse := snowflakeAccount.Spec.AdminDefaultWarehouse; warehouse != "" {
+		if err := r.execChildAccount(ctx, creds, []string{fmt.Sprintf("DESCRIBE WAREHOUSE %s", warehouse)}); err != nil {
+			return fmt.Errorf("default warehouse %s is not available in the account: %w", warehouse, err)
+		}
+	}
+
+	return r.execChildAccount(ctx, creds, []string{
+		fmt.Sprintf("ALTER USER %s SET %s", userIdentifier(adminName), strings.Join(adminDefaultProperties(snowflakeAccount.Spec), " ")),
+	})
+}
+
+// adminDefaultProperties returns the user properties set on the admin for the requested defaults
+func adminDefaultProperties(spec operatorv1alpha1.SnowflakeAccountSpec) []string {
+	var properties []string
+	if spec.AdminDefaultRole != "" {
+		properties = append(properties, fmt.Sprintf("DEFAULT_ROLE = %s", spec.AdminDefaultRole))
+	}
+	if spec.AdminDefaultWarehouse != "" {
+		properties = append(properties, fmt.Sprintf("DEFAULT_WAREHOUSE = %s", spec.AdminDefaultWarehouse))
+	}
+	return properties
+}
+
+// applyAccountParameters sets the requested account parameters in the account
+func (r *SnowflakeAccountReconciler) applyAccountParameters(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	return r.execChildAccount(ctx, creds, []string{
+		fmt.Sprintf("ALTER ACCOUNT SET %s", strings.Join(accountParameterProperties(snowflakeAccount.Spec), " ")),
+	})
+}
+
+// accountParameterProperties returns the account parameters set for the requested timezone and collation
+func accountParameterProperties(spec operatorv1alpha1.SnowflakeAccountSpec) []string {
+	var properties []string
+	if spec.Timezone != "" {
+		properties = append(properties, fmt.Sprintf("TIMEZONE = '%s'", escapeSQLString(spec.Timezone)))
+	}
+	if spec.DefaultCollation != "" {
+		properties = append(properties, fmt.Sprintf("DEFAULT_DDL_COLLATION = '%s'", escapeSQLString(spec.DefaultCollation)))
+	}
+	return properties
+}
+
+// verifyLogin connects to the account with the admin credentials and runs a query that needs a working session
+func (r *SnowflakeAccountReconciler) verifyLogin(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	return r.execChildAccount(ctx, creds, []string{"SELECT CURRENT_ACCOUNT()"})
+}
+
+// adminLoginBlocker explains why the operator cannot log in to the account as the admin yet: Snowflake
+// rejects queries from a user with MUST_CHANGE_PASSWORD = TRUE until the password has been changed
+func adminLoginBlocker(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) string {
+	if meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionPasswordChangePending) {
+		return "The admin must change the initial password before the operator can log in as the admin; " +
+			"enable autoCompletePasswordChange to have the operator change it"
+	}
+	return ""
+}
+
+// adoptedBootstrapBlocker explains why post-provisioning steps are not run against an adopted account,
+// which was configured before it was adopted, unless Spec.BootstrapAdopted asks for them
+func adoptedBootstrapBlocker(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) string {
+	if snowflakeAccount.Spec.AdoptExisting && !snowflakeAccount.Spec.BootstrapAdopted {
+		return "Post-provisioning steps are not run against an adopted account; set bootstrapAdopted to run them"
+	}
+	return ""
+}
+
+// sharedDatabaseName returns the database a share is mounted as, defaulting to the share name
+func sharedDatabaseName(consumer *operatorv1alpha1.ConsumerAccount) string {
+	if consumer.Database != "" {
+		return consumer.Database
+	}
+	return consumer.Share[strings.LastIndex(consumer.Share, ".")+1:]
+}
+
+// shareStatements returns the idempotent statements that mount the share as a database
+func shareStatements(consumer *operatorv1alpha1.ConsumerAccount) []string {
+	return []string{
+		fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s FROM SHARE %s", sharedDatabaseName(consumer), consumer.Share),
+	}
+}
+
+// validateNetworkPolicy checks that every entry of the IP lists is an IPv4 address or CIDR block
+func validateNetworkPolicy(policy *operatorv1alpha1.NetworkPolicy) error {
+	if len(policy.AllowedIPList) == 0 {
+		return fmt.Errorf("network policy %s must allow at least one IP address or CIDR block", policy.Name)
+	}
+	for _, list := range [][]string{policy.AllowedIPList, policy.BlockedIPList} {
+		for _, entry := range list {
+			if !isIPv4OrCIDR(entry) {
+				return fmt.Errorf("network policy %s: %q is not a valid IPv4 address or CIDR block", policy.Name, entry)
+			}
+		}
+	}
+	return nil
+}
+
+// validateAccountParameters checks that the timezone is a name from the tz database
+func validateAccountParameters(spec operatorv1alpha1.SnowflakeAccountSpec) error {
+	if spec.Timezone == "" {
+		return nil
+	}
+	// LoadLocation also accepts "Local", the operator's own timezone, which Snowflake does not know
+	if _, err := time.LoadLocation(spec.Timezone); err != nil || spec.Timezone == "Local" {
+		return fmt.Errorf("timezone %q is not a name from the tz database, e.g. Europe/Berlin", spec.Timezone)
+	}
+	return nil
+}
+
+// isIPv4OrCIDR reports whether value is an IPv4 address or CIDR block, the formats Snowflake accepts
+func isIPv4OrCIDR(value string) bool {
+	if ip, _, err := net.ParseCIDR(value); err == nil {
+		return ip.To4() != nil
+	}
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil
+}
+
+// networkPolicyStatements returns the idempotent statements that create or update the network
+// policy and activate it for the account
+func networkPolicyStatements(policy *operatorv1alpha1.NetworkPolicy) []string {
+	lists := fmt.Sprintf("ALLOWED_IP_LIST = (%s) BLOCKED_IP_LIST = (%s)",
+		quoteSQLStrings(policy.AllowedIPList), quoteSQLStrings(policy.BlockedIPList))
+
+	return []string{
+		fmt.Sprintf("CREATE NETWORK POLICY IF NOT EXISTS %s %s", policy.Name, lists),
+		// Apply the lists separately so changes to an existing policy are picked up
+		fmt.Sprintf("ALTER NETWORK POLICY %s SET %s", policy.Name, lists),
+		fmt.Sprintf("ALTER ACCOUNT SET NETWORK_POLICY = %s", policy.Name),
+	}
+}
+
+// quoteSQLStrings renders values as a comma-separated list of escaped SQL string literals
+func quoteSQLStrings(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("'%s'", escapeSQLString(value)))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// authenticationPolicyStatements returns the idempotent statements that create or update the
+// authentication policy and assign it to the admin user
+func authenticationPolicyStatements(policy *operatorv1alpha1.AuthenticationPolicy, adminName string) []string {
+	database := policy.Database
+	if database == "" {
+		database = "SECURITY"
+	}
+	schema := policy.Schema
+	if schema == "" {
+		schema = "POLICIES"
+	}
+	policyName := fmt.Sprintf("%s.%s.%s", database, schema, policy.Name)
+
+	var properties []string
+	if policy.MFAEnrollment != "" {
+		properties = append(properties, fmt.Sprintf("MFA_ENROLLMENT = %s", policy.MFAEnrollment))
+	}
+	if len(policy.AuthenticationMethods) > 0 {
+		properties = append(properties, fmt.Sprintf("AUTHENTICATION_METHODS = (%s)", quoteSQLStrings(policy.AuthenticationMethods)))
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", database),
+		fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s.%s", database, schema),
+		fmt.Sprintf("CREATE AUTHENTICATION POLICY IF NOT EXISTS %s", policyName),
+	}
+	// Apply the properties separately so changes to an existing policy are picked up
+	if len(properties) > 0 {
+		statements = append(statements, fmt.Sprintf("ALTER AUTHENTICATION POLICY %s SET %s", policyName, strings.Join(properties, " ")))
+	}
+	// A user can only have one policy set, so clear any previous assignment first
+	return append(statements,
+		fmt.Sprintf("ALTER USER %s UNSET AUTHENTICATION POLICY", userIdentifier(adminName)),
+		fmt.Sprintf("ALTER USER %s SET AUTHENTICATION POLICY %s", userIdentifier(adminName), policyName),
+	)
+}
+
+// getChildAccountCredentials builds credentials for connecting to the provisioned account as its
+// admin user, using the name and password stored by the account's credentials sink
+func (r *SnowflakeAccountReconciler) getChildAccountCredentials(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (*snowflakeCredentials, string, error) {
+	orgCreds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sink, err := r.credentialsSink(snowflakeAccount)
+	if err != nil {
+		return nil, "", err
+	}
+	stored, err := sink.Read(ctx, snowflakeAccount)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read stored credentials: %w", err)
+	}
+	if stored == nil {
+		return nil, "", fmt.Errorf("credentials for account not found in the %s credentials sink", credentialsSinkType(snowflakeAccount))
+	}
+
+	accountName := stored.AccountName
+	adminName := stored.AdminName
+	adminPassword := stored.AdminPassword
+	// A referenced password is not copied into the credentials sink
+	if adminPassword == "" && snowflakeAccount.Spec.AdminPasswordSecretRef != nil {
+		adminPassword, err = r.getAdminPasswordFromSecretRef(ctx, snowflakeAccount)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if accountName == "" || adminName == "" || adminPassword == "" {
+		return nil, "", fmt.Errorf("stored credentials of account %s do not contain the admin credentials", accountName)
+	}
+
+	creds, err := childAccountCredentials(orgCreds, accountName, adminName, adminPassword)
+	if err != nil {
+		return nil, "", err
+	}
+	return creds, adminName, nil
+}
+
+// childAccountCredentials builds credentials for connecting to an account of the organization as its admin
+func childAccountCredentials(orgCreds *snowflakeCredentials, accountName, adminName, adminPassword string) (*snowflakeCredentials, error) {
+	creds := &snowflakeCredentials{
+		username: adminName,
+		password: adminPassword,
+		account:  accountIdentifier(accountName, orgCreds),
+		role:     "ACCOUNTADMIN",
+
+		childAccount: true,
+	}
+	if orgCreds.host != "" {
+		accountURL, err := url.Parse(buildAccountURL(accountName, orgCreds))
+		if err != nil {
+			return nil, err
+		}
+		creds.host = accountURL.Host
+	}
+	return creds, nil
+}
+
+// execChildAccount runs statements in order against a provisioned account, stopping at the first failure
+func (r *SnowflakeAccountReconciler) execChildAccount(ctx context.Context, creds *snowflakeCredentials, statements []string) error {
+	log := logf.FromContext(ctx)
+
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	for _, statement := range statements {
+		log.Info("Executing statement in account", "account", creds.account, "sql", statement)
+		if err := r.snowflake().Exec(execCtx, creds, statement); err != nil {
+			return fmt.Errorf("failed to execute %q: %w", statement, classifySnowflakeError(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Authentication policy bootstrap", func() {
+	It("should create the policy and assign it to the admin user", func() {
+		statements := authenticationPolicyStatements(&operatorv1alpha1.AuthenticationPolicy{
+			Name:                  "ADMIN_MFA",
+			MFAEnrollment:         "REQUIRED",
+			AuthenticationMethods: []string{"PASSWORD", "SAML"},
+		}, "admin_abc")
+
+		Expect(statements).To(Equal([]string{
+			"CREATE DATABASE IF NOT EXISTS SECURITY",
+			"CREATE SCHEMA IF NOT EXISTS SECURITY.POLICIES",
+			"CREATE AUTHENTICATION POLICY IF NOT EXISTS SECURITY.POLICIES.ADMIN_MFA",
+			"ALTER AUTHENTICATION POLICY SECURITY.POLICIES.ADMIN_MFA SET MFA_ENROLLMENT = REQUIRED AUTHENTICATION_METHODS = ('PASSWORD', 'SAML')",
+			`ALTER USER "ADMIN_ABC" UNSET AUTHENTICATION POLICY`,
+			`ALTER USER "ADMIN_ABC" SET AUTHENTICATION POLICY SECURITY.POLICIES.ADMIN_MFA`,
+		}))
+	})
+
+	It("should skip ALTER AUTHENTICATION POLICY when no properties are set", func() {
+		statements := authenticationPolicyStatements(&operatorv1alpha1.AuthenticationPolicy{
+			Name:     "ADMIN_MFA",
+			Database: "GOV",
+			Schema:   "AUTH",
+		}, "admin_abc")
+
+		Expect(statements).To(ContainElement("CREATE AUTHENTICATION POLICY IF NOT EXISTS GOV.AUTH.ADMIN_MFA"))
+		Expect(statements).NotTo(ContainElement(HavePrefix("ALTER AUTHENTICATION POLICY")))
+	})
+})
+
+var _ = Describe("Network policy bootstrap", func() {
+	It("should validate IP addresses and CIDR blocks", func() {
+		policy := &operatorv1alpha1.NetworkPolicy{
+			Name:          "CORP_ONLY",
+			AllowedIPList: []string{"10.0.0.0/8", "192.168.1.10"},
+			BlockedIPList: []string{"10.1.2.3"},
+		}
+		Expect(validateNetworkPolicy(policy)).To(Succeed())
+
+		policy.BlockedIPList = []string{"10.0.0.0/33"}
+		Expect(validateNetworkPolicy(policy)).NotTo(Succeed())
+
+		policy.BlockedIPList = []string{"2001:db8::/32"}
+		Expect(validateNetworkPolicy(policy)).NotTo(Succeed())
+
+		policy.BlockedIPList = nil
+		policy.AllowedIPList = nil
+		Expect(validateNetworkPolicy(policy)).NotTo(Succeed())
+	})
+
+	It("should create the policy and set it on the account", func() {
+		Expect(networkPolicyStatements(&operatorv1alpha1.NetworkPolicy{
+			Name:          "CORP_ONLY",
+			AllowedIPList: []string{"10.0.0.0/8"},
+		})).To(Equal([]string{
+			"CREATE NETWORK POLICY IF NOT EXISTS CORP_ONLY ALLOWED_IP_LIST = ('10.0.0.0/8') BLOCKED_IP_LIST = ()",
+			"ALTER NETWORK POLICY CORP_ONLY SET ALLOWED_IP_LIST = ('10.0.0.0/8') BLOCKED_IP_LIST = ()",
+			"ALTER ACCOUNT SET NETWORK_POLICY = CORP_ONLY",
+		}))
+	})
+})
+
+var _ = Describe("Account parameters bootstrap", func() {
+	It("should only accept timezones from the tz database", func() {
+		Expect(validateAccountParameters(operatorv1alpha1.SnowflakeAccountSpec{Timezone: "Europe/Berlin"})).To(Succeed())
+		Expect(validateAccountParameters(operatorv1alpha1.SnowflakeAccountSpec{Timezone: "UTC"})).To(Succeed())
+		Expect(validateAccountParameters(operatorv1alpha1.SnowflakeAccountSpec{DefaultCollation: "de"})).To(Succeed())
+		Expect(validateAccountParameters(operatorv1alpha1.SnowflakeAccountSpec{Timezone: "Europe/Atlantis"})).NotTo(Succeed())
+		Expect(validateAccountParameters(operatorv1alpha1.SnowflakeAccountSpec{Timezone: "Local"})).NotTo(Succeed())
+	})
+
+	It("should set the timezone and the default collation", func() {
+		Expect(accountParameterProperties(operatorv1alpha1.SnowflakeAccountSpec{
+			Timezone:         "Europe/Berlin",
+			DefaultCollation: "de_DE",
+		})).To(Equal([]string{"TIMEZONE = 'Europe/Berlin'", "DEFAULT_DDL_COLLATION = 'de_DE'"}))
+		Expect(accountParameterProperties(operatorv1alpha1.SnowflakeAccountSpec{DefaultCollation: "en-ci"})).To(Equal([]string{
+			"DEFAULT_DDL_COLLATION = 'en-ci'",
+		}))
+	})
+})
+
+var _ = Describe("Share consumer bootstrap", func() {
+	It("should mount the share as the configured database", func() {
+		Expect(shareStatements(&operatorv1alpha1.ConsumerAccount{
+			Share:    "MYORG.PROVIDER.SALES_SHARE",
+			Database: "SALES",
+		})).To(Equal([]string{
+			"CREATE DATABASE IF NOT EXISTS SALES FROM SHARE MYORG.PROVIDER.SALES_SHARE",
+		}))
+	})
+
+	It("should default the database to the share name", func() {
+		Expect(sharedDatabaseName(&operatorv1alpha1.ConsumerAccount{Share: "PROVIDER.SALES_SHARE"})).To(Equal("SALES_SHARE"))
+	})
+})
+
+var _ = Describe("Post-create SQL bootstrap", func() {
+	It("should stop at the first failing statement and report it", func() {
+		executor := &fakeSnowflakeExecutor{
+			errFor: func(statement string) error {
+				if strings.HasPrefix(statement, "GRANT") {
+					return errors.New("insufficient privileges")
+				}
+				return nil
+			},
+		}
+		reconciler := &SnowflakeAccountReconciler{Executor: executor}
+		creds := &snowflakeCredentials{account: "myorg-sfacct"}
+
+		statements := []string{
+			"CREATE ROLE IF NOT EXISTS ANALYST",
+			"GRANT ROLE ANALYST TO ROLE SYSADMIN",
+			"CREATE DATABASE IF NOT EXISTS ANALYTICS",
+		}
+		err := reconciler.runPostCreateSQL(context.Background(), creds, statements)
+		Expect(err).To(MatchError(ContainSubstring("post-create statement 2 of 3 failed")))
+		Expect(err).To(MatchError(ContainSubstring("GRANT ROLE ANALYST TO ROLE SYSADMIN")))
+		Expect(executor.statements).To(Equal(statements[:2]))
+	})
+})
+
+var _ = Describe("Login verification bootstrap", func() {
+	It("should wait for the initial password to be changed", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{}
+		Expect(adminLoginBlocker(account)).To(BeEmpty())
+
+		meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+			Type:   conditionPasswordChangePending,
+			Status: metav1.ConditionTrue,
+			Reason: "MustChangePassword",
+		})
+		Expect(adminLoginBlocker(account)).To(ContainSubstring("autoCompletePasswordChange"))
+
+		meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+			Type:   conditionPasswordChangePending,
+			Status: metav1.ConditionFalse,
+			Reason: "PasswordChanged",
+		})
+		Expect(adminLoginBlocker(account)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// snowflakeConnectionCache caches one *sql.DB per Snowflake organization so that
+// accounts targeting different orgs never share a connection pool. Pools of child accounts are not cached.
+type snowflakeConnectionCache struct {
+	mu    sync.Mutex
+	conns map[string]*cachedConnection
+}
+
+// connectionPoolSettings bound the connections of each cached pool; zero keeps the database/sql default
+type connectionPoolSettings struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+}
+
+// apply sets the bounds on a newly opened pool
+func (p connectionPoolSettings) apply(db *sql.DB) {
+	if p.maxOpenConns > 0 {
+		db.SetMaxOpenConns(p.maxOpenConns)
+	}
+	if p.maxIdleConns > 0 {
+		db.SetMaxIdleConns(p.maxIdleConns)
+	}
+	if p.connMaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.connMaxLifetime)
+	}
+}
+
+// ValidateConnectionPool checks the operator's connection pool settings; zero keeps the database/sql default
+func ValidateConnectionPool(maxOpenConns, maxIdleConns int, connMaxLifetime time.Duration) error {
+	if maxOpenConns < 0 || maxIdleConns < 0 || connMaxLifetime < 0 {
+		return fmt.Errorf("connection pool settings must not be negative")
+	}
+	if maxOpenConns > 0 && maxIdleConns > maxOpenConns {
+		return fmt.Errorf("%d idle connections exceed the maximum of %d open connections", maxIdleConns, maxOpenConns)
+	}
+	return nil
+}
+
+// connectionIdleTimeout is how long a cached pool may go unused before it is closed, so pools of
+// organizations or credential profiles that are no longer used do not hold connections forever
+const connectionIdleTimeout = 30 * time.Minute
+
+// cachedConnection is a pooled connection along with a fingerprint of the credentials used to open it
+type cachedConnection struct {
+	db          *sql.DB
+	fingerprint string
+	// lastUsed is when the pool was last returned from the cache
+	lastUsed time.Time
+}
+
+// cacheKey identifies the credential profile, organization, principal and connection parameters the
+// credentials connect with
+func (c *snowflakeCredentials) cacheKey() string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", c.profile, c.account, c.region, c.host, c.username, c.role, c.dsnParams.Encode())
+}
+
+// fingerprint identifies the full set of credentials, including the secret material,
+// so that a changed password results in a new connection
+func (c *snowflakeCredentials) fingerprint() string {
+	sum := sha256.Sum256([]byte(c.cacheKey() + "|" + c.password + "|" + c.oauthToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns a cached connection for the credentials, opening a new one bounded by pool if none exists
+// or if the credentials changed since the connection was opened. Pools unused for connectionIdleTimeout are
+// closed. Connecting and verifying reloaded credentials happen outside the lock, so a slow organization
+// does not hold up reconciles of the others.
+func (c *snowflakeConnectionCache) get(ctx context.Context, creds *snowflakeCredentials, pool connectionPoolSettings) (*sql.DB, error) {
+	key := creds.cacheKey()
+	fingerprint := creds.fingerprint()
+	now := time.Now()
+
+	c.mu.Lock()
+	if c.conns == nil {
+		c.conns = map[string]*cachedConnection{}
+	}
+	idle := c.evictIdle(now)
+	cached, ok := c.conns[key]
+	if ok && cached.fingerprint == fingerprint {
+		cached.lastUsed = now
+		c.mu.Unlock()
+		closeAll(idle)
+		return cached.db, nil
+	}
+	rotated := ok
+	c.mu.Unlock()
+	closeAll(idle)
+
+	db, err := connectToSnowflake(creds)
+	if err != nil {
+		return nil, err
+	}
+	pool.apply(db)
+
+	// Confirm the reloaded credentials work before replacing the stale pool, e.g. after a password rotation
+	if rotated {
+		log := logf.FromContext(ctx)
+		log.Info("Organization credentials changed, reloaded connection", "orgAccount", creds.account, "username", creds.username)
+		if err := db.PingContext(ctx); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to connect with reloaded organization credentials: %w",
+				classifySnowflakeError(creds.redactError(err)))
+		}
+		log.Info("Reloaded organization credentials verified", "orgAccount", creds.account)
+	}
+
+	c.mu.Lock()
+	var replaced *sql.DB
+	if cached, ok := c.conns[key]; ok {
+		// Another reconcile opened a pool with the same credentials in the meantime
+		if cached.fingerprint == fingerprint {
+			cached.lastUsed = now
+			c.mu.Unlock()
+			_ = db.Close()
+			return cached.db, nil
+		}
+		replaced = cached.db
+	}
+	c.conns[key] = &cachedConnection{db: db, fingerprint: fingerprint, lastUsed: now}
+	c.mu.Unlock()
+
+	// Only close the stale pool once it can no longer be handed out
+	if replaced != nil {
+		_ = replaced.Close()
+	}
+	return db, nil
+}
+
+// evictIdle removes the pools unused for connectionIdleTimeout from the cache and returns them to be closed
+// once the lock is released. The caller must hold the lock.
+func (c *snowflakeConnectionCache) evictIdle(now time.Time) []*sql.DB {
+	var idle []*sql.DB
+	for key, cached := range c.conns {
+		if now.Sub(cached.lastUsed) > connectionIdleTimeout {
+			idle = append(idle, cached.db)
+			delete(c.conns, key)
+		}
+	}
+	return idle
+}
+
+// closeAll closes the given pools
+func closeAll(pools []*sql.DB) {
+	for _, db := range pools {
+		_ = db.Close()
+	}
+}
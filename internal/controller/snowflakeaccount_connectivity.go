@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// connectivityStatus holds the outcome of the latest organization connectivity check
+type connectivityStatus struct {
+	mu      sync.RWMutex
+	checked bool
+	err     error
+}
+
+// ValidateConnectivityReadiness rejects reporting readiness by the connectivity checks when they are disabled,
+// which would leave the pod never ready
+func ValidateConnectivityReadiness(readiness bool, interval time.Duration) error {
+	if readiness && interval <= 0 {
+		return errors.New("connectivity readiness requires a positive --connectivity-check-interval")
+	}
+	return nil
+}
+
+// connectivityChecker runs the connectivity check on every replica, not only the leader, so each pod
+// reports its own connectivity
+type connectivityChecker struct {
+	reconciler *SnowflakeAccountReconciler
+}
+
+// Start checks the connectivity immediately and then every ConnectivityCheckInterval until ctx is cancelled.
+// There is nothing to check without organization credentials in the operator's environment, e.g. when all
+// accounts use credential profiles.
+func (c *connectivityChecker) Start(ctx context.Context) error {
+	r := c.reconciler
+	log := logf.FromContext(ctx).WithName("connectivity-check")
+	ctx = logf.IntoContext(ctx, log)
+
+	if _, err := getSnowflakeCredentialsFromEnv(); err != nil {
+		log.Info("Not checking Snowflake connectivity without organization credentials in the environment", "reason", err.Error())
+		return nil
+	}
+
+	ticker := time.NewTicker(r.ConnectivityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		r.recordConnectivity(ctx, r.checkConnectivity(ctx))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (c *connectivityChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// checkConnectivity runs a trivial query over the cached connection to the organization configured in
+// the operator's environment, within ConnectivityCheckTimeout
+func (r *SnowflakeAccountReconciler) checkConnectivity(ctx context.Context) error {
+	creds, err := getSnowflakeCredentialsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if r.ConnectivityCheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.ConnectivityCheckTimeout)
+		defer cancel()
+	}
+
+	if err := r.snowflake().Exec(ctx, creds, "SELECT 1"); err != nil {
+		return fmt.Errorf("failed to reach Snowflake account %s: %w", creds.account, classifySnowflakeError(err))
+	}
+	return nil
+}
+
+// recordConnectivity stores the outcome of a connectivity check in the speck_snowflake_reachable metric,
+// logging when it changes. Readiness only depends on it with ConnectivityReadiness: otherwise an unreachable
+// Snowflake only fails the reconciles that need it, while the webhooks and other accounts keep being served.
+func (r *SnowflakeAccountReconciler) recordConnectivity(ctx context.Context, err error) {
+	log := logf.FromContext(ctx)
+
+	r.connectivity.mu.Lock()
+	defer r.connectivity.mu.Unlock()
+
+	wasHealthy := r.connectivity.checked && r.connectivity.err == nil
+	switch {
+	case err != nil && (wasHealthy || !r.connectivity.checked):
+		log.Error(err, "Snowflake is unreachable")
+	case err == nil && !wasHealthy:
+		log.Info("Snowflake is reachable")
+	}
+	r.connectivity.checked = true
+	r.connectivity.err = err
+
+	if err != nil {
+		snowflakeReachable.Set(0)
+	} else {
+		snowflakeReachable.Set(1)
+	}
+}
+
+// snowflakeReadyzCheck is a healthz.Checker failing until a connectivity check has succeeded and
+// whenever the latest one failed
+func (r *SnowflakeAccountReconciler) snowflakeReadyzCheck(_ *http.Request) error {
+	if _, err := getSnowflakeCredentialsFromEnv(); err != nil {
+		return err
+	}
+
+	r.connectivity.mu.RLock()
+	defer r.connectivity.mu.RUnlock()
+
+	if !r.connectivity.checked {
+		return errors.New("snowflake connectivity has not been checked yet")
+	}
+	return r.connectivity.err
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Snowflake connectivity check", func() {
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		for key, value := range map[string]string{
+			"SNOWFLAKE_ORG_USERNAME": "org_admin",
+			"SNOWFLAKE_ORG_PASSWORD": "org-password",
+			"SNOWFLAKE_ORG_ACCOUNT":  "myorg-admin",
+		} {
+			GinkgoT().Setenv(key, value)
+		}
+	})
+
+	It("should report whether Snowflake is reachable in a metric", func() {
+		executor := &fakeSnowflakeExecutor{}
+		reconciler := &SnowflakeAccountReconciler{Executor: executor}
+
+		reconciler.recordConnectivity(ctx, reconciler.checkConnectivity(ctx))
+		Expect(testutil.ToFloat64(snowflakeReachable)).To(Equal(1.0))
+		Expect(executor.statements).To(Equal([]string{"SELECT 1"}))
+
+		executor.err = errors.New("connection refused")
+		err := reconciler.checkConnectivity(ctx)
+		Expect(err).To(MatchError(ContainSubstring("myorg-admin")))
+		reconciler.recordConnectivity(ctx, err)
+		Expect(testutil.ToFloat64(snowflakeReachable)).To(Equal(0.0))
+
+		executor.err = nil
+		reconciler.recordConnectivity(ctx, reconciler.checkConnectivity(ctx))
+		Expect(testutil.ToFloat64(snowflakeReachable)).To(Equal(1.0))
+	})
+
+	It("should report ready only while Snowflake is reachable", func() {
+		executor := &fakeSnowflakeExecutor{}
+		reconciler := &SnowflakeAccountReconciler{Executor: executor, ConnectivityReadiness: true}
+		Expect(reconciler.snowflakeReadyzCheck(nil)).To(MatchError(ContainSubstring("not been checked")))
+
+		reconciler.recordConnectivity(ctx, reconciler.checkConnectivity(ctx))
+		Expect(reconciler.snowflakeReadyzCheck(nil)).To(Succeed())
+
+		executor.err = errors.New("connection refused")
+		reconciler.recordConnectivity(ctx, reconciler.checkConnectivity(ctx))
+		Expect(reconciler.snowflakeReadyzCheck(nil)).To(MatchError(ContainSubstring("myorg-admin")))
+
+		executor.err = nil
+		reconciler.recordConnectivity(ctx, reconciler.checkConnectivity(ctx))
+		Expect(reconciler.snowflakeReadyzCheck(nil)).To(Succeed())
+	})
+
+	It("should require the connectivity check for readiness", func() {
+		Expect(ValidateConnectivityReadiness(false, 0)).To(Succeed())
+		Expect(ValidateConnectivityReadiness(true, time.Minute)).To(Succeed())
+		Expect(ValidateConnectivityReadiness(true, 0)).NotTo(Succeed())
+	})
+
+	It("should report not ready without organization credentials", func() {
+		GinkgoT().Setenv("SNOWFLAKE_ORG_ACCOUNT", "")
+		reconciler := &SnowflakeAccountReconciler{Executor: &fakeSnowflakeExecutor{}, ConnectivityReadiness: true}
+		Expect(reconciler.snowflakeReadyzCheck(nil)).To(MatchError(ContainSubstring("SNOWFLAKE_ORG_ACCOUNT")))
+	})
+
+	It("should not check without organization credentials in the environment", func() {
+		GinkgoT().Setenv("SNOWFLAKE_ORG_ACCOUNT", "")
+		executor := &fakeSnowflakeExecutor{}
+		checker := &connectivityChecker{reconciler: &SnowflakeAccountReconciler{Executor: executor, ConnectivityCheckInterval: time.Minute}}
+
+		Expect(checker.Start(ctx)).To(Succeed())
+		Expect(executor.statements).To(BeEmpty())
+	})
+})
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// connInfoKeys are the credentials secret keys moved to the conninfo ConfigMap with Spec.SplitCredentials.
+// The account name stays in the secret as well, so the credentials remain usable on their own.
+var connInfoKeys = []string{"accountURL", "region", "edition", "email", "accountLocator"}
+
+// connInfoConfigMapName returns the name of the conninfo ConfigMap for a Snowflake account:
+// {accountName}-conninfo (lowercase for Kubernetes naming requirements)
+func connInfoConfigMapName(accountName string) string {
+	return fmt.Sprintf("%s-conninfo", strings.ToLower(accountName))
+}
+
+// splitConnInfo moves the non-sensitive connection details out of the credentials secret data and
+// returns them as ConfigMap data, along with the account name
+func splitConnInfo(secretData map[string][]byte) map[string]string {
+	data := map[string]string{
+		"accountName": string(secretData["accountName"]),
+	}
+	for _, key := range connInfoKeys {
+		if value, ok := secretData[key]; ok {
+			data[key] = string(value)
+			delete(secretData, key)
+		}
+	}
+	return data
+}
+
+// writeConnInfoConfigMap applies the conninfo ConfigMap of the account server-side, so an earlier
+// reconcile's ConfigMap is updated in place. It is labeled and owned like the credentials secret.
+func (r *SnowflakeAccountReconciler) writeConnInfoConfigMap(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, data map[string]string) error {
+	log := logf.FromContext(ctx)
+
+	name := connInfoConfigMapName(data["accountName"])
+	namespace := credentialsSecretNamespace(account)
+	configMap := corev1ac.ConfigMap(name, namespace).
+		WithLabels(r.credentialsSecretLabels(account)).
+		WithAnnotations(credentialsSecretAnnotations(account)).
+		WithOwnerReferences(ownerReferenceConfigurations(credentialsOwnerReferences(account))...).
+		WithData(data)
+
+	if err := r.Apply(ctx, configMap, client.FieldOwner(fieldManager)); err != nil {
+		return fmt.Errorf("failed to write conninfo ConfigMap: %w", err)
+	}
+
+	log.Info("Stored connection details", "configMapName", name, "namespace", namespace)
+	return nil
+}
+
+// getConnInfoConfigMap returns the conninfo ConfigMap for the account, or nil if none exists
+func (r *SnowflakeAccountReconciler) getConnInfoConfigMap(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*corev1.ConfigMap, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMaps,
+		client.InNamespace(credentialsSecretNamespace(account)),
+		client.MatchingLabels(r.discoveryLabels(account)),
+	); err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	if len(configMaps.Items) == 0 {
+		return nil, nil
+	}
+	return &configMaps.Items[0], nil
+}
+
+// updateConnInfoAccount updates the account name and URL stored in the conninfo ConfigMap, if any
+func (r *SnowflakeAccountReconciler) updateConnInfoAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, accountName, accountURL string) error {
+	configMap, err := r.getConnInfoConfigMap(ctx, account)
+	if err != nil || configMap == nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data["accountName"] = accountName
+	configMap.Data["accountURL"] = accountURL
+	if err := r.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update conninfo ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// deleteConnInfoConfigMap deletes the conninfo ConfigMap of the account if it exists
+func (r *SnowflakeAccountReconciler) deleteConnInfoConfigMap(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	configMap, err := r.getConnInfoConfigMap(ctx, account)
+	if err != nil || configMap == nil {
+		return err
+	}
+
+	if err := r.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete conninfo ConfigMap: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Deleted conninfo ConfigMap", "configMapName", configMap.Name, "namespace", configMap.Namespace)
+	return nil
+}
+
+// withConnInfo fills the connection details of secret data that were split off into the conninfo ConfigMap
+func (r *SnowflakeAccountReconciler) withConnInfo(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, secretData map[string][]byte) (map[string][]byte, error) {
+	configMap, err := r.getConnInfoConfigMap(ctx, account)
+	if err != nil || configMap == nil {
+		return secretData, err
+	}
+
+	data := make(map[string][]byte, len(secretData)+len(connInfoKeys))
+	for key, value := range secretData {
+		data[key] = value
+	}
+	for _, key := range connInfoKeys {
+		if _, ok := data[key]; !ok && configMap.Data[key] != "" {
+			data[key] = []byte(configMap.Data[key])
+		}
+	}
+	return data, nil
+}
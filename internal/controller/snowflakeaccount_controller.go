@@ -19,13 +19,26 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
 )
@@ -35,12 +48,224 @@ type SnowflakeAccountReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Clock  clock.PassiveClock
+
+	// Recorder emits Kubernetes events for the resource. If nil, no events are emitted.
+	Recorder record.EventRecorder
+
+	// MaxRequeueInterval caps how long to wait before re-checking a created account,
+	// so spec edits are picked up promptly for long-lived accounts. Zero disables the cap.
+	MaxRequeueInterval time.Duration
+
+	// RequeueJitter is the largest fraction by which a duration re-check is randomly delayed, so that
+	// accounts created together do not all re-check at once. Zero disables the jitter.
+	RequeueJitter float64
+
+	// MaxAccountDuration is the longest Spec.Duration accepted. Zero disables the ceiling.
+	MaxAccountDuration time.Duration
+
+	// MaxAccountsPerNamespace limits how many created accounts a namespace may have. Zero means unlimited.
+	MaxAccountsPerNamespace int
+
+	// DefaultEdition is the edition of accounts created without Spec.Edition. If empty,
+	// operatorv1alpha1.DefaultEdition is used.
+	DefaultEdition operatorv1alpha1.Edition
+
+	// DefaultRegion is the region of accounts created with the fixed region selection strategy without
+	// Spec.Region. If empty, operatorv1alpha1.DefaultRegion is used.
+	DefaultRegion string
+
+	// AllowedRegions is the pool of regions used by the round-robin and random region selection strategies
+	AllowedRegions []string
+
+	// AllowedBillingEntities are the consumption billing entities Spec.BillingEntity may name. An account
+	// with a billing entity is rejected when it is not listed, or when none are configured.
+	AllowedBillingEntities []string
+
+	// AllowedSecretNamespaces are the namespaces other than its own that a resource may write its secrets to
+	// through Spec.SecretNamespace. If empty, secrets can only be written to the namespace of the resource.
+	AllowedSecretNamespaces []string
+
+	// BillingEntityTag is the fully qualified tag set to the billing entity of accounts created with one.
+	// Empty disables the tag.
+	BillingEntityTag string
+
+	// CredentialProfiles maps credential profile names to secrets holding organization credentials,
+	// selected by Spec.CredentialProfile
+	CredentialProfiles map[string]types.NamespacedName
+
+	// DiscoveryLabelKeys are labels of a SnowflakeAccount copied onto its credentials secret and required
+	// to match, in addition to the resource name, when looking up a secret not yet recorded in the status
+	DiscoveryLabelKeys []string
+
+	// KubernetesTagSchema is the database.schema holding the K8S_NAMESPACE, K8S_NAME and K8S_UID tags
+	// that are applied to every new account. Empty disables automatic tagging.
+	KubernetesTagSchema string
+
+	// FinalizerName is the finalizer added to resources, so several operator instances can manage
+	// accounts in the same cluster during a migration. If empty, DefaultFinalizerName is used.
+	FinalizerName string
+
+	// AllowOrgAdminGrants lets Spec.GrantOrgAdmin hand ORGADMIN to the admin of an account. Without it the
+	// OrgAdminGranted condition reports the request as rejected.
+	AllowOrgAdminGrants bool
+
+	// DisableAccountDeletion stops the operator from ever dropping Snowflake accounts: deleted resources
+	// orphan their account as with the Retain deletion policy, and expired durations are only reported
+	DisableAccountDeletion bool
+
+	// MinGracePeriodDays and MaxGracePeriodDays bound the grace period accounts are dropped with, so a
+	// dropped account stays restorable as long as policy requires; a minimum also rules out immediate
+	// drops. Zero disables a bound.
+	MinGracePeriodDays int
+	MaxGracePeriodDays int
+
+	// MaxFinalizeAttempts is how many consecutive failures finalizing a deleted resource may reach before it
+	// gets the FinalizeFailed condition and is retried on a slower cadence. Zero never gives up.
+	MaxFinalizeAttempts int
+
+	// PasswordChangeCheckInterval is how often the operator logs in to accounts whose admin has not yet
+	// changed the initial password, to clear PasswordChangePending once it was. Zero disables the check.
+	PasswordChangeCheckInterval time.Duration
+
+	// CreditSyncInterval is how often the credits used by accounts with Spec.TrackCredits are synced into
+	// their status. Zero disables credit tracking.
+	CreditSyncInterval time.Duration
+
+	// ExpirySweepInterval is how often all accounts are listed to re-enqueue those past their expiry,
+	// so durations are enforced even when a scheduled requeue was lost. Zero disables the sweep.
+	ExpirySweepInterval time.Duration
+
+	// OrphanAuditInterval is how often the accounts of the organizations are checked for operator-created
+	// accounts that no resource owns, which are logged and counted in a metric. Zero disables the audit,
+	// which also requires KubernetesTagSchema to recognize the accounts.
+	OrphanAuditInterval time.Duration
+
+	// ConnectivityCheckInterval is how often the organization connection from the operator's environment
+	// is checked; the outcome is reported in the speck_snowflake_reachable metric. Zero disables the check.
+	ConnectivityCheckInterval time.Duration
+
+	// ConnectivityCheckTimeout bounds a single connectivity check. Zero means no timeout.
+	ConnectivityCheckTimeout time.Duration
+
+	// ConnectivityReadiness makes the pod report not ready while the latest connectivity check failed.
+	// It is opt-in as an unreachable Snowflake would also take the webhooks out of service.
+	ConnectivityReadiness bool
+
+	// SnowflakeMaxOpenConns, SnowflakeMaxIdleConns and SnowflakeConnMaxLifetime bound each cached
+	// connection pool, of which there is one per set of credentials. Zero keeps the database/sql default:
+	// unlimited open connections, 2 idle connections and connections reused indefinitely.
+	SnowflakeMaxOpenConns    int
+	SnowflakeMaxIdleConns    int
+	SnowflakeConnMaxLifetime time.Duration
+
+	// ApplicationName identifies the operator's Snowflake connections, and so its statements in
+	// QUERY_HISTORY.CLIENT_APPLICATION_ID. If empty, gosnowflake's default is used.
+	ApplicationName string
+
+	// CreateAccountTemplate renders the CREATE ACCOUNT statement from createAccountData. If nil,
+	// DefaultCreateAccountTemplate is used.
+	CreateAccountTemplate *template.Template
+
+	// NameCollisionRetries is how many times CREATE ACCOUNT is retried right away with a newly generated
+	// name when the generated name is already taken. Chosen names are never replaced.
+	NameCollisionRetries int
+
+	// Generator produces account names, admin usernames and passwords. If nil, they are random.
+	Generator Generator
+
+	// Executor runs Snowflake statements. If nil, a gosnowflake-backed executor is used.
+	Executor SnowflakeExecutor
+
+	// CredentialsSinks holds the sinks selectable by Spec.CredentialsSink besides the built-in Kubernetes
+	// sink, which an entry for Kubernetes replaces
+	CredentialsSinks map[operatorv1alpha1.CredentialsSinkType]CredentialsSink
+
+	// connections caches organization connections keyed by org credentials
+	connections snowflakeConnectionCache
+
+	// passwordChecks holds when the password change of each account, keyed by UID, was last checked
+	passwordChecks sync.Map
+
+	// creditSyncs holds when the credits used by each account, keyed by UID, were last synced
+	creditSyncs sync.Map
+
+	// privileges caches whether the organization roles have the CREATE ACCOUNT privilege
+	privileges privilegeCache
+
+	// connectivity is the outcome of the latest connectivity check
+	connectivity connectivityStatus
+
+	// regionCounter holds the round-robin position in AllowedRegions
+	regionCounter atomic.Uint64
+
+	// expiredAccounts carries accounts found by the expiry sweep to the controller
+	expiredAccounts chan event.GenericEvent
+
+	// inFlight tracks resources with a reconcile in progress so that create/delete
+	// operations for the same object never run concurrently within this process
+	inFlight sync.Map
 }
 
+// Condition types reported on the SnowflakeAccount status
+const (
+	// conditionNoExpiry indicates the account has no duration, so it is only dropped when the resource is deleted
+	conditionNoExpiry = "NoExpiry"
+	// conditionRenaming indicates whether the Snowflake account is being renamed
+	conditionRenaming = "Renaming"
+	// conditionDNSResolved indicates whether the account's hostname resolves in DNS
+	conditionDNSResolved = "DNSResolved"
+	// conditionError indicates the resource spec has a problem that needs user attention
+	conditionError = "Error"
+	// conditionSecretReady indicates whether the credentials secret exists
+	conditionSecretReady = "SecretReady"
+	// conditionPasswordChangePending indicates the admin must change the initial password on first login,
+	// after which the password stored in the credentials secret no longer works
+	conditionPasswordChangePending = "PasswordChangePending"
+	// conditionQuotaExceeded indicates whether creation is blocked by the per-namespace account limit
+	conditionQuotaExceeded = "QuotaExceeded"
+	// conditionAccountDeletionDisabled indicates the operator runs with account deletion disabled
+	conditionAccountDeletionDisabled = "AccountDeletionDisabled"
+	// conditionCredentialsMissing indicates the organization credentials for the account cannot be loaded
+	conditionCredentialsMissing = "CredentialsMissing"
+	// conditionDeletionBlocked indicates the resource is being deleted but is protected from deletion
+	conditionDeletionBlocked = "DeletionBlocked"
+	// conditionSuspended indicates whether the Snowflake account is suspended
+	conditionSuspended = "Suspended"
+	// conditionInsufficientPrivileges indicates whether the organization role lacks the CREATE ACCOUNT privilege
+	conditionInsufficientPrivileges = "InsufficientPrivileges"
+	// conditionFailed indicates provisioning failed terminally and is no longer retried
+	conditionFailed = "Failed"
+	// conditionRegionUnavailable indicates whether Snowflake reported the account's region as unavailable
+	conditionRegionUnavailable = "RegionUnavailable"
+	// conditionSecretRecreated indicates the credentials secret was deleted and recreated by the operator
+	conditionSecretRecreated = "SecretRecreated"
+	// conditionFinalizeFailed indicates the resource could not be finalized within MaxFinalizeAttempts
+	conditionFinalizeFailed = "FinalizeFailed"
+	// conditionExpired indicates the duration of an account the operator does not manage has expired
+	conditionExpired = "Expired"
+	// conditionCreditsSynced indicates whether the credits used by the account were last synced successfully
+	conditionCreditsSynced = "CreditsSynced"
+)
+
+// fieldManager is the field manager the operator applies the objects it owns with
+const fieldManager = "speck-operator"
+
+// inFlightRequeueInterval is how long to wait before retrying a reconcile that
+// was skipped because another reconcile for the same object was in progress
+const inFlightRequeueInterval = 5 * time.Second
+
+// quotaRequeueInterval is how long to wait before re-checking a namespace's account quota
+const quotaRequeueInterval = time.Minute
+
+// credentialsRequeueInterval is how long to wait before re-checking missing organization credentials
+const credentialsRequeueInterval = time.Minute
+
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccounts/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccounts/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -52,8 +277,20 @@ type SnowflakeAccountReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
 func (r *SnowflakeAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// The manager tags the logger with a reconcileID; add one when Reconcile is called directly
+	// so that every log line of a reconcile can still be correlated
+	if controller.ReconcileIDFromContext(ctx) == "" {
+		ctx = logf.IntoContext(ctx, logf.FromContext(ctx).WithValues("reconcileID", uuid.NewUUID()))
+	}
 	log := logf.FromContext(ctx)
 
+	// Serialize reconciles for the same object to avoid issuing duplicate CREATE/DROP statements
+	if !r.tryLock(req.NamespacedName) {
+		log.Info("Another reconcile is in progress for this resource, requeuing", "after", inFlightRequeueInterval)
+		return ctrl.Result{RequeueAfter: inFlightRequeueInterval}, nil
+	}
+	defer r.unlock(req.NamespacedName)
+
 	// Fetch the SnowflakeAccount instance
 	snowflakeAccount := &operatorv1alpha1.SnowflakeAccount{}
 	err := r.Get(ctx, req.NamespacedName, snowflakeAccount)
@@ -66,58 +303,184 @@ func (r *SnowflakeAccountReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Tag all downstream logs with the resource UID to correlate them across reconciles
+	log = log.WithValues("uid", snowflakeAccount.UID)
+	ctx = logf.IntoContext(ctx, log)
+
+	// Without credentials the account can neither be created nor dropped, so don't add a finalizer
+	// that could never be removed; deletion still proceeds so the finalizer can report the failure
+	if snowflakeAccount.DeletionTimestamp.IsZero() {
+		if ok, err := r.checkCredentials(ctx, snowflakeAccount); err != nil || !ok {
+			if err != nil {
+				log.Error(err, "Failed to update credentials condition")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: credentialsRequeueInterval}, nil
+		}
+	}
+
 	// Handle finalizer operations (deletion, adding/removing finalizers)
-	continueReconciliation, err := r.handleFinalizerOperations(ctx, snowflakeAccount)
+	continueReconciliation, finalizeRequeue, err := r.handleFinalizerOperations(ctx, snowflakeAccount)
 	if !continueReconciliation {
+		return ctrl.Result{RequeueAfter: finalizeRequeue}, err
+	}
+
+	// Report whether the operator may drop the account
+	if err := r.reconcileDeletionDisabledCondition(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update account deletion condition")
 		return ctrl.Result{}, err
 	}
 
+	// Report new resources as pending until provisioning starts
+	if snowflakeAccount.Status.Phase == "" && !snowflakeAccount.Status.AccountCreated {
+		if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhasePending); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Refuse to write secrets into a namespace the operator does not allow, in case the webhook is disabled
+	if err := r.validateSecretNamespace(snowflakeAccount); err != nil {
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Cannot store credentials: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	// Only maintain the credentials secret of an account created outside the operator
+	if !managesAccount(snowflakeAccount) || snowflakeAccount.Status.Unmanaged {
+		return r.reconcileUnmanagedAccount(ctx, snowflakeAccount)
+	}
+
 	// Check if the account has already been created
 	if snowflakeAccount.Status.AccountCreated {
-		log.Info("Snowflake account already created")
+		return r.reconcileCreatedAccount(ctx, snowflakeAccount)
+	}
 
-		// Check if duration has expired
-		if shouldDeleteDueToDuration, requeueAfter := r.checkDuration(ctx, snowflakeAccount); shouldDeleteDueToDuration {
-			log.Info("Duration expired, deleting Snowflake account")
+	// Finish provisioning an account that is waiting for its DNS record
+	if snowflakeAccount.Spec.WaitForDNS && snowflakeAccount.Status.AccountName != "" {
+		return r.reconcileAccountDNS(ctx, snowflakeAccount)
+	}
 
-			// Delete the Kubernetes resource - the finalizer will handle Snowflake account cleanup
-			if err := r.Delete(ctx, snowflakeAccount); err != nil {
-				log.Error(err, "Failed to delete SnowflakeAccount resource due to duration expiration")
-				return ctrl.Result{}, err
-			}
+	// Start over when a retry of a timed out provisioning is requested
+	if err := r.restartRequestedProvisioning(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to restart provisioning")
+		return ctrl.Result{}, err
+	}
 
-			log.Info("Triggered deletion of Snowflake account due to duration expiration")
-			return ctrl.Result{}, nil
-		} else if requeueAfter > 0 {
-			// Requeue to check duration again
-			log.Info("Requeuing to check duration", "after", requeueAfter)
-			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	// Wait out the backoff after a failed create before contacting Snowflake again
+	if backoff := r.remainingFailureBackoff(snowflakeAccount); backoff > 0 {
+		log.V(1).Info("Waiting before retrying after consecutive failures",
+			"failureCount", snowflakeAccount.Status.FailureCount, "after", backoff)
+		return ctrl.Result{RequeueAfter: backoff}, nil
+	}
+
+	// Restore a previously dropped account instead of creating a new one
+	if accountName := snowflakeAccount.Annotations[undropAccountAnnotation]; accountName != "" {
+		return r.reconcileUndrop(ctx, snowflakeAccount, accountName)
+	}
+
+	// Take over an existing account instead of creating a new one
+	if snowflakeAccount.Spec.AdoptExisting {
+		return r.reconcileAdoption(ctx, snowflakeAccount)
+	}
+
+	// A credentials secret means an earlier reconcile created the account but failed to record it
+	if result, handled, err := r.resumeFromCredentialsSecret(ctx, snowflakeAccount); handled {
+		if err != nil {
+			log.Error(err, "Failed to resume provisioning from the credentials secret")
+		}
+		return result, err
+	}
+
+	// A recorded account name means an earlier CREATE ACCOUNT may have succeeded without being recorded
+	if result, handled, err := r.resumeUnfinishedCreate(ctx, snowflakeAccount); handled {
+		if err != nil {
+			log.Error(err, "Failed to resume an unfinished account creation")
 		}
+		return result, err
+	}
 
-		return ctrl.Result{}, nil
+	// Refuse to create the account if the namespace has reached its account limit
+	if exceeded, err := r.checkNamespaceQuota(ctx, snowflakeAccount); err != nil || exceeded {
+		if err != nil {
+			log.Error(err, "Failed to check namespace account quota")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: quotaRequeueInterval}, nil
+	}
+
+	// Wait for the organization role to be able to create accounts rather than failing in Snowflake
+	if insufficient, err := r.checkCreateAccountPrivilege(ctx, snowflakeAccount); err != nil || insufficient {
+		if err != nil {
+			log.Error(err, "Failed to check the CREATE ACCOUNT privilege")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: privilegeCheckTTL}, nil
+	}
+
+	// Give up once creating the account has not succeeded for too long, until a retry is requested
+	if timedOut, err := r.checkProvisioningTimeout(ctx, snowflakeAccount); err != nil || timedOut {
+		if err != nil {
+			log.Error(err, "Failed to check the provisioning timeout")
+		}
+		return ctrl.Result{}, err
 	}
 
 	// Create the Snowflake account
+	if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseProvisioning); err != nil {
+		return ctrl.Result{}, err
+	}
 	log.Info("Creating Snowflake account")
 	accountDetails, err := r.createSnowflakeAccount(ctx, snowflakeAccount)
 	if err != nil {
+		if r.recordInterruption(ctx, snowflakeAccount, "Creating the Snowflake account", err) {
+			return ctrl.Result{}, err
+		}
+		if isRegionUnavailable(err) {
+			return r.handleRegionUnavailable(ctx, snowflakeAccount, err)
+		}
 		log.Error(err, "Failed to create Snowflake account")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
 		snowflakeAccount.Status.Message = fmt.Sprintf("Failed to create account: %v", err)
-		if statusErr := r.Status().Update(ctx, snowflakeAccount); statusErr != nil {
-			log.Error(statusErr, "Failed to update status")
+		// Retry with an escalating delay instead of the workqueue's backoff, so the wait is visible in the status
+		backoff, err := r.recordFailure(ctx, snowflakeAccount, "Creating the Snowflake account", err)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+		return ctrl.Result{RequeueAfter: backoff}, nil
+	}
+
+	return r.finishCreation(ctx, snowflakeAccount, accountDetails)
+}
+
+// finishCreation records a created account, stores its credentials and reports it as created
+func (r *SnowflakeAccountReconciler) finishCreation(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, accountDetails *accountDetails) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Record the account name right away so a failure below cannot orphan the account
+	if err := r.recordSnowflakeAccountName(ctx, snowflakeAccount, accountDetails.accountName); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Create a secret to store the credentials
-	if err := r.createCredentialsSecret(ctx, snowflakeAccount, accountDetails); err != nil {
+	if err := r.ensureCredentialsSecret(ctx, snowflakeAccount, accountDetails); err != nil {
+		// A generated admin password stays in the pending creation until the secret can be stored
 		log.Error(err, "Failed to create credentials secret")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
 		snowflakeAccount.Status.Message = fmt.Sprintf("Account created but failed to store credentials: %v", err)
-		if statusErr := r.Status().Update(ctx, snowflakeAccount); statusErr != nil {
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
 			log.Error(statusErr, "Failed to update status")
 		}
 		return ctrl.Result{}, err
 	}
+	if err := r.deletePendingCreate(ctx, snowflakeAccount); err != nil {
+		// The secret is owned by the resource, so it is garbage collected with it at the latest
+		log.Error(err, "Failed to delete the pending account creation")
+	}
+
+	// Wait for the account hostname to resolve before reporting it as created
+	if snowflakeAccount.Spec.WaitForDNS {
+		return r.startWaitingForAccountDNS(ctx, snowflakeAccount, accountDetails)
+	}
 
 	// Update status to indicate successful creation
 	if err := r.updateStatusAfterCreation(ctx, snowflakeAccount, accountDetails); err != nil {
@@ -125,13 +488,177 @@ func (r *SnowflakeAccountReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	log.Info("Successfully created Snowflake account and stored credentials", "accountName", accountDetails.accountName)
+	return r.expiryRequeue(ctx, snowflakeAccount), nil
+}
+
+// reconcileCreatedAccount keeps an already created account in line with its spec and
+// deletes it once its duration has expired
+func (r *SnowflakeAccountReconciler) reconcileCreatedAccount(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	log.Info("Snowflake account already created")
+
+	// Resources created before phases were reported have none yet
+	if snowflakeAccount.Status.Phase == "" {
+		if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseReady); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Recreate the credentials secret if it was deleted
+	if err := r.recreateCredentialsSecret(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to recreate credentials secret")
+		return ctrl.Result{}, err
+	}
+
+	// Rename the account if the desired name has changed
+	if err := r.reconcileAccountName(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to rename Snowflake account")
+		return ctrl.Result{}, err
+	}
+
+	// Suspend or resume the account as requested. A failure is retried once the duration has been
+	// checked, so an account that cannot be suspended or resumed still expires.
+	suspensionErr := r.reconcileSuspension(ctx, snowflakeAccount)
+	if suspensionErr != nil {
+		log.Error(suspensionErr, "Failed to change the suspension of the Snowflake account")
+	}
+
+	// Apply optional post-provisioning configuration; a suspended account cannot be logged into
+	var bootstrapRequeue time.Duration
+	if !snowflakeAccount.Spec.Suspended && suspensionErr == nil {
+		var err error
+		bootstrapRequeue, err = r.reconcileAccountBootstrap(ctx, snowflakeAccount)
+		if err != nil {
+			log.Error(err, "Failed to update post-provisioning status")
+			return ctrl.Result{}, err
+		}
+
+		passwordRequeue, err := r.checkPasswordChange(ctx, snowflakeAccount)
+		if err != nil {
+			log.Error(err, "Failed to update password change status")
+			return ctrl.Result{}, err
+		}
+		if passwordRequeue > 0 && (bootstrapRequeue == 0 || passwordRequeue < bootstrapRequeue) {
+			bootstrapRequeue = passwordRequeue
+		}
+
+		creditsRequeue, err := r.syncCredits(ctx, snowflakeAccount)
+		if err != nil {
+			log.Error(err, "Failed to update credits used")
+			return ctrl.Result{}, err
+		}
+		if creditsRequeue > 0 && (bootstrapRequeue == 0 || creditsRequeue < bootstrapRequeue) {
+			bootstrapRequeue = creditsRequeue
+		}
+	}
+
+	// Surface invalid durations instead of acting on them
+	if _, err := r.reconcileDurationCondition(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update duration condition")
+		return ctrl.Result{}, err
+	}
+
+	// Check if duration has expired, persisting an expiry that changed with the duration
+	expiresAt := snowflakeAccount.Status.ExpiresAt
+	shouldDeleteDueToDuration, requeueAfter := r.checkDuration(ctx, snowflakeAccount)
+	if !expiresAt.Equal(snowflakeAccount.Status.ExpiresAt) {
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			log.Error(err, "Failed to update the expiry of the account")
+			return ctrl.Result{}, err
+		}
+	}
+	if shouldDeleteDueToDuration && r.DisableAccountDeletion {
+		log.Info("Duration expired, but account deletion is disabled on the operator; not deleting")
+		shouldDeleteDueToDuration = false
+	}
+	if shouldDeleteDueToDuration {
+		log.Info("Duration expired, deleting Snowflake account")
+		if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseExpiring); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// Delete the Kubernetes resource - the finalizer will handle Snowflake account cleanup
+		if err := r.Delete(ctx, snowflakeAccount); err != nil {
+			log.Error(err, "Failed to delete SnowflakeAccount resource due to duration expiration")
+			return ctrl.Result{}, err
+		}
+
+		log.Info("Triggered deletion of Snowflake account due to duration expiration")
+		return ctrl.Result{}, nil
+	}
+
+	if suspensionErr != nil {
+		return ctrl.Result{}, suspensionErr
+	}
+
+	// Retry failed post-provisioning steps no later than the next duration check
+	if bootstrapRequeue > 0 && (requeueAfter == 0 || bootstrapRequeue < requeueAfter) {
+		requeueAfter = bootstrapRequeue
+	}
+	if requeueAfter > 0 {
+		log.Info("Requeuing to check duration", "after", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// eventf emits an event for the resource when a Recorder is configured
+func (r *SnowflakeAccountReconciler) eventf(snowflakeAccount *operatorv1alpha1.SnowflakeAccount, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(snowflakeAccount, eventType, reason, messageFmt, args...)
+	}
+}
+
+// tryLock marks the resource as being reconciled, returning false if it already is
+func (r *SnowflakeAccountReconciler) tryLock(key types.NamespacedName) bool {
+	_, alreadyLocked := r.inFlight.LoadOrStore(key, struct{}{})
+	return !alreadyLocked
+}
+
+// unlock releases the in-flight marker for the resource
+func (r *SnowflakeAccountReconciler) unlock(key types.NamespacedName) {
+	r.inFlight.Delete(key)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SnowflakeAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// Find the accounts to reconcile when an organization credentials secret is rotated
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &operatorv1alpha1.SnowflakeAccount{},
+		credentialsSecretIndexField, r.credentialsSecretKeys); err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&operatorv1alpha1.SnowflakeAccount{}).
-		Named("snowflakeaccount").
-		Complete(r)
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.accountsForCredentialsSecret)).
+		Named("snowflakeaccount")
+
+	if r.ExpirySweepInterval > 0 && !r.DisableAccountDeletion {
+		r.expiredAccounts = make(chan event.GenericEvent)
+		if err := mgr.Add(manager.RunnableFunc(r.runExpirySweeper)); err != nil {
+			return err
+		}
+		builder = builder.WatchesRawSource(source.Channel(r.expiredAccounts, &handler.EnqueueRequestForObject{}))
+	}
+
+	if r.OrphanAuditInterval > 0 && r.KubernetesTagSchema != "" {
+		if err := mgr.Add(manager.RunnableFunc(r.runOrphanAudit)); err != nil {
+			return err
+		}
+	}
+
+	if r.ConnectivityCheckInterval > 0 {
+		if err := mgr.Add(&connectivityChecker{reconciler: r}); err != nil {
+			return err
+		}
+		if r.ConnectivityReadiness {
+			if err := mgr.AddReadyzCheck("snowflake", r.snowflakeReadyzCheck); err != nil {
+				return err
+			}
+		}
+	}
+
+	return builder.Complete(r)
 }
@@ -18,11 +18,21 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/snowflakedb/gosnowflake"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,4 +91,2142 @@ var _ = Describe("SnowflakeAccount Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When provisioning an account", func() {
+		const resourceName = "provision-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var (
+			executor             *fakeSnowflakeExecutor
+			controllerReconciler *SnowflakeAccountReconciler
+		)
+
+		BeforeEach(func() {
+			for key, value := range map[string]string{
+				"SNOWFLAKE_ORG_USERNAME": "org_admin",
+				"SNOWFLAKE_ORG_PASSWORD": "org-password",
+				"SNOWFLAKE_ORG_ACCOUNT":  "myorg-admin",
+			} {
+				GinkgoT().Setenv(key, value)
+			}
+
+			executor = &fakeSnowflakeExecutor{
+				grants: map[string][]map[string]string{
+					"ORGADMIN": {{"privilege": "CREATE ACCOUNT", "granted_on": "ACCOUNT", "grantee_name": "ORGADMIN"}},
+				},
+			}
+			controllerReconciler = &SnowflakeAccountReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Clock:    clock.RealClock{},
+				Executor: executor,
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: operatorv1alpha1.SnowflakeAccountSpec{
+					Duration: "1h",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				resource.Finalizers = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+			}
+
+			// Owned secrets are not garbage collected by the test API server
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace("default"),
+				client.MatchingLabels{"app.kubernetes.io/instance": resourceName})).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName + pendingCreateSecretSuffix, Namespace: "default"},
+			}))).To(Succeed())
+		})
+
+		It("should not add a finalizer while organization credentials are missing", func() {
+			GinkgoT().Setenv("SNOWFLAKE_ORG_ACCOUNT", "")
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(credentialsRequeueInterval))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(BeEmpty())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionCredentialsMissing)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("SNOWFLAKE_ORG_ACCOUNT"))
+
+			By("restoring the credentials")
+			GinkgoT().Setenv("SNOWFLAKE_ORG_ACCOUNT", "myorg-admin")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).NotTo(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionCredentialsMissing)).To(BeTrue())
+		})
+
+		It("should issue CREATE ACCOUNT and DROP ACCOUNT", func() {
+			By("reconciling until the account is created")
+			var result reconcile.Result
+			for range 2 {
+				var err error
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("scheduling the first duration check right away")
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseReady))
+			Expect(resource.Status.AccountName).NotTo(BeEmpty())
+			Expect(resource.Status.OrgAccount).To(Equal("myorg-admin"))
+			Expect(resource.Status.OrgRole).To(Equal("ORGADMIN"))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionSecretReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChangePending)).To(BeTrue())
+
+			secretRef := resource.Status.CredentialsSecret
+			Expect(secretRef).NotTo(BeNil())
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: secretRef.Namespace}, secret)).To(Succeed())
+			Expect(secretRef.Name).To(Equal(credentialsSecretName(resource.Status.AccountName)))
+			Expect(secretRef.ResourceVersion).To(Equal(secret.ResourceVersion))
+
+			By("deleting the resource")
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(ConsistOf(
+				"DROP ACCOUNT IF EXISTS " + resource.Status.AccountName + " GRACE_PERIOD_IN_DAYS = 3",
+			))
+			until := time.Now().AddDate(0, 0, 3).UTC().Format("2006-01-02")
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix("Normal AccountDropped"),
+				ContainSubstring("restored with UNDROP ACCOUNT until "+until),
+			)))
+		})
+
+		It("should only manage the credentials secret of an unmanaged account", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			manageAccount := false
+			resource.Spec.ManageAccount = &manageAccount
+			resource.Spec.ExistingAccountName = "manualacct"
+			resource.Spec.ExistingAdminName = "MANUAL_ADMIN"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			var result reconcile.Result
+			for range 2 {
+				var err error
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseReady))
+			Expect(resource.Status.AccountName).To(Equal("MANUALACCT"))
+			Expect(resource.Status.Unmanaged).To(BeTrue())
+			Expect(resource.Status.Message).To(ContainSubstring("not managed by the operator"))
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "manualacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("accountName", []byte("MANUALACCT")))
+			Expect(secret.Data).To(HaveKeyWithValue("adminName", []byte("MANUAL_ADMIN")))
+
+			By("recreating a deleted credentials secret")
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "manualacct-creds", Namespace: "default"}, secret)).To(Succeed())
+
+			By("reporting an expired duration without deleting the resource")
+			fakeClock.SetTime(fakeClock.Now().Add(2 * time.Hour))
+			for range 2 {
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeZero())
+			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.DeletionTimestamp.IsZero()).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionExpired)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning DurationExpired")))
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("refusing to manage the account when manageAccount is set afterwards")
+			manageAccount = true
+			resource.Spec.ManageAccount = &manageAccount
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+			Expect(resource.Status.Message).To(ContainSubstring("cannot be managed by the operator"))
+
+			By("not dropping the account when the resource is deleted")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+			Expect(executor.statementsWithPrefix("")).To(BeEmpty())
+		})
+
+		It("should run the pre-delete statements before dropping the account", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.PreDeleteSQL = []string{
+				"REVOKE USAGE ON DATABASE SALES FROM SHARE SALES_SHARE",
+				"DROP SHARE IF EXISTS SALES_SHARE",
+			}
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+
+			By("deleting the resource with a failing pre-delete statement")
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(statement, "REVOKE") {
+					return &gosnowflake.SnowflakeError{Number: 2003, Message: "Share 'SALES_SHARE' does not exist."}
+				}
+				return nil
+			}
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			executor.statements = nil
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statements).To(ContainElements(
+				"REVOKE USAGE ON DATABASE SALES FROM SHARE SALES_SHARE",
+				"DROP SHARE IF EXISTS SALES_SHARE",
+				"DROP ACCOUNT IF EXISTS "+resource.Status.AccountName+" GRACE_PERIOD_IN_DAYS = 3",
+			))
+			Expect(executor.statements[len(executor.statements)-1]).To(HavePrefix("DROP ACCOUNT"))
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix("Warning PreDeleteSQLFailed"),
+				ContainSubstring("1 of 2 pre-delete statements failed"),
+			)))
+		})
+
+		It("should mark a deletion that keeps failing and retry it on a slower cadence", func() {
+			controllerReconciler.MaxFinalizeAttempts = 3
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("deleting the resource while the drop fails")
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(statement, "DROP ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 390100, Message: "Incorrect username or password was specified."}
+				}
+				return nil
+			}
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			for i, requeueAfter := range []time.Duration{10 * time.Second, 20 * time.Second, finalizeFailedRetryInterval, finalizeFailedRetryInterval} {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(requeueAfter))
+
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(resource.Status.FailureCount).To(Equal(i + 1))
+
+				// The update recording the attempt triggers another reconcile, which must not retry yet
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", requeueAfter, time.Second))
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(resource.Status.FailureCount).To(Equal(i + 1))
+				fakeClock.SetTime(fakeClock.Now().Add(requeueAfter))
+			}
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionFinalizeFailed)).To(BeTrue())
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(HavePrefix("Warning FinalizeFailed Finalizing failed 3 times")))
+			Expect(events).NotTo(ContainElement(ContainSubstring("Finalizing failed 4 times")))
+
+			By("letting the drop succeed")
+			executor.errFor = nil
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should add and remove the configured finalizer", func() {
+			controllerReconciler.FinalizerName = "speck.example.com/migration"
+			Expect(ValidateFinalizerName(controllerReconciler.FinalizerName)).To(Succeed())
+			Expect(ValidateFinalizerName("not a finalizer")).NotTo(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(Equal([]string{"speck.example.com/migration"}))
+
+			By("deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should report an account without a duration in the NoExpiry condition", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Duration = ""
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionNoExpiry)).To(BeTrue())
+
+			By("setting a duration")
+			resource.Spec.Duration = "2h"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionNoExpiry)).To(BeTrue())
+		})
+
+		It("should skip a resource managed by an instance with another finalizer", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(Equal([]string{DefaultFinalizerName}))
+			Expect(resource.Annotations).To(HaveKeyWithValue(finalizerNameAnnotation, DefaultFinalizerName))
+
+			By("reconciling with another instance")
+			controllerReconciler.FinalizerName = "speck.example.com/migration"
+			for range 2 {
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(Equal([]string{DefaultFinalizerName}))
+		})
+
+		It("should never drop the account when deletion is disabled", func() {
+			controllerReconciler.DisableAccountDeletion = true
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionAccountDeletionDisabled)).To(BeTrue())
+
+			By("letting the duration expire")
+			expired := metav1.NewTime(resource.Status.CreationTime.Add(-2 * time.Hour))
+			resource.Status.CreationTime = &expired
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.DeletionTimestamp).To(BeNil())
+
+			By("deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(BeEmpty())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should drop with the operator's minimum grace period", func() {
+			controllerReconciler.MinGracePeriodDays = 14
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			accountName := resource.Status.AccountName
+
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(Equal([]string{
+				"DROP ACCOUNT IF EXISTS " + accountName + " GRACE_PERIOD_IN_DAYS = 14",
+			}))
+		})
+
+		It("should suspend and resume the account while still enforcing its duration", func() {
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			accountName := resource.Status.AccountName
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, conditionSuspended)).To(BeNil())
+
+			By("suspending the account")
+			resource.Spec.Suspended = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT")).To(Equal([]string{"ALTER ACCOUNT " + accountName + " SUSPEND"}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionSuspended)).To(BeTrue())
+
+			By("resuming the account")
+			resource.Spec.Suspended = false
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT")).To(Equal([]string{
+				"ALTER ACCOUNT " + accountName + " SUSPEND",
+				"ALTER ACCOUNT " + accountName + " RESUME",
+			}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionSuspended)).To(BeTrue())
+
+			By("letting the duration of the suspended account expire")
+			resource.Spec.Suspended = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			expired := metav1.NewTime(resource.Status.CreationTime.Add(-2 * time.Hour))
+			resource.Status.CreationTime = &expired
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionSuspended)).To(BeTrue())
+			Expect(resource.DeletionTimestamp).NotTo(BeNil())
+		})
+
+		It("should still expire an account whose suspension keeps failing", func() {
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			executor.errFor = func(statement string) error {
+				if strings.HasSuffix(statement, " SUSPEND") {
+					return &gosnowflake.SnowflakeError{Number: 3001, Message: "Insufficient privileges."}
+				}
+				return nil
+			}
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Suspended = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			expired := metav1.NewTime(resource.Status.CreationTime.Add(-2 * time.Hour))
+			resource.Status.CreationTime = &expired
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, conditionSuspended).Reason).To(Equal("SuspendFailed"))
+			Expect(resource.DeletionTimestamp).NotTo(BeNil())
+		})
+
+		It("should keep a protected account until the protection annotation is removed", func() {
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{deletionProtectionAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("deleting the protected resource")
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.DeletionTimestamp).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionDeletionBlocked)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning DeletionBlocked")))
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("removing the protection")
+			delete(resource.Annotations, deletionProtectionAnnotation)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(HaveLen(1))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should back off increasingly while creating the account keeps failing", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 390100, Message: "Incorrect username or password was specified."}
+				}
+				return nil
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			for i, backoff := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second} {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(backoff))
+
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(resource.Status.FailureCount).To(Equal(i + 1))
+				Expect(resource.Status.LastFailureTime).NotTo(BeNil())
+				Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+
+				By("waiting out the backoff when reconciled before the retry is due")
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				// The failure time in the status is stored with second precision
+				Expect(result.RequeueAfter).To(BeNumerically("~", backoff, time.Second))
+				Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(i + 1))
+				fakeClock.SetTime(fakeClock.Now().Add(backoff))
+			}
+			Expect(recorder.Events).To(Receive(And(
+				HavePrefix("Warning RepeatedFailures"),
+				ContainSubstring("failed 5 times in a row"),
+			)))
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("letting the create succeed")
+			executor.errFor = nil
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.FailureCount).To(BeZero())
+			Expect(resource.Status.LastFailureTime).To(BeNil())
+		})
+
+		It("should retry a taken generated name right away with a new name", func() {
+			controllerReconciler.NameCollisionRetries = 3
+			collisions := 0
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") && collisions < 2 {
+					collisions++
+					return &gosnowflake.SnowflakeError{Number: 2002, Message: "Object already exists."}
+				}
+				return nil
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			creates := executor.statementsWithPrefix("CREATE ACCOUNT")
+			Expect(creates).To(HaveLen(3))
+			Expect(creates[1]).NotTo(Equal(creates[0]))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.FailureCount).To(BeZero())
+			Expect(creates[2]).To(HavePrefix("CREATE ACCOUNT " + resource.Status.AccountName))
+		})
+
+		It("should create the account with an allowed billing entity", func() {
+			controllerReconciler.AllowedBillingEntities = ParseBillingEntities("FINANCE_EMEA,finance_us")
+			controllerReconciler.BillingEntityTag = "governance.tags.billing_entity"
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.BillingEntity = "finance_us"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			creates := executor.statementsWithPrefix("CREATE ACCOUNT")
+			Expect(creates).To(HaveLen(1))
+			Expect(creates[0]).To(ContainSubstring("CONSUMPTION_BILLING_ENTITY = FINANCE_US"))
+			Expect(creates[0]).To(ContainSubstring("governance.tags.billing_entity = 'FINANCE_US'"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.BillingEntity).To(Equal("FINANCE_US"))
+			Expect(resource.Status.Tags).To(HaveKeyWithValue("governance.tags.billing_entity", "FINANCE_US"))
+		})
+
+		It("should not create the account with a billing entity that is not allowed", func() {
+			controllerReconciler.AllowedBillingEntities = ParseBillingEntities("FINANCE_EMEA")
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.BillingEntity = "FINANCE_EMAE"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.Message).To(ContainSubstring("billing entity FINANCE_EMAE is not one of the allowed billing entities"))
+		})
+
+		It("should not create the account with a secret namespace that is not allowed", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.SecretNamespace = "kube-system"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+			Expect(resource.Status.Message).To(ContainSubstring("secret namespace kube-system requires the operator to be configured with --allowed-secret-namespaces"))
+		})
+
+		It("should back off once the name collision retries are used up", func() {
+			controllerReconciler.NameCollisionRetries = 2
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 2002, Message: "Object already exists."}
+				}
+				return nil
+			}
+
+			var result reconcile.Result
+			for range 2 {
+				var err error
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(3))
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.FailureCount).To(Equal(1))
+		})
+
+		It("should report the query ID of a failed CREATE ACCOUNT", func() {
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 3001, QueryID: "01b2c3d4-0000-1111", Message: "Insufficient privileges."}
+				}
+				return nil
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+			Expect(resource.Status.Message).To(ContainSubstring("(query ID 01b2c3d4-0000-1111)"))
+		})
+
+		It("should give up provisioning after MaxProvisioningDuration until a retry is requested", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 390100, Message: "Incorrect username or password was specified."}
+				}
+				return nil
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+
+			By("exceeding the default provisioning duration")
+			fakeClock.SetTime(fakeClock.Now().Add(operatorv1alpha1.DefaultMaxProvisioningDuration))
+			for range 2 {
+				result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeZero())
+			}
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionFailed)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ProvisioningTimeout"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning ProvisioningTimeout")))
+			Expect(recorder.Events).NotTo(Receive())
+
+			By("requesting a retry with the annotation")
+			executor.errFor = nil
+			resource.Annotations = map[string]string{retryProvisioningAnnotation: "1"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(2))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.ProvisioningRetry).To(Equal("1"))
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionFailed)).To(BeTrue())
+		})
+
+		It("should start the provisioning clock with the first attempt to create the account", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.grants = nil
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ProvisioningStartTime).To(BeNil())
+
+			By("granting CREATE ACCOUNT after waiting longer than the provisioning duration")
+			executor.grants = map[string][]map[string]string{
+				"ORGADMIN": {{"privilege": "CREATE ACCOUNT", "granted_on": "ACCOUNT", "grantee_name": "ORGADMIN"}},
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(operatorv1alpha1.DefaultMaxProvisioningDuration))
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+		})
+
+		It("should still resume an account created before provisioning timed out", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			started := metav1.NewTime(time.Now().Add(-2 * operatorv1alpha1.DefaultMaxProvisioningDuration))
+			resource.Status.ProvisioningStartTime = &started
+			resource.Status.SnowflakeAccountName = "SFLATE1"
+			meta.SetStatusCondition(&resource.Status.Conditions, metav1.Condition{
+				Type:   conditionFailed,
+				Status: metav1.ConditionTrue,
+				Reason: "ProvisioningTimeout",
+			})
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			executor.accounts = map[string]map[string]string{
+				"SFLATE1": {"account_name": "SFLATE1", "account_locator": "LT12345"},
+			}
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "sflate1-creds", Namespace: "default"},
+				}))).To(Succeed())
+			})
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.AccountLocator).To(Equal("LT12345"))
+		})
+
+		It("should adopt an existing account without creating one", func() {
+			executor.accounts = map[string]map[string]string{
+				"LEGACY1": {
+					"account_name":     "LEGACY1",
+					"account_locator":  "XY98765",
+					"account_url":      "myorg-legacy1.snowflakecomputing.com",
+					"snowflake_region": "AWS_US_EAST_1",
+				},
+			}
+			controllerReconciler.Generator = fixedGenerator{}
+
+			By("storing the current admin password")
+			passwordSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-admin", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("Current-Passw0rd")},
+			}
+			Expect(k8sClient.Create(ctx, passwordSecret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, passwordSecret))).To(Succeed())
+			})
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AdoptExisting = true
+			resource.Spec.ExistingAccountName = "legacy1"
+			resource.Spec.ExistingAdminName = "LEGACY_ADMIN"
+			resource.Spec.ResetAdminPassword = true
+			resource.Spec.AdminPasswordSecretRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "legacy-admin"},
+				Key:                  "password",
+			}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(executor.statementsWithPrefix("ALTER USER")).To(ConsistOf(
+				`ALTER USER "LEGACY_ADMIN" SET PASSWORD = 'Fixed-Passw0rd' MUST_CHANGE_PASSWORD = TRUE`,
+			))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.SnowflakeAccountName).To(Equal("LEGACY1"))
+			Expect(resource.Status.AccountLocator).To(Equal("XY98765"))
+			Expect(resource.Status.AccountURL).To(Equal("https://myorg-legacy1.snowflakecomputing.com"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "legacy1-creds", Namespace: "default"}, secret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+			Expect(string(secret.Data["adminName"])).To(Equal("LEGACY_ADMIN"))
+			Expect(string(secret.Data["adminPassword"])).To(Equal("Fixed-Passw0rd"))
+
+			By("skipping the post-provisioning steps of the adopted account")
+			resource.Spec.PostCreateSQL = []string{"CREATE WAREHOUSE ADHOC"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE WAREHOUSE")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			postCreateSQL := meta.FindStatusCondition(resource.Status.Conditions, conditionPostCreateSQLApplied)
+			Expect(postCreateSQL).NotTo(BeNil())
+			Expect(postCreateSQL.Reason).To(Equal("Skipped"))
+
+			By("retaining the adopted account when the resource is deleted")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(BeEmpty())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should report an account to adopt that does not exist", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AdoptExisting = true
+			resource.Spec.ExistingAccountName = "MISSING1"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.Message).To(ContainSubstring("MISSING1 does not exist"))
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseFailed))
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+		})
+
+		It("should create the account with the names from the configured generator", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			executor.accounts = map[string]map[string]string{
+				"SFFIXED1": {"account_name": "SFFIXED1", "account_locator": "AB12345"},
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			statements := executor.statementsWithPrefix("CREATE ACCOUNT SFFIXED1")
+			Expect(statements).To(HaveLen(1))
+			Expect(statements[0]).To(ContainSubstring("ADMIN_NAME = 'admin_fixed'"))
+			Expect(statements[0]).To(ContainSubstring("ADMIN_PASSWORD = 'Fixed-Passw0rd'"))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountLocator).To(Equal("AB12345"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sffixed1-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data["adminPassword"])).To(Equal("Fixed-Passw0rd"))
+			Expect(string(secret.Data["accountLocator"])).To(Equal("AB12345"))
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+		})
+
+		It("should not render an invalid name from the configured generator", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SF1 COMMENT = 'x'"}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Message).To(ContainSubstring("is not a valid Snowflake account identifier"))
+		})
+
+		It("should check whether a CREATE ACCOUNT that timed out created the account before retrying", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return fmt.Errorf("waiting for CREATE ACCOUNT: %w", context.DeadlineExceeded)
+				}
+				return nil
+			}
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.SnowflakeAccountName).To(Equal("SFFIXED1"))
+			Expect(resource.Status.Message).To(ContainSubstring("did not finish in time"))
+			pendingKey := types.NamespacedName{Name: resourceName + pendingCreateSecretSuffix, Namespace: "default"}
+			pending := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, pendingKey, pending)).To(Succeed())
+			Expect(string(pending.Data["adminPassword"])).To(Equal("Fixed-Passw0rd"))
+
+			By("finding the account in Snowflake once the backoff has passed, after a restart")
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1", password: "Other-Passw0rd"}
+			fakeClock.SetTime(fakeClock.Now().Add(failureBackoffBase))
+			executor.errFor = nil
+			executor.accounts = map[string]map[string]string{
+				"SFFIXED1": {"account_name": "SFFIXED1", "account_locator": "AB12345"},
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+			Expect(executor.statementsWithPrefix("SHOW ACCOUNTS LIKE 'SFFIXED1'")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.AccountLocator).To(Equal("AB12345"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sffixed1-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data["adminPassword"])).To(Equal("Fixed-Passw0rd"))
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, pendingKey, &corev1.Secret{}))).To(BeTrue())
+		})
+
+		It("should fail the reconcile when the name of a CREATE ACCOUNT that timed out cannot be recorded", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return fmt.Errorf("waiting for CREATE ACCOUNT: %w", context.DeadlineExceeded)
+				}
+				return nil
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			controllerReconciler.Client = &failingStatusClient{Client: k8sClient, fail: func(obj client.Object) bool {
+				return obj.(*operatorv1alpha1.SnowflakeAccount).Status.SnowflakeAccountName != ""
+			}}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(MatchError(ContainSubstring("failed to record failure in status")))
+		})
+
+		It("should retry a CREATE ACCOUNT that timed out without creating the account for the same account", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return fmt.Errorf("waiting for CREATE ACCOUNT: %w", context.DeadlineExceeded)
+				}
+				return nil
+			}
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("retrying with the same account name instead of a new one")
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED2"}
+			fakeClock.SetTime(fakeClock.Now().Add(failureBackoffBase))
+			executor.errFor = nil
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("SHOW ACCOUNTS LIKE 'SFFIXED1'")).NotTo(BeEmpty())
+			creates := executor.statementsWithPrefix("CREATE ACCOUNT")
+			Expect(creates).To(HaveLen(2))
+			Expect(creates[1]).To(HavePrefix("CREATE ACCOUNT SFFIXED1"))
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.AccountName).To(Equal("SFFIXED1"))
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "sffixed1-creds", Namespace: "default"},
+				}))).To(Succeed())
+			})
+		})
+
+		It("should resume an account that a CREATE ACCOUNT which timed out created after it was checked", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return fmt.Errorf("waiting for CREATE ACCOUNT: %w", context.DeadlineExceeded)
+				}
+				return nil
+			}
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("failing the retry with a collision once the earlier CREATE ACCOUNT succeeded")
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED2"}
+			fakeClock.SetTime(fakeClock.Now().Add(failureBackoffBase))
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 2002, Message: "Object already exists."}
+				}
+				return nil
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT SFFIXED2")).To(BeEmpty())
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.SnowflakeAccountName).To(Equal("SFFIXED1"))
+
+			By("finding the account on the next attempt")
+			fakeClock.SetTime(fakeClock.Now().Add(controllerReconciler.remainingFailureBackoff(resource) + time.Second))
+			executor.accounts = map[string]map[string]string{
+				"SFFIXED1": {"account_name": "SFFIXED1", "account_locator": "AB12345"},
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(2))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.AccountName).To(Equal("SFFIXED1"))
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sffixed1-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data["adminPassword"])).To(Equal("Fixed-Passw0rd"))
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+		})
+
+		It("should report when the account expires and follow changes to the duration", func() {
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.ExpiresAt).NotTo(BeNil())
+			Expect(resource.Status.ExpiresAt.Time).To(BeTemporally("==", resource.Status.CreationTime.Add(time.Hour)))
+
+			By("extending the duration")
+			resource.Spec.Duration = "3h"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ExpiresAt.Time).To(BeTemporally("==", resource.Status.CreationTime.Add(3*time.Hour)))
+		})
+
+		It("should not create an account once the namespace quota is reached", func() {
+			By("creating another account that counts against the quota")
+			other := &operatorv1alpha1.SnowflakeAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota-other", Namespace: "default"},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, other))).To(Succeed())
+			})
+			other.Status.AccountCreated = true
+			Expect(k8sClient.Status().Update(ctx, other)).To(Succeed())
+
+			controllerReconciler.MaxAccountsPerNamespace = 1
+
+			By("reconciling the resource")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(quotaRequeueInterval))
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhasePending))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionQuotaExceeded)).To(BeTrue())
+		})
+
+		It("should wait for the organization role to be granted CREATE ACCOUNT", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.grants = map[string][]map[string]string{
+				"ORGADMIN": {{"privilege": "USAGE", "granted_on": "WAREHOUSE", "name": "COMPUTE_WH"}},
+			}
+
+			By("reconciling the resource")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(privilegeCheckTTL))
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(BeEmpty())
+			Expect(executor.statementsWithPrefix(`SHOW GRANTS TO ROLE "ORGADMIN"`)).To(HaveLen(1))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionInsufficientPrivileges)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("lacks the CREATE ACCOUNT privilege"))
+
+			By("granting a role that holds CREATE ACCOUNT through a nested role and waiting for the cached check to expire")
+			executor.grants = map[string][]map[string]string{
+				"ORGADMIN":         {{"privilege": "USAGE", "granted_on": "ROLE", "name": "Account Creators"}},
+				"Account Creators": {{"privilege": "USAGE", "granted_on": "ROLE", "name": "PROVISIONER"}},
+				"PROVISIONER":      {{"privilege": "CREATE ACCOUNT", "granted_on": "ACCOUNT", "grantee_name": "PROVISIONER"}},
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(privilegeCheckTTL))
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+			Expect(executor.statementsWithPrefix("SHOW GRANTS TO ROLE")).To(Equal([]string{
+				`SHOW GRANTS TO ROLE "ORGADMIN"`,
+				`SHOW GRANTS TO ROLE "ORGADMIN"`,
+				`SHOW GRANTS TO ROLE "Account Creators"`,
+				`SHOW GRANTS TO ROLE "PROVISIONER"`,
+			}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionInsufficientPrivileges)).To(BeTrue())
+		})
+
+		It("should record an interrupted create when the operator shuts down", func() {
+			reconcileCtx, cancelReconcile := context.WithCancel(ctx)
+			defer cancelReconcile()
+
+			executor.onExec = func(statement string) {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					cancelReconcile()
+				}
+			}
+			executor.err = context.Canceled
+
+			_, err := controllerReconciler.Reconcile(reconcileCtx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = controllerReconciler.Reconcile(reconcileCtx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(MatchError(context.Canceled))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionInterrupted)).To(BeTrue())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+		})
+
+		It("should retry status updates that conflict with a concurrent change", func() {
+			stale := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, stale)).To(Succeed())
+
+			By("modifying the resource concurrently")
+			current := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, current)).To(Succeed())
+			current.Labels = map[string]string{"team": "data"}
+			Expect(k8sClient.Update(ctx, current)).To(Succeed())
+
+			By("updating the status from the stale copy")
+			stale.Status.Message = "written after a conflict"
+			Expect(controllerReconciler.updateStatus(ctx, stale)).To(Succeed())
+			Expect(stale.Labels).To(HaveKeyWithValue("team", "data"))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Message).To(Equal("written after a conflict"))
+		})
+
+		It("should not create a second account when recording the first one failed", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("failing the status update that records the created account")
+			controllerReconciler.Client = &failingStatusClient{Client: k8sClient, fail: func(obj client.Object) bool {
+				return obj.(*operatorv1alpha1.SnowflakeAccount).Status.AccountCreated
+			}}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			accountName := resource.Status.SnowflakeAccountName
+			Expect(accountName).NotTo(BeEmpty())
+
+			By("reconciling again once status updates succeed")
+			controllerReconciler.Client = k8sClient
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.AccountName).To(Equal(accountName))
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhaseReady))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChangePending)).To(BeTrue())
+		})
+
+		It("should drop an account whose credentials secret could not be created", func() {
+			By("occupying the credentials secret name with conflicting data")
+			conflicting := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "partialacct-creds", Namespace: "default"},
+				Data:       map[string][]byte{"accountName": []byte("OTHERACCT")},
+			}
+			Expect(k8sClient.Create(ctx, conflicting)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, conflicting))).To(Succeed())
+			})
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "PARTIALACCT"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("reconciling until secret creation fails")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeFalse())
+			Expect(resource.Status.SnowflakeAccountName).To(Equal("PARTIALACCT"))
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionSecretReady)).To(BeTrue())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(conflicting), conflicting)).To(Succeed())
+			Expect(conflicting.Data).To(HaveKeyWithValue("accountName", []byte("OTHERACCT")))
+
+			By("deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(ConsistOf(
+				"DROP ACCOUNT IF EXISTS PARTIALACCT GRACE_PERIOD_IN_DAYS = 3",
+			))
+		})
+
+		It("should detect that the admin changed the initial password", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			controllerReconciler.PasswordChangeCheckInterval = time.Hour
+			passwordChanged := false
+			executor.errFor = func(statement string) error {
+				if statement != "SELECT CURRENT_USER()" {
+					return nil
+				}
+				if passwordChanged {
+					return &gosnowflake.SnowflakeError{Number: 390100, Message: "Incorrect username or password was specified."}
+				}
+				return &gosnowflake.SnowflakeError{Number: 390112, Message: "Your password has expired. Please reset your password."}
+			}
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("SELECT CURRENT_USER()")).To(HaveLen(1))
+
+			By("not logging in again before the interval has passed")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Hour))
+			Expect(executor.statementsWithPrefix("SELECT CURRENT_USER()")).To(HaveLen(1))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChangePending)).To(BeTrue())
+
+			By("rejecting the initial password once it was changed")
+			passwordChanged = true
+			fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executor.statementsWithPrefix("SELECT CURRENT_USER()")).To(HaveLen(2))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionPasswordChangePending)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ChangedByUser"))
+		})
+
+		It("should sync the credits used by the account", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			controllerReconciler.CreditSyncInterval = 30 * time.Minute
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.TrackCredits = true
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			executor.rowsFor = func(string) []map[string]string {
+				return []map[string]string{{"credits_used": "12.500000000"}}
+			}
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("SELECT")).To(ConsistOf(creditsUsedQuery))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.CreditsUsed).To(Equal("12.5"))
+			Expect(resource.Status.LastCostSync).NotTo(BeNil())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionCreditsSynced)).To(BeTrue())
+
+			By("not syncing again before the interval has passed")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Minute))
+			Expect(executor.statementsWithPrefix("SELECT")).To(HaveLen(1))
+
+			By("keeping the last figure when a sync fails")
+			lastSync := resource.Status.LastCostSync
+			executor.errFor = func(statement string) error {
+				if statement == creditsUsedQuery {
+					return &gosnowflake.SnowflakeError{Number: 2003, Message: "Object 'SNOWFLAKE.ACCOUNT_USAGE.METERING_HISTORY' does not exist or not authorized."}
+				}
+				return nil
+			}
+			fakeClock.SetTime(fakeClock.Now().Add(30 * time.Minute))
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Second))
+			Expect(executor.statementsWithPrefix("SELECT")).To(HaveLen(2))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.CreditsUsed).To(Equal("12.5"))
+			Expect(resource.Status.LastCostSync.Equal(lastSync)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionCreditsSynced)).To(BeTrue())
+
+			By("not retrying a failed sync before the interval has passed")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executor.statementsWithPrefix("SELECT")).To(HaveLen(2))
+		})
+
+		It("should replace the initial admin password when requested", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "PWDACCT"
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pwdacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			By("reconciling until the account is created and bootstrapped")
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			changes := executor.statementsWithPrefix("ALTER USER")
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0]).To(HaveSuffix("MUST_CHANGE_PASSWORD = FALSE"))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pwdacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			newPassword := string(secret.Data["adminPassword"])
+			Expect(changes[0]).To(ContainSubstring("SET PASSWORD = '" + newPassword + "'"))
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")[0]).NotTo(ContainSubstring(newPassword))
+			Expect(secret.Data).NotTo(HaveKey(pendingAdminPasswordKey))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChanged)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionPasswordChangePending)).To(BeTrue())
+
+			By("reporting the updated secret in the status")
+			Expect(resource.Status.CredentialsSecret).To(Equal(&operatorv1alpha1.CredentialsSecretReference{
+				Name:            "pwdacct-creds",
+				Namespace:       "default",
+				ResourceVersion: secret.ResourceVersion,
+			}))
+		})
+
+		It("should replace an immutable credentials secret to store the new password", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "PWDACCT"
+			resource.Spec.AutoCompletePasswordChange = true
+			resource.Spec.ImmutableSecret = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pwdacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			By("reconciling until the account is created and bootstrapped")
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			changes := executor.statementsWithPrefix("ALTER USER")
+			Expect(changes).To(HaveLen(1))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pwdacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(secret.Immutable).To(HaveValue(BeTrue()))
+			Expect(changes[0]).To(ContainSubstring("SET PASSWORD = '" + string(secret.Data["adminPassword"]) + "'"))
+			Expect(secret.Data).NotTo(HaveKey(pendingAdminPasswordKey))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			staged := types.NamespacedName{Name: "pwdacct-creds" + secretReplacementSuffix, Namespace: "default"}
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, staged, &corev1.Secret{}))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChanged)).To(BeTrue())
+			Expect(resource.Status.CredentialsSecret.ResourceVersion).To(Equal(secret.ResourceVersion))
+		})
+
+		It("should restore an immutable credentials secret whose replacement could not be created", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.ImmutableSecret = true
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "restored-creds", Namespace: "default"},
+				Data:       map[string][]byte{"adminPassword": []byte("initial")},
+				Immutable:  boolPtr(true),
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			resource.Status.CredentialsSecret = &operatorv1alpha1.CredentialsSecretReference{Name: secret.Name, Namespace: secret.Namespace}
+
+			By("failing to create the secret again after storing the replacement")
+			controllerReconciler.Client = &failingCreateClient{Client: k8sClient, secretName: secret.Name}
+			secret.Data["adminPassword"] = []byte("changed")
+			Expect(controllerReconciler.writeCredentialsSecret(ctx, resource, secret)).NotTo(Succeed())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), &corev1.Secret{}))).To(BeTrue())
+			staged := &corev1.Secret{}
+			stagedKey := types.NamespacedName{Name: "restored-creds" + secretReplacementSuffix, Namespace: "default"}
+			Expect(k8sClient.Get(ctx, stagedKey, staged)).To(Succeed())
+			Expect(staged.Data).To(HaveKeyWithValue("adminPassword", []byte("changed")))
+			Expect(staged.Labels).To(BeEmpty())
+
+			By("creating it from the replacement on a later reconcile, even after a restart")
+			controllerReconciler.Client = k8sClient
+			restarted := &SnowflakeAccountReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Clock: controllerReconciler.Clock}
+			Expect(restarted.restoreSecretReplacement(ctx, resource)).To(BeTrue())
+			restored := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), restored)).To(Succeed())
+			Expect(restored.Data).To(HaveKeyWithValue("adminPassword", []byte("changed")))
+			Expect(restored.Immutable).To(HaveValue(BeTrue()))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, stagedKey, &corev1.Secret{}))).To(BeTrue())
+			Expect(restarted.restoreSecretReplacement(ctx, resource)).To(BeFalse())
+		})
+
+		It("should split the connection details into a conninfo ConfigMap", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "SPLITACCT"
+			resource.Spec.SplitCredentials = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "splitacct-conninfo", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+			})
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "splitacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("accountName", []byte("SPLITACCT")))
+			Expect(secret.Data).To(HaveKey("adminPassword"))
+			for _, key := range connInfoKeys {
+				Expect(secret.Data).NotTo(HaveKey(key))
+			}
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "splitacct-conninfo", Namespace: "default"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("accountName", "SPLITACCT"))
+			Expect(configMap.Data).To(HaveKeyWithValue("accountURL", "https://myorg-SPLITACCT.snowflakecomputing.com"))
+			Expect(configMap.Data).To(HaveKey("region"))
+			Expect(configMap.Data).NotTo(HaveKey("adminPassword"))
+			Expect(configMap.Labels).To(HaveKeyWithValue("app.kubernetes.io/instance", resourceName))
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountURL).To(Equal(configMap.Data["accountURL"]))
+		})
+
+		It("should fall back to another region when the selected one is unavailable", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "REGIONACCT"
+			resource.Spec.Region = "AWS_US_WEST_2"
+			resource.Spec.FallbackRegions = []string{"aws_us_west_2", "AWS_EU_CENTRAL_1"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "regionacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+			executor.errFor = func(statement string) error {
+				if strings.Contains(statement, "REGION = 'AWS_US_WEST_2'") {
+					return &gosnowflake.SnowflakeError{Number: 1003, Message: "Region AWS_US_WEST_2 is currently under maintenance."}
+				}
+				return nil
+			}
+
+			var result reconcile.Result
+			for range 2 {
+				var err error
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(result.RequeueAfter).To(Equal(regionFallbackRequeueInterval))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.UnavailableRegions).To(Equal([]string{"AWS_US_WEST_2"}))
+			Expect(resource.Status.FailureCount).To(BeZero())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionRegionUnavailable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("FallingBack"))
+
+			By("creating the account in the fallback region")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			creates := executor.statementsWithPrefix("CREATE ACCOUNT")
+			Expect(creates).To(HaveLen(2))
+			Expect(creates[1]).To(ContainSubstring("REGION = 'AWS_EU_CENTRAL_1'"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AccountCreated).To(BeTrue())
+			Expect(resource.Status.Region).To(Equal("AWS_EU_CENTRAL_1"))
+			Expect(resource.Status.UnavailableRegions).To(BeEmpty())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, conditionRegionUnavailable)).To(BeTrue())
+		})
+
+		It("should wait longer when no fallback region is left", func() {
+			fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+			controllerReconciler.Clock = fakeClock
+			executor.errFor = func(statement string) error {
+				if strings.HasPrefix(strings.TrimSpace(statement), "CREATE ACCOUNT") {
+					return &gosnowflake.SnowflakeError{Number: 1003, Message: "The requested region is temporarily unavailable."}
+				}
+				return nil
+			}
+
+			var result reconcile.Result
+			for range 2 {
+				var err error
+				result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(result.RequeueAfter).To(Equal(regionUnavailableRequeueInterval))
+
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Phase).To(Equal(operatorv1alpha1.PhasePending))
+			Expect(resource.Status.UnavailableRegions).To(BeEmpty())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionRegionUnavailable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("NoFallbackRegion"))
+
+			By("giving up once the region stays unavailable for longer than the provisioning duration")
+			fakeClock.SetTime(fakeClock.Now().Add(operatorv1alpha1.DefaultMaxProvisioningDuration))
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.statementsWithPrefix("CREATE ACCOUNT")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, conditionFailed).Reason).To(Equal("ProvisioningTimeout"))
+		})
+
+		It("should apply the credentials secret as the operator's field manager", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "APPLYACCT"
+			resource.Spec.SplitCredentials = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "applyacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+				configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "applyacct-conninfo", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+			})
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			appliedBy := func(obj client.Object) []string {
+				var managers []string
+				for _, entry := range obj.GetManagedFields() {
+					if entry.Operation == metav1.ManagedFieldsOperationApply {
+						managers = append(managers, entry.Manager)
+					}
+				}
+				return managers
+			}
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "applyacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(appliedBy(secret)).To(ConsistOf(fieldManager))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "applyacct-conninfo", Namespace: "default"}, configMap)).To(Succeed())
+			Expect(appliedBy(configMap)).To(ConsistOf(fieldManager))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.CredentialsSecret.ResourceVersion).To(Equal(secret.ResourceVersion))
+		})
+
+		It("should recreate a deleted credentials secret", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "LOSTACCT"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			executor.accounts = map[string]map[string]string{
+				"LOSTACCT": {"account_name": "LOSTACCT", "account_locator": "LO12345",
+					"snowflake_region": "AWS_US_WEST_2", "edition": "ENTERPRISE"},
+			}
+			secretKey := types.NamespacedName{Name: "lostacct-creds", Namespace: "default"}
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			adminName := string(secret.Data["adminName"])
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.AdminName).To(Equal(adminName))
+
+			By("deleting the credentials secret")
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler.Recorder = recorder
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			recreated := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, recreated)).To(Succeed())
+			Expect(recreated.Data).To(HaveKeyWithValue("accountName", []byte("LOSTACCT")))
+			Expect(recreated.Data).To(HaveKeyWithValue("adminName", []byte(adminName)))
+			Expect(recreated.Data).To(HaveKeyWithValue("accountLocator", []byte("LO12345")))
+			Expect(recreated.Data).To(HaveKeyWithValue("edition", []byte("ENTERPRISE")))
+			Expect(recreated.Data).NotTo(HaveKey("adminPassword"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionSecretRecreated)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("PasswordLost"))
+			Expect(resource.Status.CredentialsSecret.ResourceVersion).To(Equal(recreated.ResourceVersion))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning SecretRecreated")))
+		})
+
+		It("should not overwrite a credentials secret that lost its labels", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "UNLABELED"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			secretKey := types.NamespacedName{Name: "unlabeled-creds", Namespace: "default"}
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			password := secret.Data["adminPassword"]
+			Expect(password).NotTo(BeEmpty())
+
+			By("removing the labels of the secret and its record in the status")
+			secret.Labels = nil
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Status.CredentialsSecret = nil
+			Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("adminPassword", password))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.FindStatusCondition(resource.Status.Conditions, conditionSecretRecreated)).To(BeNil())
+			Expect(resource.Status.CredentialsSecret.Name).To(Equal(secretKey.Name))
+		})
+
+		It("should keep a password changed by an interrupted attempt", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "PWDACCT"
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pwdacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			By("rejecting the initial password as if it had already been changed")
+			executor.errFor = func(statement string) error {
+				if strings.Contains(statement, "SET PASSWORD") {
+					return &gosnowflake.SnowflakeError{Number: 390100, Message: "Incorrect username or password was specified."}
+				}
+				return nil
+			}
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("ALTER USER")).To(ContainElement(HaveSuffix("SET MUST_CHANGE_PASSWORD = FALSE")))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pwdacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(executor.statementsWithPrefix("ALTER USER")[0]).To(ContainSubstring(string(secret.Data["adminPassword"])))
+			Expect(secret.Data).NotTo(HaveKey(pendingAdminPasswordKey))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionPasswordChanged)).To(BeTrue())
+		})
+
+		It("should log in as the admin only once the initial password has been changed", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "PWDACCT"
+			resource.Spec.VerifyLogin = true
+			resource.Spec.PostCreateSQL = []string{"CREATE WAREHOUSE ADHOC"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pwdacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			By("skipping the check while the admin must change the password")
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("SELECT CURRENT_ACCOUNT()")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			verified := meta.FindStatusCondition(resource.Status.Conditions, conditionVerified)
+			Expect(verified).NotTo(BeNil())
+			Expect(verified.Status).To(Equal(metav1.ConditionFalse))
+			Expect(verified.Reason).To(Equal("Skipped"))
+			Expect(executor.statementsWithPrefix("CREATE WAREHOUSE")).To(BeEmpty())
+			postCreateSQL := meta.FindStatusCondition(resource.Status.Conditions, conditionPostCreateSQLApplied)
+			Expect(postCreateSQL).NotTo(BeNil())
+			Expect(postCreateSQL.Reason).To(Equal("Skipped"))
+
+			By("verifying the login after the operator changed the password")
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executor.statementsWithPrefix("SELECT CURRENT_ACCOUNT()")).To(HaveLen(1))
+			Expect(executor.statementsWithPrefix("CREATE WAREHOUSE")).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionVerified)).To(BeTrue())
+		})
+
+		It("should enable ORGADMIN in the account and grant it to the admin", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "ORGACCT"
+			resource.Spec.GrantOrgAdmin = true
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "orgacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			By("rejecting the grant while the operator does not allow it")
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT ORGACCT")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionOrgAdminGranted)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("--allow-org-admin-grants"))
+
+			By("granting it once allowed")
+			controllerReconciler.AllowOrgAdminGrants = true
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "orgacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT ORGACCT")).To(Equal([]string{"ALTER ACCOUNT ORGACCT SET IS_ORG_ADMIN = TRUE"}))
+			Expect(executor.statementsWithPrefix("GRANT ROLE ORGADMIN")).To(Equal([]string{
+				"GRANT ROLE ORGADMIN TO USER " + userIdentifier(string(secret.Data["adminName"])),
+			}))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionOrgAdminGranted)).To(BeTrue())
+		})
+
+		It("should store the account details with the configured credentials sink", func() {
+			sink := &recordingSink{}
+			controllerReconciler.CredentialsSinks = map[operatorv1alpha1.CredentialsSinkType]CredentialsSink{
+				operatorv1alpha1.CredentialsSinkKubernetes: sink,
+			}
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "SINKACCT"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(sink.written).To(HaveLen(1))
+			Expect(sink.written[0].AccountName).To(Equal("SINKACCT"))
+			Expect(sink.written[0].AdminPassword).NotTo(BeEmpty())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "sinkacct-creds", Namespace: "default"}, &corev1.Secret{})).NotTo(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionSecretReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("Credentials stored in recording sink"))
+
+			By("logging in to the account with the credentials read back from the sink")
+			creds, adminName, err := controllerReconciler.getChildAccountCredentials(ctx, resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminName).To(Equal(sink.written[0].AdminName))
+			Expect(creds.password).To(Equal(sink.written[0].AdminPassword))
+
+			By("rejecting a sink the operator does not have")
+			resource.Spec.CredentialsSink = "Vault"
+			_, err = controllerReconciler.credentialsSink(resource)
+			Expect(err).To(MatchError(ContainSubstring(`credentials sink "Vault" is not available`)))
+		})
+
+		It("should set the admin's default role and warehouse once the warehouse exists", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "DEFACCT"
+			resource.Spec.AdminDefaultRole = "SYSADMIN"
+			resource.Spec.AdminDefaultWarehouse = "ANALYTICS_WH"
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "defacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			executor.errFor = func(statement string) error {
+				if statement == "DESCRIBE WAREHOUSE ANALYTICS_WH" {
+					return &gosnowflake.SnowflakeError{Number: 2003, Message: "Warehouse 'ANALYTICS_WH' does not exist or not authorized."}
+				}
+				return nil
+			}
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionAdminDefaultsApplied)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("default warehouse ANALYTICS_WH is not available"))
+			Expect(executor.statementsWithPrefix("ALTER USER")).NotTo(ContainElement(ContainSubstring("DEFAULT_ROLE")))
+
+			By("creating the warehouse")
+			executor.errFor = nil
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(Equal(bootstrapRetryInterval))
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "defacct-creds", Namespace: "default"}, secret)).To(Succeed())
+			Expect(executor.statementsWithPrefix("ALTER USER")).To(ContainElement(
+				"ALTER USER " + userIdentifier(string(secret.Data["adminName"])) + " SET DEFAULT_ROLE = SYSADMIN DEFAULT_WAREHOUSE = ANALYTICS_WH",
+			))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionAdminDefaultsApplied)).To(BeTrue())
+		})
+
+		It("should set the account's timezone and default collation after provisioning", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "TZACCT"
+			resource.Spec.Timezone = "Europe/Berlin"
+			resource.Spec.DefaultCollation = "de"
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tzacct-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("ALTER ACCOUNT SET")).To(Equal([]string{
+				"ALTER ACCOUNT SET TIMEZONE = 'Europe/Berlin' DEFAULT_DDL_COLLATION = 'de'",
+			}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionAccountParametersApplied)).To(BeTrue())
+		})
+
+		It("should create the monitoring user and store its credentials in a separate secret", func() {
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.DesiredAccountName = "MONACCT"
+			resource.Spec.MonitoringUser = &operatorv1alpha1.MonitoringUser{Name: "METRICS"}
+			resource.Spec.AutoCompletePasswordChange = true
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				for _, name := range []string{"monacct-creds", "monacct-monitoring"} {
+					secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+				}
+			})
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "monacct-monitoring", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data["accountName"])).To(Equal("MONACCT"))
+			Expect(string(secret.Data["username"])).To(Equal("METRICS"))
+			Expect(string(secret.Data["role"])).To(Equal("MONITORING"))
+			password := string(secret.Data["password"])
+			Expect(password).NotTo(BeEmpty())
+			Expect(secret.Labels).NotTo(HaveKey("app.kubernetes.io/instance"))
+
+			Expect(executor.statementsWithPrefix(`CREATE USER IF NOT EXISTS "METRICS"`)).To(Equal([]string{
+				`CREATE USER IF NOT EXISTS "METRICS" PASSWORD = '` + password + "' MUST_CHANGE_PASSWORD = FALSE",
+			}))
+			Expect(executor.statementsWithPrefix(`GRANT ROLE "MONITORING"`)).To(Equal([]string{`GRANT ROLE "MONITORING" TO USER "METRICS"`}))
+
+			credentials := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "monacct-creds", Namespace: "default"}, credentials)).To(Succeed())
+			Expect(string(credentials.Data["adminPassword"])).NotTo(Equal(password))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, conditionMonitoringUserCreated)).To(BeTrue())
+		})
+
+		It("should not create a monitoring user named like the admin", func() {
+			controllerReconciler.Generator = fixedGenerator{accountName: "SFFIXED1"}
+			resource := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.MonitoringUser = &operatorv1alpha1.MonitoringUser{Name: "ADMIN_FIXED"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sffixed1-creds", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			})
+
+			for range 3 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(executor.statementsWithPrefix("CREATE USER")).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, conditionMonitoringUserCreated)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("InvalidSpec"))
+			Expect(condition.Message).To(ContainSubstring("must not be the admin user"))
+		})
+	})
 })
+
+// recordingSink is a CredentialsSink that keeps the written account details in memory
+type recordingSink struct {
+	written []AccountCredentials
+}
+
+func (s *recordingSink) Write(_ context.Context, _ *operatorv1alpha1.SnowflakeAccount, credentials *AccountCredentials) (string, error) {
+	s.written = append(s.written, *credentials)
+	return "recording sink", nil
+}
+
+func (s *recordingSink) Read(context.Context, *operatorv1alpha1.SnowflakeAccount) (*AccountCredentials, error) {
+	if len(s.written) == 0 {
+		return nil, nil
+	}
+	return &s.written[len(s.written)-1], nil
+}
+
+func (s *recordingSink) Delete(context.Context, *operatorv1alpha1.SnowflakeAccount) error {
+	s.written = nil
+	return nil
+}
+
+// failingStatusClient fails the status updates selected by fail, to simulate a transient API server error
+type failingStatusClient struct {
+	client.Client
+	fail func(obj client.Object) bool
+}
+
+func (c *failingStatusClient) Status() client.SubResourceWriter {
+	return &failingStatusWriter{SubResourceWriter: c.Client.Status(), fail: c.fail}
+}
+
+type failingStatusWriter struct {
+	client.SubResourceWriter
+	fail func(obj client.Object) bool
+}
+
+func (w *failingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if w.fail(obj) {
+		return errors.NewServiceUnavailable("status updates are unavailable")
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// failingCreateClient fails to create secrets, to simulate a transient API server error
+type failingCreateClient struct {
+	client.Client
+
+	// secretName is the only secret that cannot be created; if empty, no secret can be created
+	secretName string
+}
+
+func (c *failingCreateClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.Secret); ok && (c.secretName == "" || obj.GetName() == c.secretName) {
+		return errors.NewServiceUnavailable("creating secrets is unavailable")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
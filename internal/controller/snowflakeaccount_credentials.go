@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkCredentials reports whether the organization credentials for the account can be loaded.
+// When they cannot, the CredentialsMissing condition is set so the misconfiguration is visible on the
+// resource; a previously set condition is cleared once the credentials are found.
+func (r *SnowflakeAccountReconciler) checkCredentials(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:               conditionCredentialsMissing,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CredentialsFound",
+		Message:            "Organization credentials are configured",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+
+	_, credsErr := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if credsErr != nil {
+		log.Info("Organization credentials are missing, not reconciling", "reason", credsErr.Error())
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "CredentialsNotFound"
+		condition.Message = fmt.Sprintf("Organization credentials could not be loaded: %v", credsErr)
+	} else if meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionCredentialsMissing) == nil {
+		// Only report the condition once a problem has been seen
+		return true, nil
+	}
+
+	if meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition) {
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			return credsErr == nil, err
+		}
+	}
+	return credsErr == nil, nil
+}
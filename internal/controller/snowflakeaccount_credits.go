@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// creditsUsedQuery sums the credits used by the account since it was created. The ACCOUNT_USAGE views lag
+// by up to 3 hours, so the most recent usage is only counted by a later sync.
+const creditsUsedQuery = "SELECT COALESCE(SUM(CREDITS_USED), 0) AS CREDITS_USED FROM SNOWFLAKE.ACCOUNT_USAGE.METERING_HISTORY"
+
+// syncCredits records the credits used by an account with Spec.TrackCredits in Status.CreditsUsed, at most
+// once per CreditSyncInterval, and reports the outcome in the CreditsSynced condition. A failed sync keeps
+// the last figure and is retried after the interval; the usage views of a new account may not be readable
+// yet, and the admin cannot query them until the initial password has been changed. It returns how long to
+// wait before the next sync, which is zero when credits are not tracked.
+func (r *SnowflakeAccountReconciler) syncCredits(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	interval := r.CreditSyncInterval
+	if interval <= 0 || !snowflakeAccount.Spec.TrackCredits {
+		return 0, nil
+	}
+
+	// Failed syncs are not recorded in the status, so they are throttled in memory
+	now := r.Clock.Now()
+	lastSync := time.Time{}
+	if snowflakeAccount.Status.LastCostSync != nil {
+		lastSync = snowflakeAccount.Status.LastCostSync.Time
+	}
+	if lastAttempt, ok := r.creditSyncs.Load(snowflakeAccount.UID); ok && lastAttempt.(time.Time).After(lastSync) {
+		lastSync = lastAttempt.(time.Time)
+	}
+	if wait := lastSync.Add(interval).Sub(now); wait > 0 {
+		return wait, nil
+	}
+	r.creditSyncs.Store(snowflakeAccount.UID, now)
+
+	condition := metav1.Condition{
+		Type:               conditionCreditsSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if reason := adminLoginBlocker(snowflakeAccount); reason != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Skipped"
+		condition.Message = reason
+	} else if credits, err := r.queryCreditsUsed(ctx, snowflakeAccount); err != nil {
+		log.Info("Failed to sync credits used, retrying", "after", interval, "error", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncFailed"
+		condition.Message = err.Error()
+	} else {
+		snowflakeAccount.Status.CreditsUsed = credits
+		snowflakeAccount.Status.LastCostSync = &metav1.Time{Time: now}
+		condition.Message = fmt.Sprintf("%s credits used; usage of the last 3 hours may not be included yet", credits)
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition)
+
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		return 0, err
+	}
+	return interval, nil
+}
+
+// queryCreditsUsed returns the credits used by the account, queried as its admin
+func (r *SnowflakeAccountReconciler) queryCreditsUsed(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return "", err
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	rows, err := r.snowflake().Query(queryCtx, creds, creditsUsedQuery)
+	if err != nil {
+		return "", fmt.Errorf("failed to query credits used: %w", err)
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("querying credits used returned no rows")
+	}
+	credits, err := strconv.ParseFloat(rows[0]["credits_used"], 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid credits used %q: %w", rows[0]["credits_used"], err)
+	}
+	return strconv.FormatFloat(credits, 'f', -1, 64), nil
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// dnsWaitTimeout is how long to wait for the account hostname to resolve before giving up
+	dnsWaitTimeout = 15 * time.Minute
+	// dnsMinRequeue and dnsMaxRequeue bound the backoff between DNS checks
+	dnsMinRequeue = 5 * time.Second
+	dnsMaxRequeue = 1 * time.Minute
+	// dnsLookupTimeout bounds a single DNS lookup
+	dnsLookupTimeout = 10 * time.Second
+)
+
+// startWaitingForAccountDNS records the provisioned account in the status without marking it
+// as created, so that subsequent reconciles wait for its hostname to resolve
+func (r *SnowflakeAccountReconciler) startWaitingForAccountDNS(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, details *accountDetails) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	setAccountDetailsStatus(snowflakeAccount, details)
+	snowflakeAccount.Status.Message = "Snowflake account provisioned, waiting for DNS to resolve"
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionDNSResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WaitingForDNS",
+		Message:            fmt.Sprintf("Waiting for %s to resolve", details.accountURL),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status while waiting for DNS")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Waiting for account DNS to resolve", "accountURL", details.accountURL)
+	return ctrl.Result{RequeueAfter: dnsMinRequeue}, nil
+}
+
+// reconcileAccountDNS checks whether the account hostname resolves and marks the account as created
+// once it does, or once the wait times out
+func (r *SnowflakeAccountReconciler) reconcileAccountDNS(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Measure the wait from when the condition was first recorded
+	waitingSince := r.Clock.Now()
+	if condition := meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionDNSResolved); condition != nil {
+		waitingSince = condition.LastTransitionTime.Time
+	}
+	elapsed := r.Clock.Now().Sub(waitingSince)
+
+	lookupErr := lookupAccountHost(ctx, snowflakeAccount.Status.AccountURL)
+	if lookupErr != nil && elapsed < dnsWaitTimeout {
+		// Back off proportionally to how long we have been waiting
+		requeueAfter := min(max(elapsed/2, dnsMinRequeue), dnsMaxRequeue)
+		log.Info("Account hostname does not resolve yet, requeuing",
+			"accountURL", snowflakeAccount.Status.AccountURL,
+			"elapsed", elapsed,
+			"after", requeueAfter,
+			"reason", lookupErr.Error())
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionDNSResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Resolved",
+		Message:            fmt.Sprintf("%s resolves", snowflakeAccount.Status.AccountURL),
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if lookupErr != nil {
+		// The account exists in Snowflake, so stop blocking on DNS and report the timeout
+		log.Info("Timed out waiting for account hostname to resolve", "accountURL", snowflakeAccount.Status.AccountURL, "elapsed", elapsed)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DNSResolutionTimeout"
+		condition.Message = fmt.Sprintf("%s did not resolve within %s: %v", snowflakeAccount.Status.AccountURL, dnsWaitTimeout, lookupErr)
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition)
+
+	snowflakeAccount.Status.AccountCreated = true
+	snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseReady
+	snowflakeAccount.Status.Message = "Snowflake account created successfully"
+	now := metav1.Now()
+	snowflakeAccount.Status.CreationTime = &now
+
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status after account creation")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Successfully created Snowflake account and stored credentials", "accountName", snowflakeAccount.Status.AccountName)
+	return r.expiryRequeue(ctx, snowflakeAccount), nil
+}
+
+// lookupAccountHost resolves the hostname of the account URL
+func lookupAccountHost(ctx context.Context, accountURL string) error {
+	parsed, err := url.Parse(accountURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse account URL: %w", err)
+	}
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("account URL %q has no hostname", accountURL)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	defer cancel()
+
+	if _, err := net.DefaultResolver.LookupHost(lookupCtx, parsed.Hostname()); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// Sentinel errors describing classes of Snowflake failures; match them with errors.Is
+var (
+	// ErrSnowflakeAuth indicates Snowflake rejected the credentials used to connect
+	ErrSnowflakeAuth = errors.New("snowflake authentication failed")
+	// ErrAccountExists indicates an account with the requested name already exists
+	ErrAccountExists = errors.New("snowflake account already exists")
+	// ErrSnowflakeTimeout indicates a Snowflake operation did not finish in time
+	ErrSnowflakeTimeout = errors.New("snowflake operation timed out")
+	// ErrRegionUnavailable indicates the requested region cannot host new accounts right now,
+	// for example because it is under maintenance
+	ErrRegionUnavailable = errors.New("snowflake region unavailable")
+)
+
+// Snowflake error codes used to classify failures
+const (
+	// snowflakeErrIncorrectCredentials is returned for an incorrect username or password
+	snowflakeErrIncorrectCredentials = 390100
+	// snowflakeErrUserLocked is returned when the user is temporarily locked
+	snowflakeErrUserLocked = 390101
+	// snowflakeErrUserDisabled is returned when the user is disabled
+	snowflakeErrUserDisabled = 390102
+	// snowflakeErrInvalidJWT is returned when a key-pair JWT is rejected
+	snowflakeErrInvalidJWT = 390144
+	// snowflakeErrInvalidOAuthToken is returned when an OAuth access token is invalid
+	snowflakeErrInvalidOAuthToken = 390303
+	// snowflakeErrExpiredOAuthToken is returned when an OAuth access token has expired
+	snowflakeErrExpiredOAuthToken = 390318
+	// snowflakeErrObjectExists is returned when creating an object that already exists
+	snowflakeErrObjectExists = 2002
+	// snowflakeErrStatementTimeout is returned when a statement reaches its timeout and is canceled
+	snowflakeErrStatementTimeout = 630
+)
+
+// regionUnavailablePattern matches the messages Snowflake returns when a region temporarily cannot take
+// new accounts. They come with different error codes, so they are recognized by their message; only
+// transient wording matches, as a region that is not available to the organization at all will not become
+// available by waiting.
+var regionUnavailablePattern = regexp.MustCompile(`(?i)region\b.*\b(temporarily unavailable|under maintenance)`)
+
+// classifySnowflakeError wraps err with the sentinel error for its class, if it is recognized.
+// The original error is preserved, so both errors.Is and errors.As keep working.
+func classifySnowflakeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrSnowflakeTimeout, err)
+	}
+
+	var sfErr *gosnowflake.SnowflakeError
+	if !errors.As(err, &sfErr) {
+		return err
+	}
+
+	switch sfErr.Number {
+	case snowflakeErrIncorrectCredentials, snowflakeErrUserLocked, snowflakeErrUserDisabled,
+		snowflakeErrInvalidJWT, snowflakeErrInvalidOAuthToken, snowflakeErrExpiredOAuthToken:
+		return fmt.Errorf("%w: %w", ErrSnowflakeAuth, err)
+	case snowflakeErrObjectExists:
+		return fmt.Errorf("%w: %w", ErrAccountExists, err)
+	case snowflakeErrStatementTimeout:
+		return fmt.Errorf("%w: %w", ErrSnowflakeTimeout, err)
+	}
+
+	if regionUnavailablePattern.MatchString(sfErr.Message) {
+		return fmt.Errorf("%w: %w", ErrRegionUnavailable, err)
+	}
+
+	return err
+}
+
+// snowflakeQueryID returns the ID of the query that failed with err, or "" if Snowflake did not assign one
+func snowflakeQueryID(err error) string {
+	var sfErr *gosnowflake.SnowflakeError
+	if errors.As(err, &sfErr) {
+		return sfErr.QueryID
+	}
+	return ""
+}
+
+// queryIDSuffix formats a query ID for error messages, so it can be quoted to Snowflake support
+func queryIDSuffix(queryID string) string {
+	if queryID == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (query ID %s)", queryID)
+}
+
+// isOutcomeUnknown reports whether a statement failed because the operator stopped waiting for it, when ctx
+// expired or was cancelled, rather than because Snowflake rejected it, so it may still have completed
+func isOutcomeUnknown(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// isNameCollision reports whether err means an account with the requested name already exists
+func isNameCollision(err error) bool {
+	return errors.Is(err, ErrAccountExists)
+}
+
+// isRegionUnavailable reports whether err means the account's region could not take new accounts
+func isRegionUnavailable(err error) bool {
+	return errors.Is(err, ErrRegionUnavailable)
+}
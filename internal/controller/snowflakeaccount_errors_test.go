@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+var _ = Describe("classifySnowflakeError", func() {
+	DescribeTable("mapping Snowflake failures to sentinel errors",
+		func(err error, expected error) {
+			classified := classifySnowflakeError(err)
+			Expect(errors.Is(classified, expected)).To(BeTrue())
+			Expect(errors.Is(classified, err)).To(BeTrue())
+		},
+		Entry("incorrect credentials", &gosnowflake.SnowflakeError{Number: 390100}, ErrSnowflakeAuth),
+		Entry("expired OAuth token", &gosnowflake.SnowflakeError{Number: 390318}, ErrSnowflakeAuth),
+		Entry("account already exists", fmt.Errorf("exec: %w", &gosnowflake.SnowflakeError{Number: 2002}), ErrAccountExists),
+		Entry("statement timeout", &gosnowflake.SnowflakeError{Number: 630}, ErrSnowflakeTimeout),
+		Entry("context deadline", context.DeadlineExceeded, ErrSnowflakeTimeout),
+		Entry("region under maintenance", &gosnowflake.SnowflakeError{
+			Number: 1003, Message: "Region AWS_US_WEST_2 is currently under maintenance"}, ErrRegionUnavailable),
+		Entry("region temporarily unavailable", &gosnowflake.SnowflakeError{
+			Number: 2003, Message: "The region GCP_US_CENTRAL1 is temporarily unavailable for account creation"}, ErrRegionUnavailable),
+	)
+
+	It("should leave unrecognized errors unchanged", func() {
+		err := &gosnowflake.SnowflakeError{Number: 1003}
+		Expect(classifySnowflakeError(err)).To(BeIdenticalTo(err))
+		Expect(classifySnowflakeError(nil)).To(Succeed())
+	})
+
+	It("should not treat a region that is not available to the organization as temporarily unavailable", func() {
+		err := &gosnowflake.SnowflakeError{Number: 2003, Message: "The region GCP_US_CENTRAL1 is not available for your organization"}
+		Expect(classifySnowflakeError(err)).To(BeIdenticalTo(err))
+	})
+})
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// SnowflakeExecutor runs statements against Snowflake on behalf of the reconciler.
+// The default implementation talks to Snowflake through gosnowflake; tests can
+// substitute a fake to assert which SQL was issued without a live Snowflake.
+type SnowflakeExecutor interface {
+	// ExecAccount runs a CREATE ACCOUNT statement and returns its Snowflake query ID, which is also
+	// returned on failure when Snowflake assigned one
+	ExecAccount(ctx context.Context, creds *snowflakeCredentials, statement string) (string, error)
+	// DropAccount runs a DROP ACCOUNT statement and returns its query ID like ExecAccount
+	DropAccount(ctx context.Context, creds *snowflakeCredentials, statement string) (string, error)
+	// Exec runs any other statement, such as ALTER ACCOUNT or UNDROP ACCOUNT
+	Exec(ctx context.Context, creds *snowflakeCredentials, statement string) error
+	// ShowAccounts runs SHOW ACCOUNTS LIKE '<pattern>' and returns each row keyed by lowercase column name
+	ShowAccounts(ctx context.Context, creds *snowflakeCredentials, pattern string) ([]map[string]string, error)
+	// ShowGrants runs SHOW GRANTS TO ROLE <role>, with the role name as stored in Snowflake, and returns
+	// each row keyed by lowercase column name
+	ShowGrants(ctx context.Context, creds *snowflakeCredentials, role string) ([]map[string]string, error)
+	// Query runs a SELECT statement and returns each row keyed by lowercase column name
+	Query(ctx context.Context, creds *snowflakeCredentials, query string) ([]map[string]string, error)
+}
+
+// snowflake returns the executor used to run Snowflake statements
+func (r *SnowflakeAccountReconciler) snowflake() SnowflakeExecutor {
+	if r.Executor != nil {
+		return r.Executor
+	}
+	return &gosnowflakeExecutor{
+		connections: &r.connections,
+		application: r.ApplicationName,
+		pool: connectionPoolSettings{
+			maxOpenConns:    r.SnowflakeMaxOpenConns,
+			maxIdleConns:    r.SnowflakeMaxIdleConns,
+			connMaxLifetime: r.SnowflakeConnMaxLifetime,
+		},
+	}
+}
+
+// gosnowflakeExecutor is the SnowflakeExecutor backed by gosnowflake and the reconciler's connection cache
+type gosnowflakeExecutor struct {
+	connections *snowflakeConnectionCache
+	// application identifies the operator's connections in Snowflake's query history
+	application string
+	// pool bounds the connections of newly opened pools
+	pool connectionPoolSettings
+}
+
+// connect returns a connection for the credentials and a function releasing it once the caller is done.
+// Connections to the organization are cached and reused. A child account is only connected to for a few
+// statements, and there may be thousands of them, so its connection is opened for the caller and closed
+// when released.
+func (e *gosnowflakeExecutor) connect(ctx context.Context, creds *snowflakeCredentials) (*sql.DB, func(), error) {
+	if e.application != "" && creds.application == "" {
+		withApplication := *creds
+		withApplication.application = e.application
+		creds = &withApplication
+	}
+	if creds.childAccount {
+		db, err := connectToSnowflake(creds)
+		if err != nil {
+			return nil, nil, err
+		}
+		e.pool.apply(db)
+		return db, func() { _ = db.Close() }, nil
+	}
+	db, err := e.connections.get(ctx, creds, e.pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() {}, nil
+}
+
+// ExecAccount runs a CREATE ACCOUNT statement and returns its query ID
+func (e *gosnowflakeExecutor) ExecAccount(ctx context.Context, creds *snowflakeCredentials, statement string) (string, error) {
+	return e.exec(ctx, creds, statement)
+}
+
+// DropAccount runs a DROP ACCOUNT statement and returns its query ID
+func (e *gosnowflakeExecutor) DropAccount(ctx context.Context, creds *snowflakeCredentials, statement string) (string, error) {
+	return e.exec(ctx, creds, statement)
+}
+
+// Exec runs a statement that returns no rows
+func (e *gosnowflakeExecutor) Exec(ctx context.Context, creds *snowflakeCredentials, statement string) error {
+	_, err := e.exec(ctx, creds, statement)
+	return err
+}
+
+// exec runs a statement that returns no rows and returns its query ID, taken from the error if it failed
+func (e *gosnowflakeExecutor) exec(ctx context.Context, creds *snowflakeCredentials, statement string) (string, error) {
+	// Get a connection, reusing a cached one to the organization if available
+	db, release, err := e.connect(ctx, creds)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	queryID, err := execQueryID(ctx, db, statement)
+	if err != nil {
+		return snowflakeQueryID(err), creds.redactError(err)
+	}
+	return queryID, nil
+}
+
+// execQueryID runs a statement that returns no rows and returns its query ID. database/sql wraps the result
+// of the driver, so the statement is run on the driver connection itself to read gosnowflake's SnowflakeResult.
+func execQueryID(ctx context.Context, db *sql.DB, statement string) (string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var queryID string
+	err = conn.Raw(func(driverConn any) error {
+		execer, ok := driverConn.(driver.ExecerContext)
+		if !ok {
+			return fmt.Errorf("driver connection %T cannot execute statements", driverConn)
+		}
+		result, err := execer.ExecContext(ctx, statement, nil)
+		if err != nil {
+			return err
+		}
+		if sfResult, ok := result.(gosnowflake.SnowflakeResult); ok {
+			queryID = sfResult.GetQueryID()
+		}
+		return nil
+	})
+	return queryID, err
+}
+
+// ShowAccounts runs SHOW ACCOUNTS LIKE '<pattern>' and returns each row keyed by lowercase column name
+func (e *gosnowflakeExecutor) ShowAccounts(ctx context.Context, creds *snowflakeCredentials, pattern string) ([]map[string]string, error) {
+	return e.query(ctx, creds, "SHOW ACCOUNTS", fmt.Sprintf(`SHOW ACCOUNTS LIKE '%s'`, escapeSQLString(pattern)))
+}
+
+// ShowGrants runs SHOW GRANTS TO ROLE <role> and returns each row keyed by lowercase column name
+func (e *gosnowflakeExecutor) ShowGrants(ctx context.Context, creds *snowflakeCredentials, role string) ([]map[string]string, error) {
+	return e.query(ctx, creds, "SHOW GRANTS", fmt.Sprintf("SHOW GRANTS TO ROLE %s", quoteIdentifier(role)))
+}
+
+// Query runs a SELECT statement and returns each row keyed by lowercase column name
+func (e *gosnowflakeExecutor) Query(ctx context.Context, creds *snowflakeCredentials, query string) ([]map[string]string, error) {
+	return e.query(ctx, creds, "SELECT", query)
+}
+
+// query runs a SHOW command or SELECT and returns each row keyed by lowercase column name; command names it in errors
+func (e *gosnowflakeExecutor) query(ctx context.Context, creds *snowflakeCredentials, command, query string) ([]map[string]string, error) {
+	// Get a connection, reusing a cached one to the organization if available
+	db, release, err := e.connect(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s: %w", command, classifySnowflakeError(creds.redactError(err)))
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s columns: %w", command, err)
+	}
+
+	var results []map[string]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		scanArgs := make([]any, len(columns))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", command, err)
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			row[strings.ToLower(column)] = values[i].String
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate %s rows: %w", command, creds.redactError(err))
+	}
+
+	return results, nil
+}
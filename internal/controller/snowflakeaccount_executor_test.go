@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/snowflakedb/gosnowflake"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// fakeSnowflakeExecutor records the statements it is asked to run instead of talking to Snowflake
+type fakeSnowflakeExecutor struct {
+	mu sync.Mutex
+
+	// statements holds every statement executed, in order
+	statements []string
+	// accounts is returned by ShowAccounts, keyed by upper-case account name
+	accounts map[string]map[string]string
+	// grants are returned by ShowGrants for each role; roles without an entry have no grants
+	grants map[string][]map[string]string
+	// err, if set, is returned by every call
+	err error
+	// onExec, if set, is called with each statement before it is recorded
+	onExec func(statement string)
+	// errFor, if set, returns the error for a statement, overriding err when non-nil
+	errFor func(statement string) error
+	// rowsFor, if set, returns the rows of a query run with Query
+	rowsFor func(query string) []map[string]string
+}
+
+func (f *fakeSnowflakeExecutor) record(statement string) error {
+	if f.onExec != nil {
+		f.onExec(statement)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, strings.TrimSpace(statement))
+	if f.errFor != nil {
+		if err := f.errFor(statement); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func (f *fakeSnowflakeExecutor) ExecAccount(_ context.Context, _ *snowflakeCredentials, statement string) (string, error) {
+	return f.recordQuery(statement)
+}
+
+func (f *fakeSnowflakeExecutor) DropAccount(_ context.Context, _ *snowflakeCredentials, statement string) (string, error) {
+	return f.recordQuery(statement)
+}
+
+// recordQuery records the statement and returns a query ID like Snowflake, numbered by statement
+func (f *fakeSnowflakeExecutor) recordQuery(statement string) (string, error) {
+	if err := f.record(statement); err != nil {
+		return snowflakeQueryID(err), err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("query-%d", len(f.statements)), nil
+}
+
+func (f *fakeSnowflakeExecutor) Exec(_ context.Context, _ *snowflakeCredentials, statement string) error {
+	return f.record(statement)
+}
+
+func (f *fakeSnowflakeExecutor) ShowAccounts(_ context.Context, _ *snowflakeCredentials, pattern string) ([]map[string]string, error) {
+	if err := f.record("SHOW ACCOUNTS LIKE '" + pattern + "'"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if pattern == "%" {
+		var rows []map[string]string
+		for _, row := range f.accounts {
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+	if row, ok := f.accounts[strings.ToUpper(pattern)]; ok {
+		return []map[string]string{row}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeSnowflakeExecutor) ShowGrants(_ context.Context, _ *snowflakeCredentials, role string) ([]map[string]string, error) {
+	if err := f.record("SHOW GRANTS TO ROLE " + quoteIdentifier(role)); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.grants[role], nil
+}
+
+func (f *fakeSnowflakeExecutor) Query(_ context.Context, _ *snowflakeCredentials, query string) ([]map[string]string, error) {
+	if err := f.record(query); err != nil {
+		return nil, err
+	}
+	if f.rowsFor == nil {
+		return nil, nil
+	}
+	return f.rowsFor(query), nil
+}
+
+// statementsWithPrefix returns the recorded statements starting with the given prefix
+func (f *fakeSnowflakeExecutor) statementsWithPrefix(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matches []string
+	for _, statement := range f.statements {
+		if strings.HasPrefix(statement, prefix) {
+			matches = append(matches, statement)
+		}
+	}
+	return matches
+}
+
+// fixedGenerator returns the same names and password every time
+type fixedGenerator struct {
+	accountName string
+	password    string
+}
+
+func (g fixedGenerator) AccountName(int, operatorv1alpha1.AccountNameCharset) (string, error) {
+	return g.accountName, nil
+}
+
+func (fixedGenerator) Username() string {
+	return "admin_fixed"
+}
+
+func (g fixedGenerator) Password() string {
+	if g.password != "" {
+		return g.password
+	}
+	return "Fixed-Passw0rd"
+}
+
+// queryIDDriver is a database/sql driver whose statements succeed with a gosnowflake.SnowflakeResult, like
+// gosnowflake's own results
+type queryIDDriver struct{}
+
+func (queryIDDriver) Open(string) (driver.Conn, error) {
+	return queryIDConn{}, nil
+}
+
+type queryIDConn struct{}
+
+func (queryIDConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepared statements are not supported")
+}
+
+func (queryIDConn) Close() error {
+	return nil
+}
+
+func (queryIDConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+
+func (queryIDConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "DROP") {
+		return nil, &gosnowflake.SnowflakeError{Number: 2003, QueryID: "01b2-failed"}
+	}
+	return queryIDResult{queryID: "01b2-created"}, nil
+}
+
+// queryIDResult implements gosnowflake.SnowflakeResult through the embedded interface, overriding GetQueryID
+type queryIDResult struct {
+	gosnowflake.SnowflakeResult
+	queryID string
+}
+
+func (r queryIDResult) GetQueryID() string {
+	return r.queryID
+}
+
+func (queryIDResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (queryIDResult) RowsAffected() (int64, error) {
+	return 0, nil
+}
+
+func init() {
+	sql.Register("speck-query-id", queryIDDriver{})
+}
+
+var _ = Describe("execQueryID", func() {
+	It("should return the query ID of the driver's result", func() {
+		db, err := sql.Open("speck-query-id", "")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+
+		Expect(execQueryID(context.Background(), db, "CREATE ACCOUNT A")).To(Equal("01b2-created"))
+
+		_, err = execQueryID(context.Background(), db, "DROP ACCOUNT A")
+		Expect(snowflakeQueryID(err)).To(Equal("01b2-failed"))
+	})
+})
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// failureBackoffBase is how long to wait before retrying after the first failure; the wait
+	// doubles with every further consecutive failure
+	failureBackoffBase = 10 * time.Second
+
+	// maxFailureBackoff caps the wait between retries after consecutive failures
+	maxFailureBackoff = 10 * time.Minute
+
+	// failureEventThreshold is the number of consecutive failures at which a warning event is emitted
+	failureEventThreshold = 5
+)
+
+// failureBackoff returns how long to wait before retrying after the given number of consecutive failures
+func failureBackoff(failureCount int) time.Duration {
+	backoff := failureBackoffBase
+	for i := 1; i < failureCount && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxFailureBackoff)
+}
+
+// remainingFailureBackoff returns how much of the wait after the last recorded failure is left, or 0
+// once the operation may be retried. Status updates trigger reconciles before the requeue is due, so
+// the wait has to be enforced from the status rather than only through RequeueAfter.
+func (r *SnowflakeAccountReconciler) remainingFailureBackoff(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) time.Duration {
+	if snowflakeAccount.Status.FailureCount == 0 || snowflakeAccount.Status.LastFailureTime == nil {
+		return 0
+	}
+	retryAt := snowflakeAccount.Status.LastFailureTime.Add(failureBackoff(snowflakeAccount.Status.FailureCount))
+	return max(retryAt.Sub(r.Clock.Now()), 0)
+}
+
+// recordFailure counts a failed create or drop of the Snowflake account in the status, emitting a
+// warning event once failureEventThreshold consecutive failures are reached, and returns how long to
+// wait before retrying. The caller is expected to have set the status message. Failing to persist the
+// status is returned, since it may hold the name of an account that Snowflake is still creating.
+func (r *SnowflakeAccountReconciler) recordFailure(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, operation string, err error) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	snowflakeAccount.Status.FailureCount++
+	now := metav1.NewTime(r.Clock.Now())
+	snowflakeAccount.Status.LastFailureTime = &now
+
+	failureCount := snowflakeAccount.Status.FailureCount
+	if failureCount == failureEventThreshold {
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "RepeatedFailures",
+			"%s failed %d times in a row: %v", operation, failureCount, err)
+	}
+
+	if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+		return 0, fmt.Errorf("failed to record failure in status: %w", statusErr)
+	}
+
+	backoff := failureBackoff(failureCount)
+	log.Info("Retrying after consecutive failures", "operation", operation, "failureCount", failureCount, "after", backoff)
+	return backoff, nil
+}
+
+// clearFailures resets the consecutive failure count after a successful operation
+func clearFailures(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	snowflakeAccount.Status.FailureCount = 0
+	snowflakeAccount.Status.LastFailureTime = nil
+}
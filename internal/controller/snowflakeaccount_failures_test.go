@@ -0,0 +1,15 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Failure backoff", func() {
+	It("should double the wait with every consecutive failure up to the cap", func() {
+		Expect(failureBackoff(1)).To(Equal(failureBackoffBase))
+		Expect(failureBackoff(2)).To(Equal(2 * failureBackoffBase))
+		Expect(failureBackoff(4)).To(Equal(8 * failureBackoffBase))
+		Expect(failureBackoff(100)).To(Equal(maxFailureBackoff))
+	})
+})
@@ -3,77 +3,417 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
-	// snowflakeAccountFinalizer is the finalizer name for SnowflakeAccount
-	snowflakeAccountFinalizer = "operator.dataverse.redhat.com/finalizer"
+	// DefaultFinalizerName is the finalizer name for SnowflakeAccount unless FinalizerName is set
+	DefaultFinalizerName = "operator.dataverse.redhat.com/finalizer"
+
+	// finalizerNameAnnotation records the finalizer of the operator instance that manages the resource, so
+	// other instances with their own finalizer leave it alone
+	finalizerNameAnnotation = "speck.dataverse.redhat.com/finalizer-name"
+
+	// deletionProtectionAnnotation, set to "true", keeps a deleted resource terminating without dropping
+	// its Snowflake account until the annotation is removed
+	deletionProtectionAnnotation = "speck.dataverse.redhat.com/deletion-protection"
+
+	// finalizeFailedRetryInterval is how often finalizing is retried once it has been marked as failed
+	finalizeFailedRetryInterval = 30 * time.Minute
 )
 
-func (r *SnowflakeAccountReconciler) handleFinalizerOperations(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (continueReconciliation bool, err error) {
+// ValidateFinalizerName checks that name can be used as a finalizer, which must be a qualified name
+func ValidateFinalizerName(name string) error {
+	if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+		return fmt.Errorf("invalid finalizer name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// finalizerName returns the finalizer this operator instance adds to SnowflakeAccounts
+func (r *SnowflakeAccountReconciler) finalizerName() string {
+	if r.FinalizerName != "" {
+		return r.FinalizerName
+	}
+	return DefaultFinalizerName
+}
+
+func (r *SnowflakeAccountReconciler) handleFinalizerOperations(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (continueReconciliation bool, requeueAfter time.Duration, err error) {
 	log := logf.FromContext(ctx)
 
 	// Check if the SnowflakeAccount is being deleted
 	if !snowflakeAccount.DeletionTimestamp.IsZero() {
 		// The object is being deleted
-		if controllerutil.ContainsFinalizer(snowflakeAccount, snowflakeAccountFinalizer) {
+		if controllerutil.ContainsFinalizer(snowflakeAccount, r.finalizerName()) {
+			// Keep the finalizer while the resource is protected; removing the annotation triggers
+			// another reconcile that finalizes it
+			if snowflakeAccount.Annotations[deletionProtectionAnnotation] == "true" {
+				return false, 0, r.blockDeletion(ctx, snowflakeAccount)
+			}
+			r.unblockDeletion(ctx, snowflakeAccount)
+
+			// Recording a failed attempt updates the status, which must not trigger the next attempt early
+			wait := r.remainingFailureBackoff(snowflakeAccount)
+			if meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionFinalizeFailed) && snowflakeAccount.Status.LastFailureTime != nil {
+				wait = max(snowflakeAccount.Status.LastFailureTime.Add(finalizeFailedRetryInterval).Sub(r.Clock.Now()), 0)
+			}
+			if wait > 0 {
+				log.V(1).Info("Waiting before retrying to finalize SnowflakeAccount",
+					"failureCount", snowflakeAccount.Status.FailureCount, "after", wait)
+				return false, wait, nil
+			}
+
 			log.Info("Running finalizer logic for SnowflakeAccount")
 
 			// Perform cleanup operations
 			if err := r.finalizeSnowflakeAccount(ctx, snowflakeAccount); err != nil {
 				log.Error(err, "Failed to finalize SnowflakeAccount")
-				return false, err
+				requeueAfter, err := r.recordFinalizeFailure(ctx, snowflakeAccount, err)
+				return false, requeueAfter, err
 			}
 
 			// Remove the finalizer
-			controllerutil.RemoveFinalizer(snowflakeAccount, snowflakeAccountFinalizer)
+			controllerutil.RemoveFinalizer(snowflakeAccount, r.finalizerName())
 			if err := r.Update(ctx, snowflakeAccount); err != nil {
 				log.Error(err, "Failed to remove finalizer")
-				return false, err
+				return false, 0, err
 			}
 			log.Info("Successfully finalized SnowflakeAccount")
 		}
-		return false, nil
+		return false, 0, nil
 	}
 
 	// Add finalizer if it doesn't exist
-	if !controllerutil.ContainsFinalizer(snowflakeAccount, snowflakeAccountFinalizer) {
+	if !controllerutil.ContainsFinalizer(snowflakeAccount, r.finalizerName()) {
+		if other := r.otherInstanceFinalizer(snowflakeAccount); other != "" {
+			log.V(1).Info("Skipping SnowflakeAccount managed by another operator instance", "finalizer", other)
+			return false, 0, nil
+		}
 		log.Info("Adding finalizer to SnowflakeAccount")
-		controllerutil.AddFinalizer(snowflakeAccount, snowflakeAccountFinalizer)
+		controllerutil.AddFinalizer(snowflakeAccount, r.finalizerName())
+		if snowflakeAccount.Annotations == nil {
+			snowflakeAccount.Annotations = map[string]string{}
+		}
+		snowflakeAccount.Annotations[finalizerNameAnnotation] = r.finalizerName()
 		if err := r.Update(ctx, snowflakeAccount); err != nil {
 			log.Error(err, "Failed to add finalizer")
-			return false, err
+			return false, 0, err
 		}
-		return false, nil
+		return false, 0, nil
 	}
 
 	// Continue with normal reconciliation
-	return true, nil
+	return true, 0, nil
 }
 
-// finalizeSnowflakeAccount performs cleanup operations before the SnowflakeAccount is deleted
+// otherInstanceFinalizer returns the finalizer of another operator instance the resource carries, or ""
+// if no other instance manages it. Instances record their finalizer in finalizerNameAnnotation; resources
+// without it carrying DefaultFinalizerName were taken by an instance that predates the annotation.
+func (r *SnowflakeAccountReconciler) otherInstanceFinalizer(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) string {
+	other, ok := snowflakeAccount.Annotations[finalizerNameAnnotation]
+	if !ok {
+		other = DefaultFinalizerName
+	}
+	if other == r.finalizerName() || !controllerutil.ContainsFinalizer(snowflakeAccount, other) {
+		return ""
+	}
+	return other
+}
+
+// recordFinalizeFailure counts a failed attempt to finalize the resource with recordFailure and returns how
+// long to wait before retrying. Attempts interrupted by operator shutdown are not counted and are retried
+// with finalizeErr. Once
+// MaxFinalizeAttempts is reached the FinalizeFailed condition is set, a warning event is emitted, and
+// finalizing is retried every finalizeFailedRetryInterval.
+func (r *SnowflakeAccountReconciler) recordFinalizeFailure(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, finalizeErr error) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	const operation = "Finalizing the SnowflakeAccount"
+	if r.recordInterruption(ctx, snowflakeAccount, operation, finalizeErr) {
+		return 0, finalizeErr
+	}
+
+	failureCount := snowflakeAccount.Status.FailureCount + 1
+	if r.MaxFinalizeAttempts == 0 || failureCount < r.MaxFinalizeAttempts {
+		return r.recordFailure(ctx, snowflakeAccount, operation, finalizeErr)
+	}
+
+	message := fmt.Sprintf("Finalizing failed %d times, retrying every %s: %v", failureCount, finalizeFailedRetryInterval, finalizeErr)
+	if !meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionFinalizeFailed) {
+		log.Error(finalizeErr, "Giving up on finalizing SnowflakeAccount, retrying on a slower cadence",
+			"failureCount", failureCount, "after", finalizeFailedRetryInterval)
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "FinalizeFailed", "%s", message)
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionFinalizeFailed,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RetriesExhausted",
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if _, err := r.recordFailure(ctx, snowflakeAccount, operation, finalizeErr); err != nil {
+		return 0, err
+	}
+	return finalizeFailedRetryInterval, nil
+}
+
+// blockDeletion reports that the resource is being deleted while protected by deletionProtectionAnnotation,
+// warning once when the deletion is first blocked
+func (r *SnowflakeAccountReconciler) blockDeletion(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	changed := meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:   conditionDeletionBlocked,
+		Status: metav1.ConditionTrue,
+		Reason: "DeletionProtected",
+		Message: fmt.Sprintf("The resource is protected by the %s annotation; remove it to drop the Snowflake account and finish the deletion",
+			deletionProtectionAnnotation),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if !changed {
+		return nil
+	}
+
+	log.Error(nil, "Deletion of a protected SnowflakeAccount is blocked until the protection annotation is removed",
+		"annotation", deletionProtectionAnnotation, "accountName", snowflakeAccount.Status.AccountName)
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "DeletionBlocked",
+		"Deletion is blocked by the %s annotation; the Snowflake account %s is kept until it is removed",
+		deletionProtectionAnnotation, snowflakeAccount.Status.AccountName)
+
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to record blocked deletion")
+		return err
+	}
+	return nil
+}
+
+// unblockDeletion clears a DeletionBlocked condition once the protection annotation has been removed
+func (r *SnowflakeAccountReconciler) unblockDeletion(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	if !meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionDeletionBlocked) {
+		return
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionDeletionBlocked,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ProtectionRemoved",
+		Message:            "The deletion protection annotation was removed, deleting",
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+
+	// The resource is about to go away, so a failed update only loses the status copy
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to clear blocked deletion")
+	}
+}
+
+// recordAccountDropped reports the dropped account and the end of its grace period in the status and
+// in an event, which outlives the resource once the finalizer is removed
+func (r *SnowflakeAccountReconciler) recordAccountDropped(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	log := logf.FromContext(ctx)
+
+	accountName := snowflakeAccount.Status.SnowflakeAccountName
+	if accountName == "" {
+		accountName = snowflakeAccount.Status.AccountName
+	}
+	clearFailures(snowflakeAccount)
+
+	if recoverableUntil := snowflakeAccount.Status.RecoverableUntil; recoverableUntil != nil {
+		until := recoverableUntil.UTC().Format(time.RFC3339)
+		snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account %s dropped; it can be restored with UNDROP ACCOUNT until %s", accountName, until)
+		r.eventf(snowflakeAccount, corev1.EventTypeNormal, "AccountDropped",
+			"Snowflake account %s dropped; it can be restored with UNDROP ACCOUNT until %s", accountName, until)
+	} else {
+		snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account %s dropped without a grace period", accountName)
+		r.eventf(snowflakeAccount, corev1.EventTypeNormal, "AccountDropped",
+			"Snowflake account %s dropped without a grace period", accountName)
+	}
+
+	// The resource is about to go away, so a failed update only loses the status copy
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to record dropped account in status")
+	}
+}
+
+// reconcileDeletionDisabledCondition sets the AccountDeletionDisabled condition while the operator runs
+// with DisableAccountDeletion, and clears a previously set condition once deletion is allowed again
+func (r *SnowflakeAccountReconciler) reconcileDeletionDisabledCondition(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	condition := metav1.Condition{
+		Type:               conditionAccountDeletionDisabled,
+		Status:             metav1.ConditionFalse,
+		Reason:             "DeletionEnabled",
+		Message:            "The operator drops the Snowflake account when the resource is deleted or its duration expires",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if r.DisableAccountDeletion {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "DeletionDisabled"
+		condition.Message = "Account deletion is disabled on the operator; the Snowflake account is never dropped " +
+			"and must be dropped manually"
+	} else if meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionAccountDeletionDisabled) == nil {
+		// Only report the condition once deletion has been disabled
+		return nil
+	}
+
+	if meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition) {
+		return r.updateStatus(ctx, snowflakeAccount)
+	}
+	return nil
+}
+
+// deletionPolicy returns Spec.DeletionPolicy, defaulting to retaining adopted accounts and dropping created ones
+func deletionPolicy(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) operatorv1alpha1.DeletionPolicy {
+	if snowflakeAccount.Spec.DeletionPolicy != "" {
+		return snowflakeAccount.Spec.DeletionPolicy
+	}
+	return operatorv1alpha1.DefaultDeletionPolicy(snowflakeAccount.Spec.AdoptExisting)
+}
+
+// releaseRetainedAccount removes the Kubernetes tags from a retained account, so the orphan audit does not
+// report it once the resource is gone. Failing to do so is reported but does not keep the resource.
+func (r *SnowflakeAccountReconciler) releaseRetainedAccount(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	accountName := snowflakeAccount.Status.SnowflakeAccountName
+	if r.KubernetesTagSchema == "" || accountName == "" {
+		return
+	}
+
+	err := func() error {
+		creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+		if err != nil {
+			return err
+		}
+		statement := fmt.Sprintf("ALTER ACCOUNT %s UNSET TAG %s.K8S_NAMESPACE, %[2]s.K8S_NAME, %[2]s.K8S_UID", accountName, r.KubernetesTagSchema)
+		return r.snowflake().Exec(ctx, creds, statement)
+	}()
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to remove the Kubernetes tags of the retained account", "accountName", accountName)
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "ReleaseFailed",
+			"Failed to remove the Kubernetes tags of retained account %s, which the orphan audit may report: %v", accountName, err)
+	}
+}
+
+// finalizeSnowflakeAccount performs cleanup operations before the SnowflakeAccount is deleted; the resource is
+// only finalized once it returns no error
 func (r *SnowflakeAccountReconciler) finalizeSnowflakeAccount(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
 	log := logf.FromContext(ctx)
 	log.Info("Finalizing SnowflakeAccount", "name", snowflakeAccount.Name, "namespace", snowflakeAccount.Namespace)
 
-	// If the account was created, delete it from Snowflake
-	if snowflakeAccount.Status.AccountCreated {
+	// The resource is going away, so a failed update (logged by setPhase) only delays reporting the phase
+	_ = r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseDeleting)
+
+	// Retain the Snowflake account if requested or if the operator may not drop accounts,
+	// only cleaning up the credentials secret
+	retainedBy := ""
+	switch {
+	case r.DisableAccountDeletion:
+		retainedBy = "the operator's --disable-account-deletion flag"
+	case !managesAccount(snowflakeAccount) || snowflakeAccount.Status.Unmanaged:
+		retainedBy = "spec.manageAccount"
+	case deletionPolicy(snowflakeAccount) == operatorv1alpha1.DeletionPolicyRetain:
+		retainedBy = "deletion policy"
+	}
+	if retainedBy != "" {
+		log.Info("Keeping Snowflake account; it must be dropped manually",
+			"retainedBy", retainedBy,
+			"accountName", snowflakeAccount.Status.AccountName,
+			"accountURL", snowflakeAccount.Status.AccountURL)
+		if r.DisableAccountDeletion && snowflakeAccount.Status.SnowflakeAccountName != "" {
+			log.Error(nil, "Account deletion is disabled, the Snowflake account is orphaned and keeps running",
+				"snowflakeAccountName", snowflakeAccount.Status.SnowflakeAccountName)
+		}
+
+		if !r.DisableAccountDeletion {
+			r.releaseRetainedAccount(ctx, snowflakeAccount)
+		}
+
+		snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account %s retained by %s and is no longer managed by the operator", snowflakeAccount.Status.AccountName, retainedBy)
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			log.Error(err, "Failed to update status")
+		}
+
+		if err := r.deleteStoredCredentials(ctx, snowflakeAccount); err != nil {
+			return err
+		}
+
+		log.Info("Successfully finalized SnowflakeAccount")
+		return nil
+	}
+
+	// Delete the account from Snowflake if it was created, even if provisioning did not finish
+	if snowflakeAccount.Status.AccountCreated || snowflakeAccount.Status.SnowflakeAccountName != "" {
 		log.Info("Deleting Snowflake account", "accountURL", snowflakeAccount.Status.AccountURL)
 
+		// Clean up while the account still exists; a failed cleanup must not keep it from being dropped
+		if snowflakeAccount.Status.AccountCreated && len(snowflakeAccount.Spec.PreDeleteSQL) > 0 {
+			r.runPreDeleteSQL(ctx, snowflakeAccount)
+		}
+
 		if err := r.deleteSnowflakeAccount(ctx, snowflakeAccount); err != nil {
-			log.Error(err, "Failed to delete Snowflake account, will retry")
 			return fmt.Errorf("failed to delete Snowflake account: %w", err)
 		}
 
 		log.Info("Successfully deleted Snowflake account")
+		r.recordAccountDropped(ctx, snowflakeAccount)
 	} else {
 		log.Info("Snowflake account was not created, skipping deletion")
 	}
 
+	// Secrets in another namespace and external sinks are not garbage collected through owner references
+	if credentialsSecretNamespace(snowflakeAccount) != snowflakeAccount.Namespace ||
+		!r.storesCredentialsSecret(snowflakeAccount) {
+		if err := r.deleteStoredCredentials(ctx, snowflakeAccount); err != nil {
+			return err
+		}
+	}
+
 	log.Info("Successfully finalized SnowflakeAccount")
 	return nil
 }
+
+// runPreDeleteSQL runs the statements of Spec.PreDeleteSQL in the account as its admin before it is
+// dropped. All statements are attempted within a single timeout; failures are logged and reported in
+// an event, but do not stop the remaining statements or the drop.
+func (r *SnowflakeAccountReconciler) runPreDeleteSQL(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	log := logf.FromContext(ctx)
+	statements := snowflakeAccount.Spec.PreDeleteSQL
+
+	if reason := adminLoginBlocker(snowflakeAccount); reason != "" {
+		log.Info("Skipping pre-delete statements", "reason", reason)
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "PreDeleteSQLFailed", "Skipped the pre-delete statements: %s", reason)
+		return
+	}
+
+	creds, _, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		log.Error(err, "Skipping pre-delete statements, the account cannot be logged into")
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "PreDeleteSQLFailed",
+			"Skipped the pre-delete statements: %v", err)
+		return
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	failed := 0
+	for i, statement := range statements {
+		if err := r.execChildAccount(execCtx, creds, []string{statement}); err != nil {
+			failed++
+			log.Error(err, "Pre-delete statement failed, continuing", "index", i+1, "count", len(statements))
+		}
+	}
+
+	if failed > 0 {
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "PreDeleteSQLFailed",
+			"%d of %d pre-delete statements failed; dropping the account anyway", failed, len(statements))
+		return
+	}
+	log.Info("Ran pre-delete statements", "count", len(statements))
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// Generator produces the names and passwords of new accounts and their admin users
+type Generator interface {
+	// AccountName returns an account name of the given total length using the given charset.
+	// A zero length or empty charset selects the generator's defaults.
+	AccountName(length int, charset operatorv1alpha1.AccountNameCharset) (string, error)
+	// Username returns the name of the admin user
+	Username() string
+	// Password returns the initial password of the admin user
+	Password() string
+}
+
+// randomGenerator is the default Generator, backed by crypto/rand
+type randomGenerator struct{}
+
+func (randomGenerator) AccountName(length int, charset operatorv1alpha1.AccountNameCharset) (string, error) {
+	return generateRandomAccountName(length, charset)
+}
+
+func (randomGenerator) Username() string {
+	return generateRandomUsername()
+}
+
+func (randomGenerator) Password() string {
+	return generateRandomPassword()
+}
+
+// generator returns the configured Generator, defaulting to random generation
+func (r *SnowflakeAccountReconciler) generator() Generator {
+	if r.Generator != nil {
+		return r.Generator
+	}
+	return randomGenerator{}
+}
+
+// generateAccountName returns a generated account name for the account, rejecting names that are not valid
+// unquoted account identifiers, as they are rendered into CREATE ACCOUNT unquoted
+func (r *SnowflakeAccountReconciler) generateAccountName(account *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	accountName, err := r.generator().AccountName(account.Spec.AccountNameLength, account.Spec.AccountNameCharset)
+	if err != nil {
+		return "", err
+	}
+	if !accountNamePattern.MatchString(accountName) {
+		return "", fmt.Errorf("generated account name %q is not a valid Snowflake account identifier", accountName)
+	}
+	return accountName, nil
+}
+
+// generateAdminName returns a generated admin name, rejecting names that are not valid unquoted identifiers
+func (r *SnowflakeAccountReconciler) generateAdminName() (string, error) {
+	adminName := r.generator().Username()
+	if !unquotedIdentifierPattern.MatchString(adminName) {
+		return "", fmt.Errorf("generated admin name %q is not a valid Snowflake identifier", adminName)
+	}
+	return adminName, nil
+}
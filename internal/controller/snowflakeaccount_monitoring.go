@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultMonitoringRole is the role created for the monitoring user when Spec.MonitoringUser.Role is empty
+const defaultMonitoringRole = "MONITORING"
+
+// monitoringSecretName returns the name of the secret holding the monitoring user's credentials:
+// {accountName}-monitoring (lowercase for Kubernetes naming requirements)
+func monitoringSecretName(accountName string) string {
+	return fmt.Sprintf("%s-monitoring", strings.ToLower(accountName))
+}
+
+// monitoringRole returns the role of the monitoring user, defaulting to MONITORING
+func monitoringRole(user *operatorv1alpha1.MonitoringUser) string {
+	if user.Role != "" {
+		return user.Role
+	}
+	return defaultMonitoringRole
+}
+
+// monitoringSecretLabels returns the labels of the monitoring secret. It is labeled like the credentials
+// secret but without the discovery labels, so it is never mistaken for the credentials secret.
+func monitoringSecretLabels(account *operatorv1alpha1.SnowflakeAccount) map[string]string {
+	labels := make(map[string]string, len(account.Spec.SecretLabels)+4)
+	for key, value := range account.Spec.SecretLabels {
+		labels[key] = value
+	}
+	labels["app.kubernetes.io/name"] = "snowflake-account"
+	labels["app.kubernetes.io/managed-by"] = "snowflake-operator"
+	labels["app.kubernetes.io/component"] = "monitoring"
+	if credentialsSecretNamespace(account) != account.Namespace {
+		labels[ownerNamespaceLabel] = account.Namespace
+	}
+	return labels
+}
+
+// validateMonitoringUser checks that the monitoring user is not the admin of the account, whose password
+// creating the monitoring user would otherwise replace
+func validateMonitoringUser(user *operatorv1alpha1.MonitoringUser, adminNames ...string) error {
+	for _, adminName := range adminNames {
+		if adminName != "" && roleIdentifier(user.Name) == roleIdentifier(adminName) {
+			return fmt.Errorf("monitoring user %s must not be the admin user of the account", user.Name)
+		}
+	}
+	return nil
+}
+
+// monitoringRoleStatements returns the idempotent statements that create the monitoring role with
+// read-only access to the account's usage and grant it to the user
+func monitoringRoleStatements(user *operatorv1alpha1.MonitoringUser) []string {
+	role := quoteIdentifier(roleIdentifier(monitoringRole(user)))
+	name := userIdentifier(user.Name)
+	return []string{
+		fmt.Sprintf("CREATE ROLE IF NOT EXISTS %s", role),
+		fmt.Sprintf("GRANT MONITOR USAGE ON ACCOUNT TO ROLE %s", role),
+		fmt.Sprintf("GRANT IMPORTED PRIVILEGES ON DATABASE SNOWFLAKE TO ROLE %s", role),
+		fmt.Sprintf("ALTER USER %s SET DEFAULT_ROLE = %s", name, role),
+		fmt.Sprintf("GRANT ROLE %s TO USER %s", role, name),
+	}
+}
+
+// createMonitoringUser creates the monitoring user of Spec.MonitoringUser in the account as its admin and
+// stores the user's credentials in the monitoring secret. The password is stored before it is set and
+// reused on later runs, so the secret always holds the password the user can log in with.
+func (r *SnowflakeAccountReconciler) createMonitoringUser(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+	user := snowflakeAccount.Spec.MonitoringUser
+
+	creds, adminName, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+	if err := validateMonitoringUser(user, adminName); err != nil {
+		return err
+	}
+
+	secretName := monitoringSecretName(snowflakeAccount.Status.AccountName)
+	secretNamespace := credentialsSecretNamespace(snowflakeAccount)
+	existing := &corev1.Secret{}
+	password := ""
+	if err := r.Get(ctx, client.ObjectKey{Namespace: secretNamespace, Name: secretName}, existing); err == nil {
+		password = string(existing.Data["password"])
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get monitoring secret: %w", err)
+	}
+	if password == "" {
+		password = r.generator().Password()
+	}
+
+	secret := corev1ac.Secret(secretName, secretNamespace).
+		WithLabels(monitoringSecretLabels(snowflakeAccount)).
+		WithAnnotations(credentialsSecretAnnotations(snowflakeAccount)).
+		WithOwnerReferences(ownerReferenceConfigurations(credentialsOwnerReferences(snowflakeAccount))...).
+		WithType(corev1.SecretTypeOpaque).
+		WithData(map[string][]byte{
+			"accountName": []byte(snowflakeAccount.Status.AccountName),
+			"accountURL":  []byte(snowflakeAccount.Status.AccountURL),
+			"username":    []byte(user.Name),
+			"password":    []byte(password),
+			"role":        []byte(monitoringRole(user)),
+		})
+	if err := r.Apply(ctx, secret, client.FieldOwner(fieldManager)); err != nil {
+		return fmt.Errorf("failed to apply monitoring secret: %w", err)
+	}
+
+	// The statements hold the password, so they are not logged
+	log.Info("Creating monitoring user", "account", creds.account, "user", user.Name)
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+	for _, statement := range []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s PASSWORD = '%s' MUST_CHANGE_PASSWORD = FALSE",
+			userIdentifier(user.Name), escapeSQLString(password)),
+		// The user may exist from an earlier run whose password was never stored
+		fmt.Sprintf("ALTER USER %s SET PASSWORD = '%s' MUST_CHANGE_PASSWORD = FALSE",
+			userIdentifier(user.Name), escapeSQLString(password)),
+	} {
+		if err := r.snowflake().Exec(execCtx, creds, statement); err != nil {
+			return fmt.Errorf("failed to create monitoring user %s: %w", user.Name, classifySnowflakeError(err))
+		}
+	}
+
+	if err := r.execChildAccount(ctx, creds, monitoringRoleStatements(user)); err != nil {
+		return err
+	}
+
+	log.Info("Stored monitoring user credentials", "secretName", secretName, "namespace", secretNamespace)
+	return nil
+}
+
+// deleteMonitoringSecret deletes the monitoring secret of the account if it exists
+func (r *SnowflakeAccountReconciler) deleteMonitoringSecret(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	if account.Status.AccountName == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: credentialsSecretNamespace(account), Name: monitoringSecretName(account.Status.AccountName)}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete monitoring secret: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Deleted monitoring secret", "secretName", secret.Name, "namespace", secret.Namespace)
+	return nil
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// runOrphanAudit periodically looks for operator-created accounts without an owning resource until ctx is cancelled
+func (r *SnowflakeAccountReconciler) runOrphanAudit(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("orphan-audit")
+	ctx = logf.IntoContext(ctx, log)
+
+	ticker := time.NewTicker(r.OrphanAuditInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := r.auditOrphanedAccounts(ctx); err != nil {
+				log.Error(err, "Failed to audit orphaned accounts")
+			}
+		}
+	}
+}
+
+// orphanAuditTimeout bounds a single audit, so a hanging organization cannot stall later audits
+const orphanAuditTimeout = 10 * time.Minute
+
+// auditOrphanedAccounts lists the accounts of every organization the operator provisions in and reports
+// those created by the operator that no SnowflakeAccount resource owns, e.g. because the operator crashed
+// before recording them. Accounts are recognized by the K8S_UID tag of KubernetesTagSchema, so the audit
+// requires it; accounts whose resource retained them on deletion have their tags removed and are not reported.
+func (r *SnowflakeAccountReconciler) auditOrphanedAccounts(ctx context.Context) ([]string, error) {
+	log := logf.FromContext(ctx)
+
+	if r.KubernetesTagSchema == "" {
+		return nil, fmt.Errorf("the orphan audit requires the Kubernetes tag schema to recognize operator-created accounts")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, orphanAuditTimeout)
+	defer cancel()
+
+	resources := &operatorv1alpha1.SnowflakeAccountList{}
+	if err := r.List(ctx, resources); err != nil {
+		return nil, err
+	}
+	orgs, err := r.auditedOrganizations(ctx, resources.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query Snowflake before listing the resources again, so an account created in between is owned by a
+	// listed resource
+	rows := make(map[string][]map[string]string, len(orgs))
+	for key, creds := range orgs {
+		orgRows, err := r.snowflake().ShowAccounts(ctx, creds, "%")
+		if err != nil {
+			return nil, err
+		}
+		rows[key] = orgRows
+	}
+
+	if err := r.List(ctx, resources); err != nil {
+		return nil, err
+	}
+	ownedUIDs := make(map[string]bool, len(resources.Items))
+	ownedNames := make(map[string]bool, len(resources.Items))
+	for _, resource := range resources.Items {
+		ownedUIDs[string(resource.UID)] = true
+		for _, name := range []string{resource.Status.SnowflakeAccountName, resource.Status.AccountName} {
+			if name != "" {
+				ownedNames[strings.ToUpper(name)] = true
+			}
+		}
+	}
+
+	var orphans []string
+	accounts := 0
+	for key, orgRows := range rows {
+		accounts += len(orgRows)
+		var unowned []string
+		for _, row := range orgRows {
+			if accountName := strings.ToUpper(row["account_name"]); !ownedNames[accountName] {
+				unowned = append(unowned, accountName)
+			}
+		}
+		uids := r.accountOwnerUIDs(ctx, orgs[key], unowned)
+		for _, row := range orgRows {
+			accountName := strings.ToUpper(row["account_name"])
+			uid := uids[accountName]
+			if uid == "" || ownedUIDs[uid] {
+				continue
+			}
+			log.Info("Found Snowflake account without an owning SnowflakeAccount",
+				"accountName", accountName,
+				"ownerUID", uid,
+				"createdOn", row["created_on"],
+				"accountURL", row["account_url"])
+			orphans = append(orphans, accountName)
+		}
+	}
+
+	orphanedAccounts.Set(float64(len(orphans)))
+	log.Info("Orphaned account audit finished", "organizations", len(orgs), "accounts", accounts, "orphaned", len(orphans))
+	return orphans, nil
+}
+
+// auditedOrganizations returns the distinct organization credentials of the operator's environment, its
+// credential profiles and the given resources, keyed by cacheKey. Credentials that cannot be resolved are
+// skipped, as long as at least one organization can be audited.
+func (r *SnowflakeAccountReconciler) auditedOrganizations(ctx context.Context, resources []operatorv1alpha1.SnowflakeAccount) (map[string]*snowflakeCredentials, error) {
+	log := logf.FromContext(ctx)
+
+	orgs := map[string]*snowflakeCredentials{}
+	add := func(creds *snowflakeCredentials, err error, source string) {
+		if err != nil {
+			log.V(1).Info("Skipping organization credentials", "source", source, "reason", err.Error())
+			return
+		}
+		orgs[creds.cacheKey()] = creds
+	}
+
+	creds, err := getSnowflakeCredentialsFromEnv()
+	add(creds, err, "environment")
+	for profile, key := range r.CredentialProfiles {
+		creds, err := r.getSnowflakeCredentialsFromSecret(ctx, key)
+		if err == nil {
+			creds.profile = profile
+		}
+		add(creds, err, "profile "+profile)
+	}
+	for i := range resources {
+		creds, err := r.getSnowflakeCredentials(ctx, &resources[i])
+		add(creds, err, resources[i].Namespace+"/"+resources[i].Name)
+	}
+
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("no organization credentials are configured")
+	}
+	return orgs, nil
+}
+
+// ownerUIDBatchSize is the number of accounts whose K8S_UID tag is read in a single query
+const ownerUIDBatchSize = 100
+
+// accountOwnerUIDs returns the K8S_UID tags of the accounts that are tagged, keyed by account name. The tags
+// are read ownerUIDBatchSize accounts per query; when a batch fails, e.g. because an account was dropped in
+// the meantime, its accounts are read one by one and those that still fail are logged and skipped, so one
+// account cannot abort the audit.
+func (r *SnowflakeAccountReconciler) accountOwnerUIDs(ctx context.Context, creds *snowflakeCredentials, accountNames []string) map[string]string {
+	log := logf.FromContext(ctx)
+
+	uids := make(map[string]string, len(accountNames))
+	for batch := range slices.Chunk(accountNames, ownerUIDBatchSize) {
+		columns := make([]string, len(batch))
+		for i, accountName := range batch {
+			columns[i] = fmt.Sprintf("SYSTEM$GET_TAG('%s.K8S_UID', '%s', 'ACCOUNT') AS k8s_uid_%d", r.KubernetesTagSchema, accountName, i)
+		}
+		rows, err := r.snowflake().Query(ctx, creds, "SELECT "+strings.Join(columns, ", "))
+		if err == nil {
+			if len(rows) > 0 {
+				for i, accountName := range batch {
+					if uid := rows[0][fmt.Sprintf("k8s_uid_%d", i)]; uid != "" {
+						uids[accountName] = uid
+					}
+				}
+			}
+			continue
+		}
+
+		log.V(1).Info("Failed to read the K8S_UID tags of a batch of accounts, reading them one by one", "reason", err.Error())
+		for _, accountName := range batch {
+			uid, err := r.accountOwnerUID(ctx, creds, accountName)
+			if err != nil {
+				log.Error(err, "Skipping account in the orphan audit", "accountName", accountName)
+				continue
+			}
+			if uid != "" {
+				uids[accountName] = uid
+			}
+		}
+	}
+	return uids
+}
+
+// accountOwnerUID returns the K8S_UID tag of the account, the UID of the SnowflakeAccount that created it,
+// or "" if the account is not tagged
+func (r *SnowflakeAccountReconciler) accountOwnerUID(ctx context.Context, creds *snowflakeCredentials, accountName string) (string, error) {
+	query := fmt.Sprintf("SELECT SYSTEM$GET_TAG('%s.K8S_UID', '%s', 'ACCOUNT') AS k8s_uid", r.KubernetesTagSchema, accountName)
+	rows, err := r.snowflake().Query(ctx, creds, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the K8S_UID tag of account %s: %w", accountName, err)
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return rows[0]["k8s_uid"], nil
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// getTagPattern matches the SYSTEM$GET_TAG calls of the orphan audit, capturing the account and the column
+var getTagPattern = regexp.MustCompile(`SYSTEM\$GET_TAG\('[^']*', '([^']*)', 'ACCOUNT'\) AS (\w+)`)
+
+// tagRows answers the SYSTEM$GET_TAG queries of the orphan audit with the K8S_UID tags by account
+func tagRows(uids map[string]string) func(string) []map[string]string {
+	return func(query string) []map[string]string {
+		row := map[string]string{}
+		for _, match := range getTagPattern.FindAllStringSubmatch(query, -1) {
+			row[match[2]] = uids[match[1]]
+		}
+		return []map[string]string{row}
+	}
+}
+
+var _ = Describe("Orphaned account audit", func() {
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		for key, value := range map[string]string{
+			"SNOWFLAKE_ORG_USERNAME": "org_admin",
+			"SNOWFLAKE_ORG_PASSWORD": "org-password",
+			"SNOWFLAKE_ORG_ACCOUNT":  "myorg-admin",
+		} {
+			GinkgoT().Setenv(key, value)
+		}
+	})
+
+	It("should report operator-tagged accounts without an owning resource", func() {
+		owner := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-audit-owner", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, owner)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, owner)).To(Succeed()) })
+		owner.Status.SnowflakeAccountName = "SFOWNED1"
+		Expect(k8sClient.Status().Update(ctx, owner)).To(Succeed())
+
+		// Tags by account, as returned by SYSTEM$GET_TAG
+		uids := map[string]string{
+			"SFOWNED1":   string(owner.UID),
+			"SFPENDING1": string(owner.UID),
+			"SFLEAKED1":  "deleted-resource-uid",
+		}
+		reconciler := &SnowflakeAccountReconciler{
+			Client:              k8sClient,
+			KubernetesTagSchema: "GOVERNANCE.TAGS",
+			Executor: &fakeSnowflakeExecutor{
+				accounts: map[string]map[string]string{
+					"SFOWNED1":   {"account_name": "SFOWNED1", "comment": defaultAccountComment},
+					"SFPENDING1": {"account_name": "SFPENDING1", "comment": defaultAccountComment},
+					"SFLEAKED1":  {"account_name": "SFLEAKED1", "comment": "Custom comment"},
+					"MANUAL":     {"account_name": "MANUAL", "comment": defaultAccountComment},
+				},
+				rowsFor: tagRows(uids),
+			},
+		}
+
+		orphans, err := reconciler.auditOrphanedAccounts(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(ConsistOf("SFLEAKED1"))
+		Expect(testutil.ToFloat64(orphanedAccounts)).To(Equal(1.0))
+	})
+
+	It("should read the tags in one query and skip accounts whose tag cannot be read", func() {
+		executor := &fakeSnowflakeExecutor{
+			accounts: map[string]map[string]string{
+				"SFLEAKED1":  {"account_name": "SFLEAKED1"},
+				"SFLEAKED2":  {"account_name": "SFLEAKED2"},
+				"SFDROPPED1": {"account_name": "SFDROPPED1"},
+			},
+			rowsFor: tagRows(map[string]string{
+				"SFLEAKED1":  "deleted-uid-1",
+				"SFLEAKED2":  "deleted-uid-2",
+				"SFDROPPED1": "deleted-uid-3",
+			}),
+		}
+		reconciler := &SnowflakeAccountReconciler{
+			Client:              k8sClient,
+			KubernetesTagSchema: "GOVERNANCE.TAGS",
+			Executor:            executor,
+		}
+
+		orphans, err := reconciler.auditOrphanedAccounts(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(ConsistOf("SFLEAKED1", "SFLEAKED2", "SFDROPPED1"))
+		Expect(executor.statementsWithPrefix("SELECT SYSTEM$GET_TAG")).To(HaveLen(1))
+
+		By("reading the accounts one by one when the batch fails")
+		executor.errFor = func(statement string) error {
+			if strings.Contains(statement, "'SFDROPPED1'") {
+				return errors.New("account SFDROPPED1 does not exist")
+			}
+			return nil
+		}
+		orphans, err = reconciler.auditOrphanedAccounts(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(ConsistOf("SFLEAKED1", "SFLEAKED2"))
+	})
+
+	It("should audit the organizations of credential profiles", func() {
+		profileSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-audit-profile", Namespace: "default"},
+			Data: map[string][]byte{
+				"SNOWFLAKE_ORG_USERNAME": []byte("eu_admin"),
+				"SNOWFLAKE_ORG_PASSWORD": []byte("eu-password"),
+				"SNOWFLAKE_ORG_ACCOUNT":  []byte("myorg-eu"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, profileSecret)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, profileSecret)).To(Succeed()) })
+
+		reconciler := &SnowflakeAccountReconciler{
+			Client:              k8sClient,
+			KubernetesTagSchema: "GOVERNANCE.TAGS",
+			CredentialProfiles:  map[string]types.NamespacedName{"eu": {Name: "orphan-audit-profile", Namespace: "default"}},
+			Executor:            &fakeSnowflakeExecutor{},
+		}
+
+		orgs, err := reconciler.auditedOrganizations(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		var accounts []string
+		for _, creds := range orgs {
+			accounts = append(accounts, creds.account)
+		}
+		Expect(accounts).To(ConsistOf("myorg-admin", "myorg-eu"))
+	})
+
+	It("should not audit without the Kubernetes tags", func() {
+		reconciler := &SnowflakeAccountReconciler{Client: k8sClient, Executor: &fakeSnowflakeExecutor{}}
+
+		_, err := reconciler.auditOrphanedAccounts(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should remove the Kubernetes tags of a retained account", func() {
+		typeNamespacedName := types.NamespacedName{Name: "orphan-audit-retained", Namespace: "default"}
+		resource := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       typeNamespacedName.Name,
+				Namespace:  typeNamespacedName.Namespace,
+				Finalizers: []string{DefaultFinalizerName},
+			},
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{DeletionPolicy: operatorv1alpha1.DeletionPolicyRetain},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		resource.Status.SnowflakeAccountName = "SFRETAINED1"
+		resource.Status.AccountCreated = true
+		Expect(k8sClient.Status().Update(ctx, resource)).To(Succeed())
+
+		executor := &fakeSnowflakeExecutor{}
+		reconciler := &SnowflakeAccountReconciler{
+			Client:              k8sClient,
+			Scheme:              k8sClient.Scheme(),
+			KubernetesTagSchema: "GOVERNANCE.TAGS",
+			Executor:            executor,
+		}
+
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(executor.statementsWithPrefix("DROP ACCOUNT")).To(BeEmpty())
+		Expect(executor.statementsWithPrefix("ALTER ACCOUNT")).To(ConsistOf(
+			"ALTER ACCOUNT SFRETAINED1 UNSET TAG GOVERNANCE.TAGS.K8S_NAMESPACE, GOVERNANCE.TAGS.K8S_NAME, GOVERNANCE.TAGS.K8S_UID",
+		))
+	})
+})
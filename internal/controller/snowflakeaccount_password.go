@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// pendingAdminPasswordKey holds the new admin password in the credentials secret until the password
+// change is confirmed, so a failure between changing and storing the password cannot lose it
+const pendingAdminPasswordKey = "pendingAdminPassword"
+
+// validatePasswordChange checks that the operator owns the admin password it is asked to change and
+// stores it in the credentials secret, the only place it can write the new password to
+func (r *SnowflakeAccountReconciler) validatePasswordChange(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	if snowflakeAccount.Spec.AdminPasswordSecretRef != nil {
+		return fmt.Errorf("autoCompletePasswordChange cannot be used with adminPasswordSecretRef; " +
+			"the operator does not update the referenced secret")
+	}
+	if !r.storesCredentialsSecret(snowflakeAccount) {
+		return fmt.Errorf("autoCompletePasswordChange requires the Kubernetes credentials sink; "+
+			"the operator cannot update the password stored in the %s sink", credentialsSinkType(snowflakeAccount))
+	}
+	return nil
+}
+
+// completePasswordChange performs the password change the admin is required to make on first login:
+// it logs in with the initial password, sets a new one that does not have to be changed, and stores it
+// in the credentials secret. It does nothing once no password change is pending.
+func (r *SnowflakeAccountReconciler) completePasswordChange(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	if !meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionPasswordChangePending) {
+		return nil
+	}
+
+	orgCreds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	secret, err := r.getCredentialsSecret(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return fmt.Errorf("credentials secret for account not found")
+	}
+
+	accountName := string(secret.Data["accountName"])
+	adminName := string(secret.Data["adminName"])
+	initialPassword := string(secret.Data["adminPassword"])
+	if accountName == "" || adminName == "" || initialPassword == "" {
+		return fmt.Errorf("credentials secret %s does not contain the initial admin credentials", secret.Name)
+	}
+
+	// Store the new password before setting it, so it survives a failure to store it afterwards
+	newPassword := string(secret.Data[pendingAdminPasswordKey])
+	if newPassword == "" {
+		newPassword = r.generator().Password()
+		secret.Data[pendingAdminPasswordKey] = []byte(newPassword)
+		if err := r.writeCredentialsSecret(ctx, snowflakeAccount, secret); err != nil {
+			return fmt.Errorf("failed to store new admin password: %w", err)
+		}
+		recordCredentialsSecret(snowflakeAccount, secret)
+	}
+
+	err = r.changeAdminPassword(ctx, orgCreds, accountName, adminName, initialPassword, newPassword)
+	if errors.Is(err, ErrSnowflakeAuth) {
+		// A previous attempt may have changed the password before the secret could be updated
+		if confirmErr := r.confirmAdminPassword(ctx, orgCreds, accountName, adminName, newPassword); confirmErr == nil {
+			log.Info("Admin password was already changed by a previous attempt", "adminName", adminName)
+			err = nil
+		} else {
+			err = fmt.Errorf("the initial admin password was rejected and may have been changed by a user: %w", err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	secret.Data["adminPassword"] = []byte(newPassword)
+	if _, ok := secret.Data[corev1.BasicAuthPasswordKey]; ok {
+		secret.Data[corev1.BasicAuthPasswordKey] = []byte(newPassword)
+	}
+	delete(secret.Data, pendingAdminPasswordKey)
+	if err := r.writeCredentialsSecret(ctx, snowflakeAccount, secret); err != nil {
+		return fmt.Errorf("failed to store new admin password: %w", err)
+	}
+	recordCredentialsSecret(snowflakeAccount, secret)
+
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionPasswordChangePending,
+		Status:             metav1.ConditionFalse,
+		Reason:             "PasswordChanged",
+		Message:            fmt.Sprintf("The initial password of admin user %s was changed by the operator", adminName),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+
+	log.Info("Replaced initial admin password", "adminName", adminName, "secretName", secret.Name)
+	return nil
+}
+
+// checkPasswordChange detects whether the admin has made the password change required on first login by
+// logging in with the initial password, at most once per PasswordChangeCheckInterval. A rejected login
+// means the password was changed; a successful query means no change is required anymore. It returns how
+// long to wait before checking again, which is zero once no password change is pending.
+func (r *SnowflakeAccountReconciler) checkPasswordChange(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	interval := r.PasswordChangeCheckInterval
+	if interval <= 0 || snowflakeAccount.Spec.AutoCompletePasswordChange ||
+		!meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionPasswordChangePending) {
+		return 0, nil
+	}
+
+	now := r.Clock.Now()
+	if lastCheck, ok := r.passwordChecks.Load(snowflakeAccount.UID); ok {
+		if wait := lastCheck.(time.Time).Add(interval).Sub(now); wait > 0 {
+			return wait, nil
+		}
+	}
+	r.passwordChecks.Store(snowflakeAccount.UID, now)
+
+	creds, adminName, err := r.getChildAccountCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		log.Error(err, "Cannot check whether the initial admin password was changed")
+		return interval, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionPasswordChangePending,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	err = r.execChildAccount(ctx, creds, []string{"SELECT CURRENT_USER()"})
+	switch {
+	case errors.Is(err, ErrSnowflakeAuth):
+		condition.Reason = "ChangedByUser"
+		condition.Message = fmt.Sprintf("The initial password of admin user %s was changed; "+
+			"the password in the credentials secret no longer works", adminName)
+	case err == nil:
+		condition.Reason = "NotRequired"
+		condition.Message = fmt.Sprintf("Admin user %s no longer has to change the initial password", adminName)
+	default:
+		// Snowflake refuses queries until the password is changed, which cannot be told apart from other failures
+		log.Info("Initial admin password change is still pending", "adminName", adminName, "error", err.Error())
+		return interval, nil
+	}
+
+	log.Info("Initial admin password change is no longer pending", "adminName", adminName, "reason", condition.Reason)
+	r.passwordChecks.Delete(snowflakeAccount.UID)
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition)
+	return 0, r.updateStatus(ctx, snowflakeAccount)
+}
+
+// changeAdminPassword logs in to the account as its admin with the current password and sets a new
+// password that does not have to be changed on the next login
+func (r *SnowflakeAccountReconciler) changeAdminPassword(ctx context.Context, orgCreds *snowflakeCredentials, accountName, adminName, currentPassword, newPassword string) error {
+	log := logf.FromContext(ctx)
+
+	creds, err := childAccountCredentials(orgCreds, accountName, adminName, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	// The statement holds the new password, so it is not logged
+	log.Info("Changing initial admin password", "account", creds.account, "adminName", adminName)
+	statement := fmt.Sprintf("ALTER USER %s SET PASSWORD = '%s' MUST_CHANGE_PASSWORD = FALSE",
+		userIdentifier(adminName), escapeSQLString(newPassword))
+	if err := r.snowflake().Exec(execCtx, creds, statement); err != nil {
+		return fmt.Errorf("failed to change admin password: %w", classifySnowflakeError(err))
+	}
+	return nil
+}
+
+// confirmAdminPassword logs in to the account as its admin with password, clearing any remaining
+// requirement to change it
+func (r *SnowflakeAccountReconciler) confirmAdminPassword(ctx context.Context, orgCreds *snowflakeCredentials, accountName, adminName, password string) error {
+	creds, err := childAccountCredentials(orgCreds, accountName, adminName, password)
+	if err != nil {
+		return err
+	}
+
+	return r.execChildAccount(ctx, creds, []string{
+		fmt.Sprintf("ALTER USER %s SET MUST_CHANGE_PASSWORD = FALSE", userIdentifier(adminName)),
+	})
+}
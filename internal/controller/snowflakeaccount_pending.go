@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pendingCreateSecretSuffix is appended to the resource name to name the secret holding the generated admin
+// credentials of an account while it is being created
+const pendingCreateSecretSuffix = "-pending-create"
+
+// pendingCreate holds the generated admin credentials of an account stored before its CREATE ACCOUNT
+type pendingCreate struct {
+	accountName   string
+	adminName     string
+	adminPassword string
+}
+
+// pendingCreateSecretKey returns the name and namespace of the secret holding the pending creation of an account
+func pendingCreateSecretKey(account *operatorv1alpha1.SnowflakeAccount) client.ObjectKey {
+	return client.ObjectKey{Namespace: account.Namespace, Name: account.Name + pendingCreateSecretSuffix}
+}
+
+// storePendingCreate stores the generated admin credentials of an account before its CREATE ACCOUNT is run,
+// so an account created by a statement whose outcome is unknown, or whose credentials could not be stored,
+// can still be handed over with its password after the operator restarts. The secret is owned by the
+// resource and deleted once the credentials are stored.
+func (r *SnowflakeAccountReconciler) storePendingCreate(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, pending *pendingCreate) error {
+	key := pendingCreateSecretKey(account)
+	secret := corev1ac.Secret(key.Name, key.Namespace).
+		WithLabels(map[string]string{"app.kubernetes.io/managed-by": "snowflake-operator"}).
+		WithOwnerReferences(ownerReferenceConfigurations([]metav1.OwnerReference{
+			*metav1.NewControllerRef(account, operatorv1alpha1.GroupVersion.WithKind("SnowflakeAccount")),
+		})...).
+		WithType(corev1.SecretTypeOpaque).
+		WithData(map[string][]byte{
+			"accountName":   []byte(pending.accountName),
+			"adminName":     []byte(pending.adminName),
+			"adminPassword": []byte(pending.adminPassword),
+		})
+	if err := r.Apply(ctx, secret, client.FieldOwner(fieldManager)); err != nil {
+		return fmt.Errorf("failed to store the admin credentials before creating the account: %w", err)
+	}
+	return nil
+}
+
+// loadPendingCreate returns the admin credentials stored before the CREATE ACCOUNT of accountName, or nil if
+// none were stored for that account
+func (r *SnowflakeAccountReconciler) loadPendingCreate(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, accountName string) (*pendingCreate, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, pendingCreateSecretKey(account), secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the admin credentials stored before creating the account: %w", err)
+	}
+	if owner := metav1.GetControllerOf(secret); owner == nil || owner.UID != account.UID {
+		return nil, nil
+	}
+	if string(secret.Data["accountName"]) != accountName {
+		return nil, nil
+	}
+	return &pendingCreate{
+		accountName:   accountName,
+		adminName:     string(secret.Data["adminName"]),
+		adminPassword: string(secret.Data["adminPassword"]),
+	}, nil
+}
+
+// deletePendingCreate deletes the admin credentials stored before creating an account once they are stored
+// in the credentials sink
+func (r *SnowflakeAccountReconciler) deletePendingCreate(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, pendingCreateSecretKey(account), secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the admin credentials stored before creating the account: %w", err)
+	}
+	if owner := metav1.GetControllerOf(secret); owner == nil || owner.UID != account.UID {
+		return nil
+	}
+	if err := r.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete the admin credentials stored before creating the account: %w", err)
+	}
+	return nil
+}
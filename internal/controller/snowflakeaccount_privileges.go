@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// privilegeCheckTTL is how long the outcome of a privilege check is reused, which is also how long to
+// wait before re-checking a role that lacks the privilege
+const privilegeCheckTTL = time.Minute
+
+// privilegeCache caches the outcome of privilege checks keyed by org credentials, so that the grants
+// are not listed on every reconcile
+type privilegeCache struct {
+	mu      sync.Mutex
+	entries map[string]privilegeCheck
+}
+
+// privilegeCheck is the outcome of a privilege check
+type privilegeCheck struct {
+	granted   bool
+	checkedAt time.Time
+}
+
+// get returns the cached outcome for the credentials if it was checked within privilegeCheckTTL
+func (c *privilegeCache) get(creds *snowflakeCredentials, now time.Time) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	check, ok := c.entries[creds.cacheKey()]
+	if !ok || now.Sub(check.checkedAt) >= privilegeCheckTTL {
+		return false, false
+	}
+	return check.granted, true
+}
+
+// set records the outcome of a privilege check for the credentials
+func (c *privilegeCache) set(creds *snowflakeCredentials, granted bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]privilegeCheck)
+	}
+	c.entries[creds.cacheKey()] = privilegeCheck{granted: granted, checkedAt: now}
+}
+
+// maxCheckedRoles bounds how many roles of the hierarchy are listed when looking for the CREATE ACCOUNT
+// privilege, as each takes a SHOW GRANTS query
+const maxCheckedRoles = 20
+
+// unquotedIdentifierPattern matches identifiers that Snowflake resolves case-insensitively, in upper case
+var unquotedIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// roleIdentifier returns the name Snowflake stores for a configured role: unquoted identifiers are
+// upper-cased, anything else is taken literally
+func roleIdentifier(role string) string {
+	if unquotedIdentifierPattern.MatchString(role) {
+		return strings.ToUpper(role)
+	}
+	return role
+}
+
+// quoteIdentifier renders a name exactly as stored in Snowflake as a quoted identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// userIdentifier renders a user name as the quoted identifier of the user Snowflake stores it as, so a name
+// from the spec or the credentials secret cannot change the statement it is rendered into
+func userIdentifier(name string) string {
+	return quoteIdentifier(roleIdentifier(name))
+}
+
+// hasCreateAccountPrivilege reports whether SHOW GRANTS TO ROLE rows include the CREATE ACCOUNT
+// privilege, either directly or through the ORGADMIN role being granted to the role, and returns the
+// other roles granted to the role, which may hold it in turn
+func hasCreateAccountPrivilege(grants []map[string]string) (bool, []string) {
+	var grantedRoles []string
+	for _, grant := range grants {
+		privilege := strings.ToUpper(grant["privilege"])
+		grantedOn := strings.ToUpper(grant["granted_on"])
+		if privilege == "CREATE ACCOUNT" && grantedOn == "ACCOUNT" {
+			return true, nil
+		}
+		if privilege == "USAGE" && grantedOn == "ROLE" {
+			if strings.EqualFold(grant["name"], "ORGADMIN") {
+				return true, nil
+			}
+			grantedRoles = append(grantedRoles, grant["name"])
+		}
+	}
+	return false, grantedRoles
+}
+
+// roleHasCreateAccountPrivilege walks the role and the roles granted to it, up to maxCheckedRoles,
+// looking for the CREATE ACCOUNT privilege
+func (r *SnowflakeAccountReconciler) roleHasCreateAccountPrivilege(ctx context.Context, creds *snowflakeCredentials, role string) (bool, error) {
+	pending := []string{role}
+	checked := map[string]bool{}
+	for len(pending) > 0 && len(checked) < maxCheckedRoles {
+		role, pending = pending[0], pending[1:]
+		if checked[role] {
+			continue
+		}
+		checked[role] = true
+
+		grants, err := r.snowflake().ShowGrants(ctx, creds, role)
+		if err != nil {
+			return false, err
+		}
+		granted, grantedRoles := hasCreateAccountPrivilege(grants)
+		if granted {
+			return true, nil
+		}
+		pending = append(pending, grantedRoles...)
+	}
+	return false, nil
+}
+
+// checkCreateAccountPrivilege reports whether the organization role lacks the CREATE ACCOUNT privilege.
+// When it does, the InsufficientPrivileges condition is set and persisted; otherwise a previously set
+// condition is cleared in memory and persisted with the next status update. The check is skipped for
+// credentials without a role, whose default role is not known, and a failure to list the grants does not
+// block creation, which then reports the actual error; both outcomes are cached like a granted privilege.
+func (r *SnowflakeAccountReconciler) checkCreateAccountPrivilege(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return false, err
+	}
+
+	now := r.Clock.Now()
+	granted, cached := r.privileges.get(creds, now)
+	if !cached {
+		if creds.role == "" {
+			log.V(1).Info("Organization credentials have no role, not checking the CREATE ACCOUNT privilege")
+			granted = true
+		} else if granted, err = r.roleHasCreateAccountPrivilege(ctx, creds, roleIdentifier(creds.role)); err != nil {
+			log.Error(err, "Failed to check the CREATE ACCOUNT privilege of the organization role", "role", creds.role)
+			granted = true
+		}
+		r.privileges.set(creds, granted, now)
+	}
+
+	if granted {
+		if meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionInsufficientPrivileges) != nil {
+			meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+				Type:               conditionInsufficientPrivileges,
+				Status:             metav1.ConditionFalse,
+				Reason:             "PrivilegeGranted",
+				Message:            fmt.Sprintf("Role %s has the CREATE ACCOUNT privilege", creds.role),
+				ObservedGeneration: snowflakeAccount.Generation,
+			})
+		}
+		return false, nil
+	}
+
+	log.Info("Organization role lacks the CREATE ACCOUNT privilege, not creating account", "role", creds.role)
+
+	message := fmt.Sprintf("Role %s in organization account %s lacks the CREATE ACCOUNT privilege; "+
+		"grant it the ORGADMIN role or use a role that has it", creds.role, creds.account)
+	snowflakeAccount.Status.Message = message
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionInsufficientPrivileges,
+		Status:             metav1.ConditionTrue,
+		Reason:             "MissingCreateAccount",
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ParseCredentialProfiles parses a comma-separated list of credential profiles of the form
+// name=namespace/secret, mapping each profile name to the secret holding its organization credentials
+func ParseCredentialProfiles(value string) (map[string]types.NamespacedName, error) {
+	profiles := map[string]types.NamespacedName{}
+	if strings.TrimSpace(value) == "" {
+		return profiles, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		name, ref, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid credential profile %q: expected name=namespace/secret", entry)
+		}
+
+		namespace, secretName, ok := strings.Cut(ref, "/")
+		if !ok || namespace == "" || secretName == "" {
+			return nil, fmt.Errorf("invalid credential profile %q: expected name=namespace/secret", entry)
+		}
+
+		if _, exists := profiles[name]; exists {
+			return nil, fmt.Errorf("credential profile %q is defined more than once", name)
+		}
+		profiles[name] = types.NamespacedName{Namespace: namespace, Name: secretName}
+	}
+
+	return profiles, nil
+}
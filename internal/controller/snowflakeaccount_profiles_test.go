@@ -0,0 +1,33 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Credential profiles", func() {
+	It("should parse profiles into secret references", func() {
+		profiles, err := ParseCredentialProfiles("billing=snowflake-system/billing-org, research=snowflake-system/research-org")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profiles).To(Equal(map[string]types.NamespacedName{
+			"billing":  {Namespace: "snowflake-system", Name: "billing-org"},
+			"research": {Namespace: "snowflake-system", Name: "research-org"},
+		}))
+
+		profiles, err = ParseCredentialProfiles("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profiles).To(BeEmpty())
+	})
+
+	DescribeTable("should reject malformed profiles",
+		func(value string) {
+			_, err := ParseCredentialProfiles(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("missing secret reference", "billing"),
+		Entry("missing namespace", "billing=billing-org"),
+		Entry("empty name", "=snowflake-system/billing-org"),
+		Entry("duplicate name", "billing=ns/a,billing=ns/b"),
+	)
+})
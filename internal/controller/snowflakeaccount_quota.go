@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkNamespaceQuota reports whether the namespace already has MaxAccountsPerNamespace created accounts.
+// When it does, the QuotaExceeded condition is set and persisted; otherwise a previously set condition
+// is cleared in memory and persisted with the next status update.
+func (r *SnowflakeAccountReconciler) checkNamespaceQuota(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	if r.MaxAccountsPerNamespace <= 0 {
+		return false, nil
+	}
+
+	log := logf.FromContext(ctx)
+
+	accounts := &operatorv1alpha1.SnowflakeAccountList{}
+	if err := r.List(ctx, accounts, client.InNamespace(snowflakeAccount.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list SnowflakeAccounts: %w", err)
+	}
+
+	created := 0
+	for i := range accounts.Items {
+		if accounts.Items[i].Name != snowflakeAccount.Name && accounts.Items[i].Status.AccountCreated {
+			created++
+		}
+	}
+
+	if created < r.MaxAccountsPerNamespace {
+		if meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionQuotaExceeded) != nil {
+			meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+				Type:               conditionQuotaExceeded,
+				Status:             metav1.ConditionFalse,
+				Reason:             "WithinQuota",
+				Message:            fmt.Sprintf("Namespace has %d of %d allowed accounts", created, r.MaxAccountsPerNamespace),
+				ObservedGeneration: snowflakeAccount.Generation,
+			})
+		}
+		return false, nil
+	}
+
+	log.Info("Namespace account quota reached, not creating account",
+		"created", created, "limit", r.MaxAccountsPerNamespace)
+
+	message := fmt.Sprintf("Namespace %s already has %d of %d allowed accounts", snowflakeAccount.Namespace, created, r.MaxAccountsPerNamespace)
+	snowflakeAccount.Status.Message = message
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionQuotaExceeded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "QuotaExceeded",
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		return true, err
+	}
+	return true, nil
+}
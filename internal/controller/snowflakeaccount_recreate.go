@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// recreateCredentialsSecret recreates the credentials secret of a created account after it was deleted,
+// from the status and the account's SHOW ACCOUNTS row. The secret only counts as deleted when it is found
+// neither as recorded in the status, by its labels nor by its name. A generated admin password cannot be
+// recovered or regenerated from the organization account, so the recreated secret goes without it and the
+// SecretRecreated condition asks for a password reset.
+func (r *SnowflakeAccountReconciler) recreateCredentialsSecret(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	// Only the secret written by the built-in sink can be recreated
+	if !r.storesCredentialsSecret(snowflakeAccount) {
+		return nil
+	}
+
+	// Finish replacing an immutable secret, which keeps the data the account cannot be recreated with
+	if restored, err := r.restoreSecretReplacement(ctx, snowflakeAccount); err != nil || restored {
+		return err
+	}
+
+	secret, err := r.getCredentialsSecret(ctx, snowflakeAccount)
+	if err != nil || secret != nil {
+		return err
+	}
+
+	accountName := snowflakeAccount.Status.SnowflakeAccountName
+	if accountName == "" {
+		accountName = snowflakeAccount.Status.AccountName
+	}
+	if accountName == "" {
+		return nil
+	}
+
+	// A secret that lost its labels is still there under its name; recreating it would overwrite its data
+	existing := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: credentialsSecretName(accountName), Namespace: credentialsSecretNamespace(snowflakeAccount)}, existing)
+	if err == nil {
+		if !ownsCredentialsSecret(snowflakeAccount, existing) {
+			return fmt.Errorf("secret %s/%s does not belong to the resource; delete or rename it to recreate the credentials secret",
+				existing.Namespace, existing.Name)
+		}
+		recordCredentialsSecret(snowflakeAccount, existing)
+		return r.updateStatus(ctx, snowflakeAccount)
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get secret %s: %w", credentialsSecretName(accountName), err)
+	}
+
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	showCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	rows, err := r.snowflake().ShowAccounts(showCtx, creds, accountName)
+	if err != nil {
+		return fmt.Errorf("failed to show account %s: %w", accountName, classifySnowflakeError(err))
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("account %s not found in SHOW ACCOUNTS", accountName)
+	}
+
+	details := accountDetailsFromRow(accountName, rows[0], creds)
+	details.adminName = snowflakeAccount.Status.AdminName
+	if details.adminName == "" {
+		details.adminName = snowflakeAccount.Spec.ExistingAdminName
+	}
+	if details.adminName != "" && !snowflakeAccount.Spec.AdoptExisting {
+		details.email = fmt.Sprintf("%s@example.com", details.adminName)
+	}
+	if details.adminPublicKey, err = normalizeRSAPublicKey(snowflakeAccount.Spec.AdminPublicKey); err != nil {
+		return err
+	}
+	// A reset admin password of an adopted account was generated and only stored in the lost secret
+	details.passwordFromSecretRef = snowflakeAccount.Spec.AdminPasswordSecretRef != nil &&
+		!(snowflakeAccount.Spec.AdoptExisting && snowflakeAccount.Spec.ResetAdminPassword)
+
+	log.Info("Credentials secret was deleted, recreating it", "accountName", accountName)
+	if err := r.createCredentialsSecret(ctx, snowflakeAccount, details); err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionSecretRecreated,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Recreated",
+		Message:            "The credentials secret was deleted and has been recreated",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if !details.passwordFromSecretRef && details.adminPublicKey == "" {
+		condition.Reason = "PasswordLost"
+		condition.Message = fmt.Sprintf("The credentials secret was deleted and has been recreated without the admin password, "+
+			"which cannot be recovered; reset the password of admin user %s in Snowflake", details.adminName)
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition)
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "SecretRecreated", "%s", condition.Message)
+
+	return r.updateStatus(ctx, snowflakeAccount)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedPlaceholder replaces secrets removed from DSNs, errors and logs
+const redactedPlaceholder = "[REDACTED]"
+
+// redactDSN returns the DSN with the password and OAuth token replaced, so it is safe to log
+func redactDSN(dsn string) string {
+	userInfo, rest := "", dsn
+	// The password may itself contain '@' or '?', but the query parameters are escaped,
+	// so the host starts after the last '@'
+	if at := strings.LastIndex(dsn, "@"); at >= 0 {
+		userInfo, rest = dsn[:at], dsn[at:]
+	}
+	if user, _, ok := strings.Cut(userInfo, ":"); ok {
+		userInfo = user + ":" + redactedPlaceholder
+	}
+
+	host, rawQuery, ok := strings.Cut(rest, "?")
+	if !ok {
+		return userInfo + rest
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		if name, _, ok := strings.Cut(param, "="); ok && strings.EqualFold(name, "token") {
+			params[i] = name + "=" + redactedPlaceholder
+		}
+	}
+	return userInfo + host + "?" + strings.Join(params, "&")
+}
+
+// redact replaces the secret material of the credentials in s
+func (c *snowflakeCredentials) redact(s string) string {
+	for _, secret := range []string{c.password, c.oauthToken, url.QueryEscape(c.password), url.QueryEscape(c.oauthToken)} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedPlaceholder)
+		}
+	}
+	return s
+}
+
+// redactError scrubs the secret material of the credentials from err's message. The original error
+// stays reachable through errors.Is and errors.As, but its message must not be logged directly.
+func (c *snowflakeCredentials) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := c.redact(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &redactedError{err: err, message: message}
+}
+
+// redactedError is an error whose message has had secrets removed
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+var _ = Describe("Credential redaction", func() {
+	const password = "p@ss?w:rd&1"
+
+	It("should remove the password and token from DSNs", func() {
+		dsn, err := buildDSN(&snowflakeCredentials{username: "org_admin", password: password, account: "myorg-admin", role: "ORGADMIN"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dsn).To(ContainSubstring(url.QueryEscape(password)))
+		Expect(redactDSN(dsn)).To(Equal("org_admin:[REDACTED]@myorg-admin.snowflakecomputing.com:443" +
+			"?ocspFailOpen=true&role=ORGADMIN&validateDefaultParameters=true"))
+
+		dsn, err = buildDSN(&snowflakeCredentials{username: "org_admin", account: "myorg-admin", role: "ORGADMIN", oauthToken: "tok/en+1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(redactDSN(dsn)).To(Equal("org_admin:[REDACTED]@myorg-admin.snowflakecomputing.com:443" +
+			"?authenticator=oauth&ocspFailOpen=true&role=ORGADMIN&token=[REDACTED]&validateDefaultParameters=true"))
+	})
+
+	It("should never return the password in an error quoting the DSN", func() {
+		creds := &snowflakeCredentials{username: "org_admin", password: password, account: "myorg-admin", role: "ORGADMIN", host: "org.example.com"}
+		dsn, err := buildDSN(creds)
+		Expect(err).NotTo(HaveOccurred())
+		sfErr := &gosnowflake.SnowflakeError{Number: 260008, Message: "failed to parse " + dsn}
+
+		err = classifySnowflakeError(creds.redactError(fmt.Errorf("failed to open connection: %w", sfErr)))
+		Expect(err.Error()).NotTo(ContainSubstring(password))
+		Expect(err.Error()).To(ContainSubstring("org_admin:[REDACTED]@org.example.com"))
+
+		var unwrapped *gosnowflake.SnowflakeError
+		Expect(errors.As(err, &unwrapped)).To(BeTrue())
+		Expect(unwrapped.Number).To(Equal(260008))
+	})
+
+	It("should leave errors without secrets untouched", func() {
+		creds := &snowflakeCredentials{password: password}
+		err := errors.New("connection refused")
+		Expect(creds.redactError(err)).To(BeIdenticalTo(err))
+		Expect(creds.redactError(nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ParseRegions parses a comma-separated list of Snowflake regions, ignoring empty entries
+func ParseRegions(value string) []string {
+	var regions []string
+	for _, region := range strings.Split(value, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			regions = append(regions, strings.ToUpper(region))
+		}
+	}
+	return regions
+}
+
+// regionPattern matches the Snowflake region identifiers accepted in Spec.Region
+var regionPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ParseDefaultRegion validates an operator-wide default region, returning it in upper case
+func ParseDefaultRegion(value string) (string, error) {
+	if value != "" && !regionPattern.MatchString(value) {
+		return "", fmt.Errorf("invalid region %q: must contain only letters, digits and underscores", value)
+	}
+	return strings.ToUpper(value), nil
+}
+
+// defaultRegion returns the region of accounts created with the fixed strategy without Spec.Region
+func (r *SnowflakeAccountReconciler) defaultRegion() string {
+	if r.DefaultRegion != "" {
+		return r.DefaultRegion
+	}
+	return operatorv1alpha1.DefaultRegion
+}
+
+// selectRegion picks the region for a new account according to Spec.RegionSelectionStrategy
+func (r *SnowflakeAccountReconciler) selectRegion(account *operatorv1alpha1.SnowflakeAccount) (string, error) {
+	strategy := account.Spec.RegionSelectionStrategy
+	if strategy == "" || strategy == operatorv1alpha1.RegionSelectionFixed {
+		if account.Spec.Region != "" {
+			return strings.ToUpper(account.Spec.Region), nil
+		}
+		return r.defaultRegion(), nil
+	}
+
+	if len(r.AllowedRegions) == 0 {
+		return "", fmt.Errorf("region selection strategy %q requires the operator to be configured with --allowed-regions", strategy)
+	}
+
+	switch strategy {
+	case operatorv1alpha1.RegionSelectionRoundRobin:
+		next := r.regionCounter.Add(1) - 1
+		return r.AllowedRegions[next%uint64(len(r.AllowedRegions))], nil
+	case operatorv1alpha1.RegionSelectionRandom:
+		return r.AllowedRegions[rand.IntN(len(r.AllowedRegions))], nil
+	default:
+		return "", fmt.Errorf("unsupported region selection strategy %q", strategy)
+	}
+}
+
+// regionUnavailableRequeueInterval is how long to wait before retrying in a region Snowflake reported
+// as unavailable, once no fallback region is left to try
+const regionUnavailableRequeueInterval = 15 * time.Minute
+
+// regionFallbackRequeueInterval is how long to wait before retrying in the next fallback region
+const regionFallbackRequeueInterval = 5 * time.Second
+
+// nextFallbackRegion returns the first of Spec.FallbackRegions not reported as unavailable, or ""
+func nextFallbackRegion(account *operatorv1alpha1.SnowflakeAccount) string {
+	for _, region := range account.Spec.FallbackRegions {
+		region = strings.ToUpper(region)
+		if !slices.Contains(account.Status.UnavailableRegions, region) && region != "" {
+			return region
+		}
+	}
+	return ""
+}
+
+// availableRegion returns the region to create the account in: the selected region, unless Snowflake
+// reported it as unavailable and a fallback region is left to try
+func availableRegion(account *operatorv1alpha1.SnowflakeAccount, selected string) string {
+	if !slices.Contains(account.Status.UnavailableRegions, selected) {
+		return selected
+	}
+	if fallback := nextFallbackRegion(account); fallback != "" {
+		return fallback
+	}
+	return selected
+}
+
+// handleRegionUnavailable records that account creation failed because its region was unavailable and
+// decides when to retry: right away in the next fallback region, or after a longer wait in the selected
+// region once every fallback region was tried
+func (r *SnowflakeAccountReconciler) handleRegionUnavailable(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, err error) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	unavailable := snowflakeAccount.Status.UnavailableRegions
+	requeueAfter := regionFallbackRequeueInterval
+	reason := "FallingBack"
+	fallback := nextFallbackRegion(snowflakeAccount)
+	message := fmt.Sprintf("Regions %s are unavailable; retrying in fallback region %s", strings.Join(unavailable, ", "), fallback)
+	if fallback == "" {
+		// Start over with the selected region once the wait is over
+		requeueAfter = regionUnavailableRequeueInterval
+		reason = "NoFallbackRegion"
+		message = fmt.Sprintf("Regions %s are unavailable; retrying in %s", strings.Join(unavailable, ", "), requeueAfter)
+		snowflakeAccount.Status.UnavailableRegions = nil
+	}
+
+	log.Error(err, "Snowflake region unavailable", "unavailableRegions", unavailable, "fallbackRegion", fallback, "after", requeueAfter)
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "RegionUnavailable", "%s", message)
+
+	snowflakeAccount.Status.Phase = operatorv1alpha1.PhasePending
+	snowflakeAccount.Status.Message = message
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionRegionUnavailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("%s: %v", message, err),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// clearUnavailableRegions forgets the unavailable regions once the account has been created
+func clearUnavailableRegions(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	snowflakeAccount.Status.UnavailableRegions = nil
+	if meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionRegionUnavailable) == nil {
+		return
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionRegionUnavailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AccountCreated",
+		Message:            fmt.Sprintf("The account was created in region %s", snowflakeAccount.Status.Region),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("Region selection", func() {
+	var reconciler *SnowflakeAccountReconciler
+
+	BeforeEach(func() {
+		reconciler = &SnowflakeAccountReconciler{
+			AllowedRegions: ParseRegions("aws_us_west_2, AWS_EU_CENTRAL_1,,AZURE_EASTUS2"),
+		}
+	})
+
+	accountWith := func(strategy operatorv1alpha1.RegionSelectionStrategy, region string) *operatorv1alpha1.SnowflakeAccount {
+		return &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{RegionSelectionStrategy: strategy, Region: region},
+		}
+	}
+
+	It("should use Spec.Region or the default with the fixed strategy", func() {
+		Expect(reconciler.selectRegion(accountWith(operatorv1alpha1.RegionSelectionFixed, "gcp_us_central1"))).To(Equal("GCP_US_CENTRAL1"))
+		Expect(reconciler.selectRegion(accountWith("", ""))).To(Equal(operatorv1alpha1.DefaultRegion))
+	})
+
+	It("should prefer the operator's default region over the built-in one", func() {
+		region, err := ParseDefaultRegion("aws_eu_central_1")
+		Expect(err).NotTo(HaveOccurred())
+		reconciler.DefaultRegion = region
+
+		Expect(reconciler.selectRegion(accountWith("", ""))).To(Equal("AWS_EU_CENTRAL_1"))
+		Expect(reconciler.selectRegion(accountWith("", "gcp_us_central1"))).To(Equal("GCP_US_CENTRAL1"))
+
+		_, err = ParseDefaultRegion("aws-us-west-2")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should validate the operator's default edition", func() {
+		Expect(ParseDefaultEdition("business_critical")).To(Equal(operatorv1alpha1.EditionBusinessCritical))
+		Expect(ParseDefaultEdition("")).To(BeEmpty())
+		_, err := ParseDefaultEdition("PLATINUM")
+		Expect(err).To(MatchError(ContainSubstring(`invalid edition "PLATINUM"`)))
+	})
+
+	It("should cycle through the allowed regions with round-robin", func() {
+		account := accountWith(operatorv1alpha1.RegionSelectionRoundRobin, "")
+		var regions []string
+		for range 4 {
+			region, err := reconciler.selectRegion(account)
+			Expect(err).NotTo(HaveOccurred())
+			regions = append(regions, region)
+		}
+		Expect(regions).To(Equal([]string{"AWS_US_WEST_2", "AWS_EU_CENTRAL_1", "AZURE_EASTUS2", "AWS_US_WEST_2"}))
+	})
+
+	It("should pick an allowed region at random", func() {
+		region, err := reconciler.selectRegion(accountWith(operatorv1alpha1.RegionSelectionRandom, ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.AllowedRegions).To(ContainElement(region))
+	})
+
+	It("should require allowed regions for pooled strategies", func() {
+		reconciler.AllowedRegions = nil
+		_, err := reconciler.selectRegion(accountWith(operatorv1alpha1.RegionSelectionRandom, ""))
+		Expect(err).To(HaveOccurred())
+	})
+})
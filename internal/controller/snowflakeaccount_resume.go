@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resumeFromCredentialsSecret finishes provisioning an account whose credentials secret was stored by an
+// earlier reconcile that failed before recording the account as created, instead of creating a second
+// account. It reports handled=false when there is no credentials secret and the account must be created.
+func (r *SnowflakeAccountReconciler) resumeFromCredentialsSecret(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (result ctrl.Result, handled bool, err error) {
+	log := logf.FromContext(ctx)
+
+	accountName, err := r.getAccountNameFromSecret(ctx, snowflakeAccount)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+	if accountName == "" {
+		return ctrl.Result{}, false, nil
+	}
+
+	log.Info("Credentials secret already exists, resuming provisioning instead of creating another account",
+		"accountName", accountName)
+
+	details, err := r.accountDetailsFromSecret(ctx, snowflakeAccount)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	// Pick up where the earlier reconcile stopped
+	if snowflakeAccount.Spec.WaitForDNS {
+		result, err := r.startWaitingForAccountDNS(ctx, snowflakeAccount, details)
+		return result, true, err
+	}
+
+	if err := r.updateStatusAfterCreation(ctx, snowflakeAccount, details); err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	log.Info("Recorded previously created Snowflake account from its credentials secret", "accountName", accountName)
+	return r.expiryRequeue(ctx, snowflakeAccount), true, nil
+}
+
+// resumeUnfinishedCreate finishes provisioning an account whose name was recorded by an earlier reconcile,
+// either because its CREATE ACCOUNT did not finish in time or because its credentials could not be stored,
+// after checking with SHOW ACCOUNTS whether it exists. An account that does not exist yet may still be
+// created by the earlier statement, so its name is kept and handled=false is reported for the CREATE ACCOUNT
+// to be retried with the same name, which then fails instead of creating a second account.
+func (r *SnowflakeAccountReconciler) resumeUnfinishedCreate(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (result ctrl.Result, handled bool, err error) {
+	log := logf.FromContext(ctx)
+
+	accountName := snowflakeAccount.Status.SnowflakeAccountName
+	if accountName == "" {
+		return ctrl.Result{}, false, nil
+	}
+
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	showCtx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	rows, err := r.snowflake().ShowAccounts(showCtx, creds, accountName)
+	if err != nil {
+		return ctrl.Result{}, true, fmt.Errorf("failed to show account %s: %w", accountName, classifySnowflakeError(err))
+	}
+	if len(rows) == 0 {
+		log.Info("Earlier CREATE ACCOUNT has not created the account, retrying it with the same name", "accountName", accountName)
+		return ctrl.Result{}, false, nil
+	}
+
+	log.Info("Account of an earlier CREATE ACCOUNT exists, resuming provisioning instead of creating another account",
+		"accountName", accountName)
+
+	details, err := r.unfinishedAccountDetails(ctx, snowflakeAccount, accountName, rows[0], creds)
+	if err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	result, err = r.finishCreation(ctx, snowflakeAccount, details)
+	return result, true, err
+}
+
+// unfinishedAccountDetails rebuilds the details of an account whose creation was not recorded from its
+// SHOW ACCOUNTS row, the status and the admin credentials stored before its CREATE ACCOUNT. A generated
+// admin password that was not stored cannot be recovered, so it is left out and a warning event asks for it
+// to be reset.
+func (r *SnowflakeAccountReconciler) unfinishedAccountDetails(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount,
+	accountName string, row map[string]string, creds *snowflakeCredentials) (*accountDetails, error) {
+	details := accountDetailsFromRow(accountName, row, creds)
+	details.adminName = snowflakeAccount.Status.AdminName
+	if details.adminName != "" {
+		details.email = fmt.Sprintf("%s@example.com", details.adminName)
+	}
+	var err error
+	if details.adminPublicKey, err = normalizeRSAPublicKey(snowflakeAccount.Spec.AdminPublicKey); err != nil {
+		return nil, err
+	}
+	details.billingEntity = strings.ToUpper(snowflakeAccount.Spec.BillingEntity)
+	details.tags = r.accountTags(snowflakeAccount, details.billingEntity)
+
+	// A referenced password stays in its own secret and is read from there when needed
+	details.passwordFromSecretRef = snowflakeAccount.Spec.AdminPasswordSecretRef != nil
+	if details.passwordFromSecretRef || details.adminPublicKey != "" {
+		return details, nil
+	}
+	pending, err := r.loadPendingCreate(ctx, snowflakeAccount, accountName)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil {
+		r.eventf(snowflakeAccount, corev1.EventTypeWarning, "AdminPasswordLost",
+			"The generated admin password of account %s could not be recovered and must be reset", accountName)
+		return details, nil
+	}
+	details.adminPassword = pending.adminPassword
+	if details.adminName == "" {
+		details.adminName = pending.adminName
+		details.email = fmt.Sprintf("%s@example.com", details.adminName)
+	}
+	return details, nil
+}
+
+// accountDetailsFromSecret rebuilds the details of a created account from its credentials secret,
+// which it records in the status
+func (r *SnowflakeAccountReconciler) accountDetailsFromSecret(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (*accountDetails, error) {
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := r.getCredentialsSecret(ctx, snowflakeAccount)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("credentials secret for account not found")
+	}
+	recordCredentialsSecret(snowflakeAccount, secret)
+
+	data := secret.Data
+	if snowflakeAccount.Spec.SplitCredentials {
+		if data, err = r.withConnInfo(ctx, snowflakeAccount, data); err != nil {
+			return nil, err
+		}
+	}
+
+	// The billing entity is not stored in the secret; the spec the account was just created from holds it
+	billingEntity := strings.ToUpper(snowflakeAccount.Spec.BillingEntity)
+	details := &accountDetails{
+		accountName:    string(data["accountName"]),
+		adminName:      string(data["adminName"]),
+		adminPassword:  string(data["adminPassword"]),
+		adminPublicKey: string(data["adminPublicKey"]),
+		email:          string(data["email"]),
+		region:         string(data["region"]),
+		edition:        string(data["edition"]),
+		accountURL:     string(data["accountURL"]),
+		accountLocator: string(data["accountLocator"]),
+		billingEntity:  billingEntity,
+		tags:           r.accountTags(snowflakeAccount, billingEntity),
+		orgAccount:     creds.account,
+		orgRole:        creds.role,
+	}
+	if details.accountURL == "" {
+		details.accountURL = buildAccountURL(details.accountName, creds)
+	}
+	return details, nil
+}
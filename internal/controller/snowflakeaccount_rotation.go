@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// credentialsSecretIndexField indexes SnowflakeAccounts by the namespace/name of the secret
+// holding their organization credentials
+const credentialsSecretIndexField = ".spec.orgCredentialsSecret"
+
+// credentialsSecretKeys returns the organization credentials secret an account uses, if any,
+// for indexing. Accounts using the environment credentials are not indexed.
+func (r *SnowflakeAccountReconciler) credentialsSecretKeys(obj client.Object) []string {
+	snowflakeAccount, ok := obj.(*operatorv1alpha1.SnowflakeAccount)
+	if !ok {
+		return nil
+	}
+
+	if ref := snowflakeAccount.Spec.OrgCredentialsSecretRef; ref != nil && ref.Name != "" {
+		return []string{types.NamespacedName{Namespace: snowflakeAccount.Namespace, Name: ref.Name}.String()}
+	}
+	if key, ok := r.CredentialProfiles[snowflakeAccount.Spec.CredentialProfile]; ok {
+		return []string{key.String()}
+	}
+	return nil
+}
+
+// accountsForCredentialsSecret maps a changed secret to the accounts using it as their organization
+// credentials, so a rotated password is picked up without waiting for the next requeue
+func (r *SnowflakeAccountReconciler) accountsForCredentialsSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	log := logf.FromContext(ctx)
+
+	key := client.ObjectKeyFromObject(secret).String()
+	accounts := &operatorv1alpha1.SnowflakeAccountList{}
+	if err := r.List(ctx, accounts, client.MatchingFields{credentialsSecretIndexField: key}); err != nil {
+		log.Error(err, "Failed to list accounts using organization credentials secret", "secret", key)
+		return nil
+	}
+	if len(accounts.Items) == 0 {
+		return nil
+	}
+
+	log.Info("Organization credentials secret changed, reloading credentials", "secret", key, "accounts", len(accounts.Items))
+	requests := make([]reconcile.Request, 0, len(accounts.Items))
+	for i := range accounts.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&accounts.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("Organization credentials rotation", func() {
+	It("should reconcile the accounts using a changed credentials secret", func() {
+		reconciler := &SnowflakeAccountReconciler{
+			CredentialProfiles: map[string]types.NamespacedName{
+				"prod": {Namespace: "operator", Name: "prod-org"},
+			},
+		}
+
+		accountWith := func(name string, spec operatorv1alpha1.SnowflakeAccountSpec) *operatorv1alpha1.SnowflakeAccount {
+			return &operatorv1alpha1.SnowflakeAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+				Spec:       spec,
+			}
+		}
+		reconciler.Client = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithIndex(&operatorv1alpha1.SnowflakeAccount{}, credentialsSecretIndexField, reconciler.credentialsSecretKeys).
+			WithObjects(
+				accountWith("by-ref", operatorv1alpha1.SnowflakeAccountSpec{
+					OrgCredentialsSecretRef: &corev1.LocalObjectReference{Name: "team-org"},
+				}),
+				accountWith("by-profile", operatorv1alpha1.SnowflakeAccountSpec{CredentialProfile: "prod"}),
+				accountWith("from-env", operatorv1alpha1.SnowflakeAccountSpec{}),
+			).
+			Build()
+
+		secretNamed := func(namespace, name string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		}
+
+		Expect(reconciler.accountsForCredentialsSecret(context.Background(), secretNamed("team-a", "team-org"))).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "by-ref"}},
+		))
+		Expect(reconciler.accountsForCredentialsSecret(context.Background(), secretNamed("operator", "prod-org"))).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "by-profile"}},
+		))
+		Expect(reconciler.accountsForCredentialsSecret(context.Background(), secretNamed("team-a", "unrelated"))).To(BeEmpty())
+	})
+})
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// conditionInterrupted indicates a Snowflake operation was cut short by the operator shutting down
+	conditionInterrupted = "Interrupted"
+
+	// interruptedStatusTimeout bounds the status update recording an interruption, which has to
+	// outlive the cancelled reconcile context
+	interruptedStatusTimeout = 5 * time.Second
+)
+
+// recordInterruption sets the Interrupted condition if ctx was cancelled, which happens when the
+// manager stops while a Snowflake statement is running. It reports whether ctx was cancelled.
+//
+// The reconcile context is derived from the manager's context, so shutting down the operator
+// cancels in-flight statements; the statement may still have completed in Snowflake.
+func (r *SnowflakeAccountReconciler) recordInterruption(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, operation string, err error) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+
+	log := logf.FromContext(ctx)
+	log.Info("Snowflake operation interrupted by operator shutdown", "operation", operation, "error", err.Error())
+
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionInterrupted,
+		Status:             metav1.ConditionTrue,
+		Reason:             "OperatorShutdown",
+		Message:            fmt.Sprintf("%s was interrupted by operator shutdown and will be retried; it may have completed in Snowflake: %v", operation, err),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+
+	statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptedStatusTimeout)
+	defer cancel()
+	if statusErr := r.updateStatus(statusCtx, snowflakeAccount); statusErr != nil {
+		log.Error(statusErr, "Failed to record interruption")
+	}
+	return true
+}
+
+// clearInterruption marks a previously interrupted operation as completed
+func clearInterruption(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) {
+	if !meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionInterrupted) {
+		return
+	}
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionInterrupted,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Completed",
+		Message:            "The interrupted operation has since completed",
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AccountCredentials are the details of a provisioned account a CredentialsSink stores. AdminPassword is
+// empty when the password is read from Spec.AdminPasswordSecretRef or the admin only has a public key.
+type AccountCredentials struct {
+	AccountName    string
+	AccountURL     string
+	AccountLocator string
+	AdminName      string
+	AdminPassword  string
+	AdminPublicKey string
+	Email          string
+	Region         string
+	Edition        string
+}
+
+// CredentialsSink stores the details of a provisioned account, including the admin credentials, where its
+// users pick them up. The sink of an account is selected by Spec.CredentialsSink.
+type CredentialsSink interface {
+	// Write stores the account details, replacing those of an earlier write, and returns a description
+	// of where they are stored for the SecretReady condition
+	Write(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, credentials *AccountCredentials) (string, error)
+	// Read returns the stored account details, which the operator logs in to the account with, or nil if
+	// there are none
+	Read(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*AccountCredentials, error)
+	// Delete removes the stored details when the resource is finalized; it succeeds if there are none
+	Delete(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error
+}
+
+// sinkCredentials returns the details of the account a CredentialsSink stores. A user-supplied password
+// is left out; it already lives in the referenced secret.
+func (d *accountDetails) sinkCredentials() *AccountCredentials {
+	credentials := &AccountCredentials{
+		AccountName:    d.accountName,
+		AccountURL:     d.accountURL,
+		AccountLocator: d.accountLocator,
+		AdminName:      d.adminName,
+		AdminPassword:  d.adminPassword,
+		AdminPublicKey: d.adminPublicKey,
+		Email:          d.email,
+		Region:         d.region,
+		Edition:        d.edition,
+	}
+	if d.passwordFromSecretRef {
+		credentials.AdminPassword = ""
+	}
+	return credentials
+}
+
+// kubernetesSink is the built-in CredentialsSink, storing the details in the credentials secret
+type kubernetesSink struct {
+	r *SnowflakeAccountReconciler
+}
+
+// Write applies the credentials secret and confirms it can be read back
+func (s *kubernetesSink) Write(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, credentials *AccountCredentials) (string, error) {
+	details := &accountDetails{
+		accountName:    credentials.AccountName,
+		adminName:      credentials.AdminName,
+		adminPassword:  credentials.AdminPassword,
+		adminPublicKey: credentials.AdminPublicKey,
+		email:          credentials.Email,
+		region:         credentials.Region,
+		edition:        credentials.Edition,
+		accountURL:     credentials.AccountURL,
+		accountLocator: credentials.AccountLocator,
+	}
+	if err := s.r.createCredentialsSecret(ctx, account, details); err != nil {
+		return "", err
+	}
+
+	key := client.ObjectKey{Namespace: credentialsSecretNamespace(account), Name: credentialsSecretName(details.accountName)}
+	if err := s.r.Get(ctx, key, &corev1.Secret{}); err != nil {
+		return "", fmt.Errorf("failed to confirm secret %s: %w", key, err)
+	}
+	return fmt.Sprintf("secret %s", key.Name), nil
+}
+
+// Read returns the details stored in the credentials secret, along with those split into the conninfo
+// ConfigMap
+func (s *kubernetesSink) Read(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) (*AccountCredentials, error) {
+	secret, err := s.r.getCredentialsSecret(ctx, account)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	data := secret.Data
+	if account.Spec.SplitCredentials {
+		if data, err = s.r.withConnInfo(ctx, account, data); err != nil {
+			return nil, err
+		}
+	}
+	return &AccountCredentials{
+		AccountName:    string(data["accountName"]),
+		AccountURL:     string(data["accountURL"]),
+		AccountLocator: string(data["accountLocator"]),
+		AdminName:      string(data["adminName"]),
+		AdminPassword:  string(data["adminPassword"]),
+		AdminPublicKey: string(data["adminPublicKey"]),
+		Email:          string(data["email"]),
+		Region:         string(data["region"]),
+		Edition:        string(data["edition"]),
+	}, nil
+}
+
+// Delete deletes the credentials secret along with the objects stored next to it
+func (s *kubernetesSink) Delete(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	return s.r.deleteCredentialsSecret(ctx, account)
+}
+
+// credentialsSinkType returns the sink type selected by Spec.CredentialsSink, defaulting to Kubernetes
+func credentialsSinkType(account *operatorv1alpha1.SnowflakeAccount) operatorv1alpha1.CredentialsSinkType {
+	if account.Spec.CredentialsSink == "" {
+		return operatorv1alpha1.CredentialsSinkKubernetes
+	}
+	return account.Spec.CredentialsSink
+}
+
+// credentialsSink returns the sink selected by Spec.CredentialsSink, defaulting to the credentials secret
+func (r *SnowflakeAccountReconciler) credentialsSink(account *operatorv1alpha1.SnowflakeAccount) (CredentialsSink, error) {
+	sinkType := credentialsSinkType(account)
+	if sink, ok := r.CredentialsSinks[sinkType]; ok {
+		return sink, nil
+	}
+	if sinkType == operatorv1alpha1.CredentialsSinkKubernetes {
+		return &kubernetesSink{r: r}, nil
+	}
+	return nil, fmt.Errorf("credentials sink %q is not available in this operator", sinkType)
+}
+
+// storesCredentialsSecret reports whether the account details are written by the built-in sink to the
+// credentials secret, which the operator can then read, recreate and leave to garbage collection
+func (r *SnowflakeAccountReconciler) storesCredentialsSecret(account *operatorv1alpha1.SnowflakeAccount) bool {
+	sink, err := r.credentialsSink(account)
+	if err != nil {
+		return false
+	}
+	_, ok := sink.(*kubernetesSink)
+	return ok
+}
+
+// deleteStoredCredentials deletes the account details from the sink they were written to. Nothing can
+// have been written to a sink the operator does not have, so it is skipped.
+func (r *SnowflakeAccountReconciler) deleteStoredCredentials(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount) error {
+	sink, err := r.credentialsSink(account)
+	if err != nil {
+		logf.FromContext(ctx).Info("Not deleting stored credentials", "reason", err.Error())
+		return nil
+	}
+	return sink.Delete(ctx, account)
+}
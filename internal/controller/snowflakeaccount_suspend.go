@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reconcileSuspension suspends or resumes the Snowflake account from the organization account so that it
+// matches Spec.Suspended, recording the outcome in the Suspended condition
+func (r *SnowflakeAccountReconciler) reconcileSuspension(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	suspend := snowflakeAccount.Spec.Suspended
+	condition := meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionSuspended)
+	suspended := condition != nil && condition.Status == metav1.ConditionTrue
+
+	// Nothing to do once the account is in the requested state; the condition is only reported
+	// once the account has been suspended
+	if suspend == suspended {
+		return nil
+	}
+
+	accountName := snowflakeAccount.Status.AccountName
+	if accountName == "" {
+		log.Info("Account name is unknown, cannot change the suspension of the account")
+		return nil
+	}
+
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return err
+	}
+
+	statement, reason, failedReason := fmt.Sprintf("ALTER ACCOUNT %s RESUME", accountName), "Resumed", "ResumeFailed"
+	if suspend {
+		statement, reason, failedReason = fmt.Sprintf("ALTER ACCOUNT %s SUSPEND", accountName), "Suspended", "SuspendFailed"
+	}
+
+	log.Info("Executing statement in organization account", "sql", statement)
+	if err := r.snowflake().Exec(ctx, creds, statement); err != nil {
+		err = fmt.Errorf("failed to execute %q: %w", statement, classifySnowflakeError(err))
+		// The account keeps its previous state until the statement succeeds
+		meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+			Type:               conditionSuspended,
+			Status:             conditionStatus(suspended),
+			Reason:             failedReason,
+			Message:            err.Error(),
+			ObservedGeneration: snowflakeAccount.Generation,
+		})
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return err
+	}
+
+	message := fmt.Sprintf("Snowflake account %s resumed", accountName)
+	if suspend {
+		message = fmt.Sprintf("Snowflake account %s suspended", accountName)
+	}
+	snowflakeAccount.Status.Message = message
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionSuspended,
+		Status:             conditionStatus(suspend),
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	return r.updateStatus(ctx, snowflakeAccount)
+}
+
+// conditionStatus converts a boolean into a condition status
+func conditionStatus(value bool) metav1.ConditionStatus {
+	if value {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// runExpirySweeper periodically re-enqueues created accounts past their expiry until ctx is cancelled.
+// Per-object requeues are lost when the operator restarts, so this enforces durations independently of them.
+func (r *SnowflakeAccountReconciler) runExpirySweeper(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("expiry-sweeper")
+	ctx = logf.IntoContext(ctx, log)
+
+	ticker := time.NewTicker(r.ExpirySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.sweepExpiredAccounts(ctx); err != nil {
+				log.Error(err, "Failed to sweep expired accounts")
+			}
+		}
+	}
+}
+
+// sweepExpiredAccounts lists all accounts and sends a reconcile event for each created account past its expiry
+func (r *SnowflakeAccountReconciler) sweepExpiredAccounts(ctx context.Context) error {
+	log := logf.FromContext(ctx)
+
+	accounts := &operatorv1alpha1.SnowflakeAccountList{}
+	if err := r.List(ctx, accounts); err != nil {
+		return err
+	}
+
+	for i := range accounts.Items {
+		account := &accounts.Items[i]
+		if !account.DeletionTimestamp.IsZero() || !r.accountExpired(account) {
+			continue
+		}
+
+		log.Info("Re-enqueueing expired account", "namespace", account.Namespace, "name", account.Name)
+		select {
+		case r.expiredAccounts <- event.GenericEvent{Object: account}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// accountExpired reports whether a created account has outlived a valid Spec.Duration
+func (r *SnowflakeAccountReconciler) accountExpired(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) bool {
+	if !snowflakeAccount.Status.AccountCreated || snowflakeAccount.Status.CreationTime == nil {
+		return false
+	}
+
+	duration, _ := accountDuration(snowflakeAccount)
+	if r.validateDuration(duration) != nil {
+		return false
+	}
+	return r.Clock.Now().After(snowflakeAccount.Status.CreationTime.Add(duration))
+}
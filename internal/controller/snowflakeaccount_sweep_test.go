@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("Expiry sweep", func() {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var reconciler *SnowflakeAccountReconciler
+
+	BeforeEach(func() {
+		reconciler = &SnowflakeAccountReconciler{
+			Client:          k8sClient,
+			Clock:           clocktesting.NewFakePassiveClock(now),
+			expiredAccounts: make(chan event.GenericEvent, 10),
+		}
+	})
+
+	createAccount := func(name, duration string, createdAgo time.Duration, created bool) {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       operatorv1alpha1.SnowflakeAccountSpec{Duration: duration},
+		}
+		Expect(k8sClient.Create(ctx, account)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, account)).To(Succeed()) })
+
+		creationTime := metav1.NewTime(now.Add(-createdAgo))
+		account.Status.AccountCreated = created
+		account.Status.CreationTime = &creationTime
+		Expect(k8sClient.Status().Update(ctx, account)).To(Succeed())
+	}
+
+	It("should re-enqueue only created accounts past their expiry", func() {
+		createAccount("sweep-expired", "1h", 2*time.Hour, true)
+		createAccount("sweep-active", "1h", 30*time.Minute, true)
+		createAccount("sweep-not-created", "1h", 2*time.Hour, false)
+		createAccount("sweep-invalid", "0s", 2*time.Hour, true)
+
+		Expect(reconciler.sweepExpiredAccounts(ctx)).To(Succeed())
+		close(reconciler.expiredAccounts)
+
+		var names []string
+		for evt := range reconciler.expiredAccounts {
+			names = append(names, evt.Object.GetName())
+		}
+		Expect(names).To(ConsistOf("sweep-expired"))
+	})
+})
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// DefaultCreateAccountTemplate is the text/template rendering the CREATE ACCOUNT statement unless the
+// operator is given another one. It is executed with createAccountData; values must be passed through
+// quote (or escape, inside a quoted literal) so they cannot break out of their SQL literal.
+const DefaultCreateAccountTemplate = `
+        CREATE ACCOUNT {{ .AccountName }}
+            ADMIN_NAME = {{ quote .AdminName }}
+            {{ adminAuth .AdminPassword .AdminPublicKey }}
+            ADMIN_USER_TYPE = PERSON
+            FIRST_NAME = {{ quote .FirstName }}
+            LAST_NAME = {{ quote .LastName }}
+            EMAIL = {{ quote .Email }}
+            EDITION = {{ .Edition }}
+            REGION = {{ quote .Region }}
+            {{ billingEntityClause .BillingEntity }}
+            COMMENT = {{ quote .Comment }}
+            {{ tagClause .Tags }}
+    `
+
+// createAccountData is the data model of the CREATE ACCOUNT template
+type createAccountData struct {
+	AccountName    string
+	AdminName      string
+	AdminPassword  string
+	AdminPublicKey string
+	FirstName      string
+	LastName       string
+	Email          string
+	Edition        string
+	Region         string
+	BillingEntity  string
+	Comment        string
+	Tags           map[string]string
+
+	// Spec is the spec of the SnowflakeAccount being created
+	Spec operatorv1alpha1.SnowflakeAccountSpec
+}
+
+// createAccountTemplateFuncs are the functions available to the CREATE ACCOUNT template
+var createAccountTemplateFuncs = template.FuncMap{
+	"quote": func(value string) string {
+		return "'" + escapeSQLString(value) + "'"
+	},
+	"escape":              escapeSQLString,
+	"adminAuth":           buildAdminAuthClause,
+	"tagClause":           buildTagClause,
+	"billingEntityClause": buildBillingEntityClause,
+}
+
+// defaultCreateAccountTemplate is DefaultCreateAccountTemplate, parsed once
+var defaultCreateAccountTemplate = template.Must(parseCreateAccountTemplate(DefaultCreateAccountTemplate))
+
+// parseCreateAccountTemplate parses a CREATE ACCOUNT template, failing on references to unknown fields
+// when it is executed
+func parseCreateAccountTemplate(text string) (*template.Template, error) {
+	return template.New("create-account").Funcs(createAccountTemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// LoadCreateAccountTemplate reads and parses the CREATE ACCOUNT template at path, typically mounted
+// from a ConfigMap. An empty path returns the default template.
+func LoadCreateAccountTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return defaultCreateAccountTemplate, nil
+	}
+
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CREATE ACCOUNT template: %w", err)
+	}
+	tmpl, err := parseCreateAccountTemplate(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CREATE ACCOUNT template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// renderCreateAccountSQL renders the CREATE ACCOUNT statement from the configured template
+func (r *SnowflakeAccountReconciler) renderCreateAccountSQL(data *createAccountData) (string, error) {
+	tmpl := r.CreateAccountTemplate
+	if tmpl == nil {
+		tmpl = defaultCreateAccountTemplate
+	}
+
+	var sql strings.Builder
+	if err := tmpl.Execute(&sql, data); err != nil {
+		return "", fmt.Errorf("failed to render CREATE ACCOUNT template: %w", err)
+	}
+	if strings.TrimSpace(sql.String()) == "" {
+		return "", fmt.Errorf("the CREATE ACCOUNT template rendered an empty statement")
+	}
+	return sql.String(), nil
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("CREATE ACCOUNT template", func() {
+	data := func() *createAccountData {
+		return &createAccountData{
+			AccountName:   "ACCT",
+			AdminName:     "admin",
+			AdminPassword: "secret",
+			Edition:       "STANDARD",
+			Region:        "AWS_US_WEST_2",
+			Comment:       "it's mine",
+			Spec:          operatorv1alpha1.SnowflakeAccountSpec{DesiredAccountName: "acct"},
+		}
+	}
+
+	It("should render the built-in statement by default", func() {
+		sql, err := (&SnowflakeAccountReconciler{}).renderCreateAccountSQL(data())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sql).To(ContainSubstring("CREATE ACCOUNT ACCT"))
+		Expect(sql).To(ContainSubstring("ADMIN_NAME = 'admin'"))
+		Expect(sql).To(ContainSubstring("ADMIN_PASSWORD = 'secret'"))
+		Expect(sql).To(ContainSubstring("COMMENT = 'it''s mine'"))
+	})
+
+	It("should load a template from a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "create-account.tmpl")
+		Expect(os.WriteFile(path, []byte(
+			"CREATE ACCOUNT {{ .AccountName }} COMMENT = {{ quote .Comment }} -- {{ .Spec.DesiredAccountName }}"), 0o600)).To(Succeed())
+
+		tmpl, err := LoadCreateAccountTemplate(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		sql, err := (&SnowflakeAccountReconciler{CreateAccountTemplate: tmpl}).renderCreateAccountSQL(data())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sql).To(Equal("CREATE ACCOUNT ACCT COMMENT = 'it''s mine' -- acct"))
+	})
+
+	It("should reject invalid templates", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "create-account.tmpl")
+		Expect(os.WriteFile(path, []byte("CREATE ACCOUNT {{ .AccountName"), 0o600)).To(Succeed())
+
+		_, err := LoadCreateAccountTemplate(path)
+		Expect(err).To(HaveOccurred())
+
+		_, err = LoadCreateAccountTemplate(filepath.Join(GinkgoT().TempDir(), "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when the statement is empty or references unknown fields", func() {
+		tmpl, err := parseCreateAccountTemplate("  {{/* nothing */}}\n")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = (&SnowflakeAccountReconciler{CreateAccountTemplate: tmpl}).renderCreateAccountSQL(data())
+		Expect(err).To(MatchError(ContainSubstring("empty statement")))
+
+		tmpl, err = parseCreateAccountTemplate("CREATE ACCOUNT {{ .Unknown }}")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = (&SnowflakeAccountReconciler{CreateAccountTemplate: tmpl}).renderCreateAccountSQL(data())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,580 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/snowflakedb/gosnowflake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("Account tags", func() {
+	It("should reject tag names that are not fully qualified", func() {
+		Expect(validateTags(map[string]string{"cost_center": "eng"})).NotTo(Succeed())
+		Expect(validateTags(map[string]string{"governance.tags.cost_center": "eng"})).To(Succeed())
+	})
+
+	It("should render a sorted and escaped WITH TAG clause", func() {
+		clause := buildTagClause(map[string]string{
+			"governance.tags.owner":       "o'brien",
+			"governance.tags.cost_center": "eng",
+		})
+		Expect(clause).To(Equal(`WITH TAG (governance.tags.cost_center = 'eng', governance.tags.owner = 'o''brien')`))
+		Expect(buildTagClause(nil)).To(BeEmpty())
+	})
+
+	It("should add Kubernetes metadata tags when a tag schema is configured", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-account", Namespace: "team-a", UID: "1234"},
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{
+				Tags: map[string]string{
+					"governance.tags.cost_center": "eng",
+					"governance.tags.K8S_NAME":    "spoofed",
+				},
+			},
+		}
+
+		reconciler := &SnowflakeAccountReconciler{}
+		Expect(reconciler.accountTags(account, "")).To(Equal(account.Spec.Tags))
+
+		reconciler.KubernetesTagSchema = "governance.tags"
+		Expect(reconciler.accountTags(account, "")).To(Equal(map[string]string{
+			"governance.tags.cost_center":   "eng",
+			"governance.tags.K8S_NAMESPACE": "team-a",
+			"governance.tags.K8S_NAME":      "my-account",
+			"governance.tags.K8S_UID":       "1234",
+		}))
+		Expect(account.Spec.Tags).To(HaveKeyWithValue("governance.tags.K8S_NAME", "spoofed"))
+	})
+})
+
+var _ = Describe("Billing entity", func() {
+	It("should only accept allowed billing entities", func() {
+		reconciler := &SnowflakeAccountReconciler{}
+		account := &operatorv1alpha1.SnowflakeAccount{}
+		Expect(reconciler.billingEntity(account)).To(BeEmpty())
+
+		account.Spec.BillingEntity = "finance_emea"
+		_, err := reconciler.billingEntity(account)
+		Expect(err).To(MatchError(ContainSubstring("--allowed-billing-entities")))
+
+		reconciler.AllowedBillingEntities = ParseBillingEntities(" finance_emea,,FINANCE_US ")
+		Expect(reconciler.AllowedBillingEntities).To(Equal([]string{"FINANCE_EMEA", "FINANCE_US"}))
+		Expect(reconciler.billingEntity(account)).To(Equal("FINANCE_EMEA"))
+
+		account.Spec.BillingEntity = "FINANCE_EU"
+		_, err = reconciler.billingEntity(account)
+		Expect(err).To(MatchError(ContainSubstring("not one of the allowed billing entities")))
+	})
+
+	It("should tag accounts with their billing entity when a tag is configured", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{
+				Tags: map[string]string{"governance.tags.billing_entity": "spoofed"},
+			},
+		}
+
+		reconciler := &SnowflakeAccountReconciler{}
+		Expect(reconciler.accountTags(account, "FINANCE_EMEA")).To(Equal(account.Spec.Tags))
+
+		reconciler.BillingEntityTag = "governance.tags.billing_entity"
+		Expect(reconciler.accountTags(account, "")).To(Equal(account.Spec.Tags))
+		Expect(reconciler.accountTags(account, "FINANCE_EMEA")).To(Equal(map[string]string{
+			"governance.tags.billing_entity": "FINANCE_EMEA",
+		}))
+
+		Expect(ValidateBillingEntityTag("")).To(Succeed())
+		Expect(ValidateBillingEntityTag("governance.tags.billing_entity")).To(Succeed())
+		Expect(ValidateBillingEntityTag("billing_entity")).NotTo(Succeed())
+	})
+
+	It("should only render CONSUMPTION_BILLING_ENTITY for accounts with a billing entity", func() {
+		Expect(buildBillingEntityClause("")).To(BeEmpty())
+		Expect(buildBillingEntityClause("FINANCE_EMEA")).To(Equal("CONSUMPTION_BILLING_ENTITY = FINANCE_EMEA"))
+	})
+})
+
+var _ = Describe("Drop grace period", func() {
+	It("should validate the operator's bounds", func() {
+		Expect(ValidateGracePeriodBounds(0, 0)).To(Succeed())
+		Expect(ValidateGracePeriodBounds(7, 30)).To(Succeed())
+		Expect(ValidateGracePeriodBounds(1, 0)).NotTo(Succeed())
+		Expect(ValidateGracePeriodBounds(0, 91)).NotTo(Succeed())
+		Expect(ValidateGracePeriodBounds(30, 7)).NotTo(Succeed())
+	})
+
+	It("should clamp the grace period to the bounds", func() {
+		reconciler := &SnowflakeAccountReconciler{}
+		account := &operatorv1alpha1.SnowflakeAccount{}
+		Expect(reconciler.dropGracePeriod(context.Background(), account)).To(Equal(dropGracePeriodDays))
+
+		reconciler.MinGracePeriodDays = 7
+		Expect(reconciler.dropGracePeriod(context.Background(), account)).To(Equal(7))
+	})
+
+	It("should use the requested grace period within the bounds", func() {
+		reconciler := &SnowflakeAccountReconciler{}
+		account := &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{GracePeriodInDays: 60},
+		}
+		Expect(reconciler.dropGracePeriod(context.Background(), account)).To(Equal(60))
+
+		reconciler.MaxGracePeriodDays = 30
+		Expect(reconciler.dropGracePeriod(context.Background(), account)).To(Equal(30))
+
+		account.Spec.GracePeriodInDays = 5
+		reconciler.MinGracePeriodDays = 7
+		Expect(reconciler.dropGracePeriod(context.Background(), account)).To(Equal(7))
+	})
+})
+
+var _ = Describe("Role identifiers", func() {
+	It("should upper-case unquoted role names and quote every name", func() {
+		Expect(roleIdentifier("orgadmin")).To(Equal("ORGADMIN"))
+		Expect(roleIdentifier("Account Creators")).To(Equal("Account Creators"))
+		Expect(quoteIdentifier("ORGADMIN")).To(Equal(`"ORGADMIN"`))
+		Expect(quoteIdentifier(`My "Role"`)).To(Equal(`"My ""Role"""`))
+	})
+
+	It("should quote user names and reject admin names that are not identifiers", func() {
+		Expect(userIdentifier("admin_abc")).To(Equal(`"ADMIN_ABC"`))
+		Expect(userIdentifier(`x" SET PASSWORD = 'p`)).To(Equal(`"x"" SET PASSWORD = 'p"`))
+
+		account := &operatorv1alpha1.SnowflakeAccount{Spec: operatorv1alpha1.SnowflakeAccountSpec{
+			ExistingAccountName: "LEGACY",
+			ExistingAdminName:   "ADMIN; DROP USER OTHER",
+		}}
+		Expect(validateAdoption(account)).To(MatchError(ContainSubstring("existingAdminName")))
+		Expect(validateUnmanagedAccount(account)).To(MatchError(ContainSubstring("existingAdminName")))
+		account.Spec.ExistingAdminName = "LEGACY_ADMIN"
+		Expect(validateAdoption(account)).To(Succeed())
+	})
+})
+
+var _ = Describe("Account comment", func() {
+	withComment := func(comment string, truncate bool) *operatorv1alpha1.SnowflakeAccount {
+		return &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{Comment: comment, TruncateComment: truncate},
+		}
+	}
+
+	It("should default the comment", func() {
+		Expect(accountComment(withComment("", false))).To(Equal(defaultAccountComment))
+	})
+
+	It("should accept a comment of exactly the maximum length", func() {
+		comment := strings.Repeat("é", maxCommentLength)
+		Expect(accountComment(withComment(comment, false))).To(Equal(comment))
+	})
+
+	It("should reject a comment one character too long unless truncation is requested", func() {
+		comment := strings.Repeat("é", maxCommentLength+1)
+		_, err := accountComment(withComment(comment, false))
+		Expect(err).To(MatchError(ContainSubstring("at most 256")))
+
+		Expect(accountComment(withComment(comment, true))).To(Equal(strings.Repeat("é", maxCommentLength)))
+	})
+})
+
+var _ = Describe("Credentials secret metadata", func() {
+	It("should merge user labels without overriding managed labels", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-account"},
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{
+				SecretLabels: map[string]string{
+					"reloader.stakater.com/match":  "true",
+					"app.kubernetes.io/managed-by": "someone-else",
+					"app.kubernetes.io/instance":   "other-account",
+				},
+			},
+		}
+
+		Expect((&SnowflakeAccountReconciler{}).credentialsSecretLabels(account)).To(Equal(map[string]string{
+			"reloader.stakater.com/match":  "true",
+			"app.kubernetes.io/name":       "snowflake-account",
+			"app.kubernetes.io/managed-by": "snowflake-operator",
+			"app.kubernetes.io/instance":   "my-account",
+		}))
+	})
+
+	It("should label secrets in another namespace with the owner namespace", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-account", Namespace: "team-a"},
+			Spec:       operatorv1alpha1.SnowflakeAccountSpec{SecretNamespace: "snowflake-creds"},
+		}
+
+		Expect(credentialsSecretNamespace(account)).To(Equal("snowflake-creds"))
+		Expect((&SnowflakeAccountReconciler{}).credentialsSecretLabels(account)).To(HaveKeyWithValue(ownerNamespaceLabel, "team-a"))
+
+		account.Spec.SecretNamespace = ""
+		Expect(credentialsSecretNamespace(account)).To(Equal("team-a"))
+		Expect((&SnowflakeAccountReconciler{}).credentialsSecretLabels(account)).NotTo(HaveKey(ownerNamespaceLabel))
+	})
+
+	It("should only allow secret namespaces the operator is configured with", func() {
+		reconciler := &SnowflakeAccountReconciler{}
+		account := &operatorv1alpha1.SnowflakeAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-account", Namespace: "team-a"},
+		}
+		Expect(reconciler.validateSecretNamespace(account)).To(Succeed())
+
+		account.Spec.SecretNamespace = "team-a"
+		Expect(reconciler.validateSecretNamespace(account)).To(Succeed())
+
+		account.Spec.SecretNamespace = "snowflake-creds"
+		Expect(reconciler.validateSecretNamespace(account)).To(MatchError(ContainSubstring("--allowed-secret-namespaces")))
+
+		reconciler.AllowedSecretNamespaces = []string{"other"}
+		Expect(reconciler.validateSecretNamespace(account)).To(MatchError(ContainSubstring("not one of the allowed secret namespaces other")))
+
+		var err error
+		reconciler.AllowedSecretNamespaces, err = ParseSecretNamespaces(" snowflake-creds ,,other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.AllowedSecretNamespaces).To(Equal([]string{"snowflake-creds", "other"}))
+		Expect(reconciler.validateSecretNamespace(account)).To(Succeed())
+
+		_, err = ParseSecretNamespaces("snowflake-creds,Not_A_Namespace")
+		Expect(err).To(MatchError(ContainSubstring(`invalid namespace "Not_A_Namespace"`)))
+	})
+
+	It("should parse and validate discovery label keys", func() {
+		Expect(ParseDiscoveryLabelKeys(" tenant , example.com/team,")).To(Equal([]string{"tenant", "example.com/team"}))
+		Expect(ParseDiscoveryLabelKeys("")).To(BeEmpty())
+
+		_, err := ParseDiscoveryLabelKeys("tenant,not a key")
+		Expect(err).To(MatchError(ContainSubstring(`invalid label key "not a key"`)))
+	})
+
+	It("should tell apart the secrets of resources sharing a name by the discovery labels", func() {
+		ctx := context.Background()
+		r := &SnowflakeAccountReconciler{Client: k8sClient, DiscoveryLabelKeys: []string{"tenant"}}
+		accountFor := func(tenant string) *operatorv1alpha1.SnowflakeAccount {
+			return &operatorv1alpha1.SnowflakeAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "shared-name",
+					Namespace: "default",
+					Labels:    map[string]string{"tenant": tenant},
+					UID:       types.UID("uid-" + tenant),
+				},
+			}
+		}
+		secretFor := func(account *operatorv1alpha1.SnowflakeAccount, accountName string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "shared-name-" + account.Labels["tenant"],
+					Namespace:       "default",
+					Labels:          r.credentialsSecretLabels(account),
+					OwnerReferences: credentialsOwnerReferences(account),
+				},
+				Data: map[string][]byte{"accountName": []byte(accountName)},
+			}
+		}
+		accountA, accountB := accountFor("a"), accountFor("b")
+		Expect(r.credentialsSecretLabels(accountA)).To(HaveKeyWithValue("tenant", "a"))
+
+		secretB := secretFor(accountB, "ACCOUNT_B")
+		Expect(k8sClient.Create(ctx, secretB)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, secretB)
+
+		Expect(r.getCredentialsSecret(ctx, accountA)).To(BeNil())
+
+		By("ignoring a secret with the labels of the resource that it does not own")
+		foreign := secretFor(accountA, "FOREIGN")
+		foreign.Name = "shared-name-foreign"
+		foreign.OwnerReferences = nil
+		Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, foreign)
+		Expect(r.getCredentialsSecret(ctx, accountA)).To(BeNil())
+
+		By("ignoring a secret in another namespace without the UID of the resource")
+		accountA.Spec.SecretNamespace = "creds"
+		Expect(r.credentialsSecretLabels(accountA)).To(HaveKeyWithValue(ownerUIDLabel, "uid-a"))
+		Expect(ownsCredentialsSecret(accountA, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "creds",
+			Labels:    map[string]string{ownerUIDLabel: "uid-b"},
+		}})).To(BeFalse())
+		Expect(ownsCredentialsSecret(accountA, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "creds",
+			Labels:    map[string]string{ownerUIDLabel: "uid-a"},
+		}})).To(BeTrue())
+		accountA.Spec.SecretNamespace = ""
+
+		secretA := secretFor(accountA, "ACCOUNT_A")
+		Expect(k8sClient.Create(ctx, secretA)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, secretA)
+
+		Expect(r.getAccountNameFromSecret(ctx, accountA)).To(Equal("ACCOUNT_A"))
+		Expect(r.getAccountNameFromSecret(ctx, accountB)).To(Equal("ACCOUNT_B"))
+
+		By("finding the recorded secret by name once the labels of the resource change")
+		recordCredentialsSecret(accountA, secretA)
+		accountA.Labels["tenant"] = "c"
+		Expect(r.getAccountNameFromSecret(ctx, accountA)).To(Equal("ACCOUNT_A"))
+	})
+
+	It("should copy user annotations", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{
+				SecretAnnotations: map[string]string{"reloader.stakater.com/auto": "true"},
+			},
+		}
+
+		Expect(credentialsSecretAnnotations(account)).To(Equal(map[string]string{"reloader.stakater.com/auto": "true"}))
+		Expect(credentialsSecretAnnotations(&operatorv1alpha1.SnowflakeAccount{})).To(BeNil())
+	})
+
+	It("should add the standard keys for basic-auth secrets", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{SecretType: operatorv1alpha1.CredentialsSecretTypeBasicAuth},
+		}
+		data := map[string][]byte{"adminName": []byte("admin"), "adminPassword": []byte("secret")}
+
+		secretType, err := credentialsSecretType(account, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secretType).To(Equal(corev1.SecretTypeBasicAuth))
+		Expect(data).To(HaveKeyWithValue("username", []byte("admin")))
+		Expect(data).To(HaveKeyWithValue("password", []byte("secret")))
+		Expect(data).To(HaveKey("adminName"))
+
+		_, err = credentialsSecretType(account, map[string][]byte{"adminName": []byte("admin")})
+		Expect(err).To(MatchError(ContainSubstring("requires the password key")))
+
+		secretType, err = credentialsSecretType(&operatorv1alpha1.SnowflakeAccount{}, data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secretType).To(Equal(corev1.SecretTypeOpaque))
+	})
+
+	It("should reject basic-auth secrets without a generated password", func() {
+		account := &operatorv1alpha1.SnowflakeAccount{
+			Spec: operatorv1alpha1.SnowflakeAccountSpec{SecretType: operatorv1alpha1.CredentialsSecretTypeBasicAuth},
+		}
+		Expect(validateSecretType(account)).To(Succeed())
+
+		account.Spec.AdminPasswordSecretRef = &corev1.SecretKeySelector{Key: "password"}
+		Expect(validateSecretType(account)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Admin public key", func() {
+	var encodedKey string
+
+	BeforeEach(func() {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+		encodedKey = base64.StdEncoding.EncodeToString(der)
+	})
+
+	It("should strip PEM armor and line breaks", func() {
+		pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustDecodeBase64(encodedKey)}))
+
+		normalized, err := normalizeRSAPublicKey(pemKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(normalized).To(Equal(encodedKey))
+
+		normalized, err = normalizeRSAPublicKey(encodedKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(normalized).To(Equal(encodedKey))
+	})
+
+	It("should reject keys that are not RSA public keys", func() {
+		_, err := normalizeRSAPublicKey("not-a-key")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only set the credentials that were given", func() {
+		Expect(buildAdminAuthClause("", encodedKey)).To(Equal("ADMIN_RSA_PUBLIC_KEY = '" + encodedKey + "'"))
+		Expect(buildAdminAuthClause("Secr3tPass", "")).To(Equal("ADMIN_PASSWORD = 'Secr3tPass'\n            MUST_CHANGE_PASSWORD = TRUE"))
+		Expect(buildAdminAuthClause("Secr3tPass", encodedKey)).To(ContainSubstring("ADMIN_RSA_PUBLIC_KEY"))
+	})
+})
+
+func mustDecodeBase64(value string) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	Expect(err).NotTo(HaveOccurred())
+	return decoded
+}
+
+var _ = Describe("Connection DSN", func() {
+	parse := func(creds *snowflakeCredentials) *gosnowflake.Config {
+		dsn, err := buildDSN(creds)
+		Expect(err).NotTo(HaveOccurred())
+		cfg, err := gosnowflake.ParseDSN(dsn)
+		Expect(err).NotTo(HaveOccurred())
+		return cfg
+	}
+
+	It("should keep special characters of the password intact", func() {
+		const password = `p@ss/w:rd?&=%#'1`
+		cfg := parse(&snowflakeCredentials{username: "org_admin", password: password, account: "myorg-admin", role: "ORGADMIN"})
+		Expect(cfg.User).To(Equal("org_admin"))
+		Expect(cfg.Password).To(Equal(password))
+		Expect(cfg.Account).To(Equal("myorg-admin"))
+		Expect(cfg.Role).To(Equal("ORGADMIN"))
+		Expect(cfg.Host).To(Equal("myorg-admin.snowflakecomputing.com"))
+	})
+
+	It("should derive the host from a separate region", func() {
+		cfg := parse(&snowflakeCredentials{username: "u", password: "p", account: "xy12345", region: "us-east-1.privatelink", role: "ORGADMIN"})
+		Expect(cfg.Account).To(Equal("xy12345"))
+		Expect(cfg.Region).To(Equal("us-east-1.privatelink"))
+		Expect(cfg.Host).To(Equal("xy12345.us-east-1.privatelink.snowflakecomputing.com"))
+	})
+
+	It("should connect to a custom host and port", func() {
+		cfg := parse(&snowflakeCredentials{username: "u", password: "p", account: "xy12345", region: "eu-central-1",
+			host: "xy12345.eu-central-1.privatelink.snowflakecomputing.com", role: "ORGADMIN"})
+		Expect(cfg.Account).To(Equal("xy12345"))
+		Expect(cfg.Host).To(Equal("xy12345.eu-central-1.privatelink.snowflakecomputing.com"))
+		Expect(cfg.Port).To(Equal(443))
+
+		cfg = parse(&snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", host: "snowflake.internal:8443", role: "ORGADMIN"})
+		Expect(cfg.Host).To(Equal("snowflake.internal"))
+		Expect(cfg.Port).To(Equal(8443))
+	})
+
+	It("should authenticate with an OAuth token instead of a password", func() {
+		cfg := parse(&snowflakeCredentials{username: "org_admin", password: "ignored", account: "myorg-admin", role: "ORGADMIN", oauthToken: "tok/en+1"})
+		Expect(cfg.Authenticator).To(Equal(gosnowflake.AuthTypeOAuth))
+		Expect(cfg.Token).To(Equal("tok/en+1"))
+		Expect(cfg.Password).To(BeEmpty())
+	})
+
+	It("should reject conflicting or incomplete settings", func() {
+		_, err := buildDSN(&snowflakeCredentials{username: "u", password: "p", account: "xy12345.eu-central-1", region: "us-east-1", role: "ORGADMIN"})
+		Expect(err).To(HaveOccurred())
+		_, err = buildDSN(&snowflakeCredentials{username: "u", account: "myorg-admin", role: "ORGADMIN"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should merge extra parameters over gosnowflake's defaults", func() {
+		params, err := parseDSNParams(" application=speck, loginTimeout=30,client_session_keep_alive=true,ocspFailOpen=false")
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg := parse(&snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", role: "ORGADMIN", dsnParams: params})
+		Expect(cfg.Application).To(Equal("speck"))
+		Expect(cfg.LoginTimeout).To(Equal(30 * time.Second))
+		Expect(cfg.OCSPFailOpen).To(Equal(gosnowflake.OCSPFailOpenFalse))
+		Expect(cfg.Params).To(HaveKey("client_session_keep_alive"))
+		Expect(*cfg.Params["client_session_keep_alive"]).To(Equal("true"))
+		Expect(cfg.Account).To(Equal("myorg-admin"))
+		Expect(cfg.Role).To(Equal("ORGADMIN"))
+	})
+
+	It("should not let extra parameters override the credentials", func() {
+		_, err := parseDSNParams("application=speck,authenticator=snowflake_jwt")
+		Expect(err).To(MatchError(ContainSubstring(`parameter "authenticator" may not be set`)))
+		_, err = parseDSNParams("role=ACCOUNTADMIN")
+		Expect(err).To(HaveOccurred())
+		_, err = parseDSNParams("loginTimeout")
+		Expect(err).To(MatchError(ContainSubstring("key=value")))
+
+		_, err = parseSnowflakeCredentials(func(key string) string {
+			return map[string]string{
+				"SNOWFLAKE_ORG_USERNAME":   "u",
+				"SNOWFLAKE_ORG_PASSWORD":   "p",
+				"SNOWFLAKE_ORG_ACCOUNT":    "myorg-admin",
+				"SNOWFLAKE_ORG_DSN_PARAMS": "account=other",
+			}[key]
+		}, func(key string) string { return key })
+		Expect(err).To(MatchError(ContainSubstring("invalid SNOWFLAKE_ORG_DSN_PARAMS")))
+	})
+
+	It("should reject values gosnowflake cannot parse", func() {
+		params, err := parseDSNParams("loginTimeout=soon")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = buildDSN(&snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", role: "ORGADMIN", dsnParams: params})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should report the application name, unless an extra parameter sets one", func() {
+		creds := &snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", role: "ORGADMIN", application: "speck-operator/v1.2.3"}
+		Expect(parse(creds).Application).To(Equal("speck-operator/v1.2.3"))
+
+		params, err := parseDSNParams("application=custom")
+		Expect(err).NotTo(HaveOccurred())
+		creds.dsnParams = params
+		Expect(parse(creds).Application).To(Equal("custom"))
+	})
+
+	It("should use separate connections for different extra parameters", func() {
+		params, err := parseDSNParams("application=speck")
+		Expect(err).NotTo(HaveOccurred())
+		creds := &snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", role: "ORGADMIN"}
+		withParams := *creds
+		withParams.dsnParams = params
+		Expect(withParams.cacheKey()).NotTo(Equal(creds.cacheKey()))
+	})
+})
+
+var _ = Describe("Connection pool", func() {
+	It("should validate the operator's pool settings", func() {
+		Expect(ValidateConnectionPool(0, 0, 0)).To(Succeed())
+		Expect(ValidateConnectionPool(10, 2, time.Hour)).To(Succeed())
+		Expect(ValidateConnectionPool(0, 5, 0)).To(Succeed())
+		Expect(ValidateConnectionPool(-1, 0, 0)).NotTo(Succeed())
+		Expect(ValidateConnectionPool(0, 0, -time.Second)).NotTo(Succeed())
+		Expect(ValidateConnectionPool(2, 5, 0)).NotTo(Succeed())
+	})
+
+	It("should bound newly opened pools", func() {
+		executor := (&SnowflakeAccountReconciler{SnowflakeMaxOpenConns: 4, SnowflakeMaxIdleConns: 1}).snowflake().(*gosnowflakeExecutor)
+		db, release, err := executor.connect(context.Background(), &snowflakeCredentials{username: "u", password: "p", account: "xy12345", role: "ORGADMIN"})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+		release()
+		Expect(db.Stats().MaxOpenConnections).To(Equal(4))
+
+		unbounded, err := (&snowflakeConnectionCache{}).get(context.Background(),
+			&snowflakeCredentials{username: "u", password: "p", account: "xy12345", role: "ORGADMIN"}, connectionPoolSettings{})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(unbounded.Close)
+		Expect(unbounded.Stats().MaxOpenConnections).To(BeZero())
+	})
+
+	It("should not cache connections to child accounts", func() {
+		reconciler := &SnowflakeAccountReconciler{}
+		executor := reconciler.snowflake().(*gosnowflakeExecutor)
+		orgCreds := &snowflakeCredentials{username: "u", password: "p", account: "myorg-admin", role: "ORGADMIN"}
+		creds, err := childAccountCredentials(orgCreds, "CHILD", "ADMIN", "secret")
+		Expect(err).NotTo(HaveOccurred())
+
+		db, release, err := executor.connect(context.Background(), creds)
+		Expect(err).NotTo(HaveOccurred())
+		release()
+		Expect(db.PingContext(context.Background())).To(MatchError(ContainSubstring("database is closed")))
+		Expect(reconciler.connections.conns).To(BeEmpty())
+	})
+
+	It("should close pools that went unused and pools replaced by reloaded credentials", func() {
+		cache := &snowflakeConnectionCache{}
+		creds := &snowflakeCredentials{username: "u", password: "p", account: "xy12345", role: "ORGADMIN"}
+		other := &snowflakeCredentials{username: "u", password: "p", account: "zz99999", role: "ORGADMIN"}
+
+		idle, err := cache.get(context.Background(), other, connectionPoolSettings{})
+		Expect(err).NotTo(HaveOccurred())
+		cache.conns[other.cacheKey()].lastUsed = time.Now().Add(-connectionIdleTimeout - time.Minute)
+
+		db, err := cache.get(context.Background(), creds, connectionPoolSettings{})
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(db.Close)
+		Expect(cache.conns).To(HaveLen(1))
+		Expect(cache.conns).To(HaveKey(creds.cacheKey()))
+		Expect(idle.PingContext(context.Background())).To(MatchError(ContainSubstring("database is closed")))
+
+		Expect(cache.get(context.Background(), creds, connectionPoolSettings{})).To(BeIdenticalTo(db))
+	})
+})
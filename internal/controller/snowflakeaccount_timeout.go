@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// retryProvisioningAnnotation restarts provisioning after a ProvisioningTimeout whenever its value changes
+	retryProvisioningAnnotation = "speck.dataverse.redhat.com/retry-provisioning"
+)
+
+// maxProvisioningDuration returns how long provisioning is retried; zero means forever
+func maxProvisioningDuration(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) time.Duration {
+	if snowflakeAccount.Spec.MaxProvisioningDuration == nil {
+		return operatorv1alpha1.DefaultMaxProvisioningDuration
+	}
+	return snowflakeAccount.Spec.MaxProvisioningDuration.Duration
+}
+
+// restartRequestedProvisioning restarts provisioning when the retry-provisioning annotation changes: the
+// clock of Spec.MaxProvisioningDuration and the failure backoff are reset, and a ProvisioningTimeout is
+// cleared
+func (r *SnowflakeAccountReconciler) restartRequestedProvisioning(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	retry := snowflakeAccount.Annotations[retryProvisioningAnnotation]
+	if retry == snowflakeAccount.Status.ProvisioningRetry {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Restarting provisioning as requested by annotation", "annotation", retryProvisioningAnnotation, "value", retry)
+	snowflakeAccount.Status.ProvisioningRetry = retry
+	snowflakeAccount.Status.ProvisioningStartTime = nil
+	clearFailures(snowflakeAccount)
+	if meta.IsStatusConditionTrue(snowflakeAccount.Status.Conditions, conditionFailed) {
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhasePending
+		snowflakeAccount.Status.Message = "Provisioning restarted"
+		meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+			Type:               conditionFailed,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ProvisioningRestarted",
+			Message:            fmt.Sprintf("Provisioning was restarted by the %s annotation", retryProvisioningAnnotation),
+			ObservedGeneration: snowflakeAccount.Generation,
+		})
+	}
+	return r.updateStatus(ctx, snowflakeAccount)
+}
+
+// checkProvisioningTimeout reports whether creating the account must stop because it has not succeeded
+// within Spec.MaxProvisioningDuration. The clock starts with the first attempt to create the account, so
+// waiting for quota or privileges does not count. Once the timeout is reached the Failed condition is set
+// and an event is emitted; creating is then not attempted until the retry-provisioning annotation changes.
+func (r *SnowflakeAccountReconciler) checkProvisioningTimeout(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	log := logf.FromContext(ctx)
+	now := r.Clock.Now()
+
+	if snowflakeAccount.Status.ProvisioningStartTime == nil {
+		start := metav1.NewTime(now)
+		snowflakeAccount.Status.ProvisioningStartTime = &start
+		return false, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	// Provisioning has already timed out and waits for the annotation to change
+	if condition := meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionFailed); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.Reason == "ProvisioningTimeout" {
+		return true, nil
+	}
+
+	maxDuration := maxProvisioningDuration(snowflakeAccount)
+	elapsed := now.Sub(snowflakeAccount.Status.ProvisioningStartTime.Time)
+	if maxDuration <= 0 || elapsed < maxDuration {
+		return false, nil
+	}
+
+	log.Error(nil, "Provisioning timed out, giving up", "elapsed", elapsed, "maxProvisioningDuration", maxDuration)
+	message := fmt.Sprintf("The account was not provisioned within %s; change the %s annotation to try again",
+		maxDuration, retryProvisioningAnnotation)
+	if lastMessage := snowflakeAccount.Status.Message; lastMessage != "" {
+		message = fmt.Sprintf("%s. Last status: %s", message, lastMessage)
+	}
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "ProvisioningTimeout", "%s", message)
+
+	snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+	snowflakeAccount.Status.Message = message
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionFailed,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ProvisioningTimeout",
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	return true, r.updateStatus(ctx, snowflakeAccount)
+}
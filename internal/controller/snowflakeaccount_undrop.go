@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// undropAccountAnnotation requests that a previously dropped account be restored instead of creating a new one
+	undropAccountAnnotation = "speck.dataverse.redhat.com/undrop-account"
+)
+
+// reconcileUndrop restores a dropped Snowflake account with UNDROP ACCOUNT and rebuilds the status and secret
+func (r *SnowflakeAccountReconciler) reconcileUndrop(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, accountName string) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	accountName = strings.ToUpper(accountName)
+	if !accountNamePattern.MatchString(accountName) {
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Cannot undrop account: %q is not a valid Snowflake identifier", accountName)
+		return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	if err := r.setPhase(ctx, snowflakeAccount, operatorv1alpha1.PhaseProvisioning); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Restoring dropped Snowflake account", "accountName", accountName)
+
+	details, err := r.undropSnowflakeAccount(ctx, snowflakeAccount, accountName)
+	if err != nil {
+		if r.recordInterruption(ctx, snowflakeAccount, "Restoring the Snowflake account", err) {
+			return ctrl.Result{}, err
+		}
+		if isUndropExpiredError(err) {
+			// The grace period has elapsed, so retrying will never succeed
+			log.Info("Snowflake account can no longer be restored", "accountName", accountName, "reason", err.Error())
+			snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+			snowflakeAccount.Status.Message = fmt.Sprintf("Account %s can no longer be restored (grace period expired or account not found)", accountName)
+			return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+		}
+
+		log.Error(err, "Failed to restore Snowflake account")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Failed to restore account: %v", err)
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Recreate the credentials secret; the original admin password cannot be recovered
+	if err := r.ensureCredentialsSecret(ctx, snowflakeAccount, details); err != nil {
+		log.Error(err, "Failed to create credentials secret")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Account restored but failed to store credentials: %v", err)
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatusAfterCreation(ctx, snowflakeAccount, details); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account %s restored with UNDROP ACCOUNT", accountName)
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status after account restore")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Successfully restored Snowflake account", "accountName", accountName)
+	return r.expiryRequeue(ctx, snowflakeAccount), nil
+}
+
+// undropSnowflakeAccount runs UNDROP ACCOUNT and returns the restored account's details from SHOW ACCOUNTS
+func (r *SnowflakeAccountReconciler) undropSnowflakeAccount(ctx context.Context, account *operatorv1alpha1.SnowflakeAccount, accountName string) (*accountDetails, error) {
+	log := logf.FromContext(ctx)
+
+	// Get Snowflake organization credentials for the account
+	creds, err := r.getSnowflakeCredentials(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set a timeout for the operation
+	undropCtx, cancel := context.WithTimeout(ctx, 120*time.Second)
+	defer cancel()
+
+	// Skip the UNDROP if a previous reconcile already restored the account
+	rows, err := r.snowflake().ShowAccounts(undropCtx, creds, accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		undropAccountSQL := fmt.Sprintf(`UNDROP ACCOUNT %s`, accountName)
+
+		log.Info("Executing UNDROP ACCOUNT", "sql", undropAccountSQL)
+
+		if err := r.snowflake().Exec(undropCtx, creds, undropAccountSQL); err != nil {
+			return nil, fmt.Errorf("failed to execute UNDROP ACCOUNT: %w", classifySnowflakeError(err))
+		}
+
+		rows, err = r.snowflake().ShowAccounts(undropCtx, creds, accountName)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("account %s does not exist after UNDROP ACCOUNT", accountName)
+		}
+	}
+
+	return accountDetailsFromRow(accountName, rows[0], creds), nil
+}
+
+// isUndropExpiredError reports whether an UNDROP failure means the account can no longer be restored
+func isUndropExpiredError(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "does not exist") ||
+		strings.Contains(message, "grace period") ||
+		strings.Contains(message, "cannot be undropped")
+}
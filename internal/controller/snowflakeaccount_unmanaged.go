@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// managesAccount reports whether the operator manages the Snowflake account itself, which it does
+// unless Spec.ManageAccount is false
+func managesAccount(account *operatorv1alpha1.SnowflakeAccount) bool {
+	return account.Spec.ManageAccount == nil || *account.Spec.ManageAccount
+}
+
+// reconcileUnmanagedAccount maintains the credentials secret and status of an account created outside the
+// operator, without running any SQL against Snowflake. An expired duration is reported, not acted on.
+func (r *SnowflakeAccountReconciler) reconcileUnmanagedAccount(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Setting manageAccount afterwards must not hand an account the operator did not create over to it
+	if managesAccount(snowflakeAccount) {
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account %s was registered with manageAccount false "+
+			"and cannot be managed by the operator; set manageAccount back to false", snowflakeAccount.Status.AccountName)
+		return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	if err := validateUnmanagedAccount(snowflakeAccount); err != nil {
+		// Retrying cannot help until the spec is fixed, which triggers a new reconcile
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Cannot manage credentials of account: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	details, err := r.unmanagedAccountDetails(ctx, snowflakeAccount)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Recorded with the status updates below, so the account is never dropped whatever the spec says later
+	snowflakeAccount.Status.Unmanaged = true
+
+	// Applying the secret on every reconcile recreates it if it was deleted and keeps it in line with the spec
+	if err := r.ensureCredentialsSecret(ctx, snowflakeAccount, details); err != nil {
+		log.Error(err, "Failed to create credentials secret")
+		snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseFailed
+		snowflakeAccount.Status.Message = fmt.Sprintf("Failed to store credentials: %v", err)
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !snowflakeAccount.Status.AccountCreated {
+		// The duration counts from the time the operator took over the credentials
+		if err := r.updateStatusAfterCreation(ctx, snowflakeAccount, details); err != nil {
+			return ctrl.Result{}, err
+		}
+		snowflakeAccount.Status.Message = fmt.Sprintf("Credentials of existing Snowflake account %s stored; "+
+			"the account is not managed by the operator", details.accountName)
+		log.Info("Stored credentials of unmanaged Snowflake account", "accountName", details.accountName)
+	}
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status of unmanaged account")
+		return ctrl.Result{}, err
+	}
+
+	// Surface invalid durations instead of acting on them
+	if _, err := r.reconcileDurationCondition(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update duration condition")
+		return ctrl.Result{}, err
+	}
+
+	expiresAt := snowflakeAccount.Status.ExpiresAt
+	expired, requeueAfter := r.checkDuration(ctx, snowflakeAccount)
+	if !expiresAt.Equal(snowflakeAccount.Status.ExpiresAt) {
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			log.Error(err, "Failed to update the expiry of the account")
+			return ctrl.Result{}, err
+		}
+	}
+	if expired {
+		return ctrl.Result{}, r.reportUnmanagedExpiry(ctx, snowflakeAccount)
+	}
+	if requeueAfter > 0 {
+		log.Info("Requeuing to check duration", "after", requeueAfter)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// validateUnmanagedAccount checks that the spec names the account and asks for nothing that needs SQL
+func validateUnmanagedAccount(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	spec := snowflakeAccount.Spec
+	if !accountNamePattern.MatchString(spec.ExistingAccountName) {
+		return fmt.Errorf("existingAccountName %q is not a valid Snowflake identifier", spec.ExistingAccountName)
+	}
+	if spec.ExistingAdminName != "" && !unquotedIdentifierPattern.MatchString(spec.ExistingAdminName) {
+		return fmt.Errorf("existingAdminName %q is not a valid Snowflake identifier", spec.ExistingAdminName)
+	}
+	if spec.ResetAdminPassword {
+		return fmt.Errorf("resetAdminPassword requires manageAccount")
+	}
+	return nil
+}
+
+// unmanagedAccountDetails returns the details of an unmanaged account from its spec. The organization
+// credentials are only read to build the account URL.
+func (r *SnowflakeAccountReconciler) unmanagedAccountDetails(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (*accountDetails, error) {
+	creds, err := r.getSnowflakeCredentials(ctx, snowflakeAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	accountName := strings.ToUpper(snowflakeAccount.Spec.ExistingAccountName)
+	return &accountDetails{
+		accountName: accountName,
+		adminName:   snowflakeAccount.Spec.ExistingAdminName,
+		accountURL:  buildAccountURL(accountName, creds),
+		// A referenced password stays in its own secret and is read from there when needed
+		passwordFromSecretRef: snowflakeAccount.Spec.AdminPasswordSecretRef != nil,
+		orgAccount:            creds.account,
+		orgRole:               creds.role,
+	}, nil
+}
+
+// reportUnmanagedExpiry sets the Expired condition of an unmanaged account whose duration has expired,
+// emitting a warning event the first time. The account must be dropped, and the resource deleted, by its
+// owners.
+func (r *SnowflakeAccountReconciler) reportUnmanagedExpiry(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	message := fmt.Sprintf("Duration %s has expired; the Snowflake account %s is not managed by the operator "+
+		"and must be dropped manually", snowflakeAccount.Spec.Duration, snowflakeAccount.Status.AccountName)
+	if !meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionExpired,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DurationExpired",
+		Message:            message,
+		ObservedGeneration: snowflakeAccount.Generation,
+	}) {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Duration expired, but the account is not managed by the operator; not deleting",
+		"accountName", snowflakeAccount.Status.AccountName)
+	r.eventf(snowflakeAccount, corev1.EventTypeWarning, "DurationExpired", "%s", message)
+	return r.updateStatus(ctx, snowflakeAccount)
+}
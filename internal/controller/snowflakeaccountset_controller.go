@@ -0,0 +1,246 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// conditionSetReady indicates whether every account of a SnowflakeAccountSet is ready
+const conditionSetReady = "Ready"
+
+// SnowflakeAccountSetReconciler reconciles a SnowflakeAccountSet object by creating and deleting the
+// SnowflakeAccounts it owns; each account is then provisioned, and dropped when deleted, by the
+// SnowflakeAccountReconciler
+type SnowflakeAccountSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccountsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccountsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.dataverse.redhat.com,resources=snowflakeaccountsets/finalizers,verbs=update
+
+// Reconcile creates the missing accounts of the set, deletes those beyond Spec.Replicas and reports them
+// in the status. Deleting the set deletes its accounts through their owner references, which drops them
+// in Snowflake.
+func (r *SnowflakeAccountSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+	if err := r.Get(ctx, req.NamespacedName, accountSet); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("SnowflakeAccountSet resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get SnowflakeAccountSet")
+		return ctrl.Result{}, err
+	}
+	if !accountSet.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateAccountSetTemplate(&accountSet.Spec.Template); err != nil {
+		// Retrying cannot help until the spec is fixed, which triggers a new reconcile
+		meta.SetStatusCondition(&accountSet.Status.Conditions, metav1.Condition{
+			Type:               conditionSetReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidTemplate",
+			Message:            err.Error(),
+			ObservedGeneration: accountSet.Generation,
+		})
+		return ctrl.Result{}, r.updateSetStatus(ctx, accountSet)
+	}
+
+	accounts, err := r.setAccounts(ctx, accountSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired := make(map[string]bool, accountSet.Spec.Replicas)
+	for i := range int(accountSet.Spec.Replicas) {
+		desired[setAccountName(accountSet, i)] = true
+	}
+
+	// Delete accounts beyond the requested replicas; their finalizers drop them in Snowflake
+	var members []operatorv1alpha1.SnowflakeAccountSetMember
+	for i := range accounts {
+		account := &accounts[i]
+		if !desired[account.Name] {
+			if account.DeletionTimestamp.IsZero() {
+				log.Info("Deleting account beyond the requested replicas", "name", account.Name)
+				if err := r.Delete(ctx, account); client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, fmt.Errorf("failed to delete SnowflakeAccount %s: %w", account.Name, err)
+				}
+			}
+			continue
+		}
+		delete(desired, account.Name)
+		members = append(members, setMember(account))
+	}
+
+	// Create the missing accounts in index order
+	for i := range int(accountSet.Spec.Replicas) {
+		name := setAccountName(accountSet, i)
+		if !desired[name] {
+			continue
+		}
+		account, err := r.createSetAccount(ctx, accountSet, name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Created account of the set", "name", name)
+		members = append(members, setMember(account))
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	accountSet.Status.Accounts = members
+	accountSet.Status.Replicas = int32(len(members))
+	accountSet.Status.ReadyReplicas = 0
+	for _, member := range members {
+		if member.Phase == operatorv1alpha1.PhaseReady {
+			accountSet.Status.ReadyReplicas++
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionSetReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AllReady",
+		Message:            fmt.Sprintf("All %d accounts are ready", accountSet.Spec.Replicas),
+		ObservedGeneration: accountSet.Generation,
+	}
+	if accountSet.Status.ReadyReplicas < accountSet.Spec.Replicas {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Provisioning"
+		condition.Message = fmt.Sprintf("%d of %d accounts are ready", accountSet.Status.ReadyReplicas, accountSet.Spec.Replicas)
+	}
+	meta.SetStatusCondition(&accountSet.Status.Conditions, condition)
+
+	return ctrl.Result{}, r.updateSetStatus(ctx, accountSet)
+}
+
+// validateAccountSetTemplate checks that the template provisions new accounts, as every account of the set
+// needs its own generated name, that do not expire, as the set would replace an expired account right away
+func validateAccountSetTemplate(template *operatorv1alpha1.SnowflakeAccountSpec) error {
+	if template.DesiredAccountName != "" {
+		return fmt.Errorf("template must not set desiredAccountName; each account of the set gets a generated name")
+	}
+	if template.Duration != "" {
+		return fmt.Errorf("template must not set duration; the set would recreate every expired account")
+	}
+	if template.ExistingAccountName != "" || template.AdoptExisting || !managesAccount(&operatorv1alpha1.SnowflakeAccount{Spec: *template}) {
+		return fmt.Errorf("template must create new accounts; existing accounts cannot be adopted or referenced by a set")
+	}
+	return nil
+}
+
+// setAccountName returns the name of the SnowflakeAccount of the set at index
+func setAccountName(accountSet *operatorv1alpha1.SnowflakeAccountSet, index int) string {
+	return fmt.Sprintf("%s-%d", accountSet.Name, index)
+}
+
+// setAccounts returns the SnowflakeAccounts controlled by the set
+func (r *SnowflakeAccountSetReconciler) setAccounts(ctx context.Context, accountSet *operatorv1alpha1.SnowflakeAccountSet) ([]operatorv1alpha1.SnowflakeAccount, error) {
+	list := &operatorv1alpha1.SnowflakeAccountList{}
+	if err := r.List(ctx, list, client.InNamespace(accountSet.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list SnowflakeAccounts: %w", err)
+	}
+
+	var accounts []operatorv1alpha1.SnowflakeAccount
+	for _, account := range list.Items {
+		if metav1.IsControlledBy(&account, accountSet) {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
+// createSetAccount creates the SnowflakeAccount of the set with the given name from the template
+func (r *SnowflakeAccountSetReconciler) createSetAccount(ctx context.Context, accountSet *operatorv1alpha1.SnowflakeAccountSet, name string) (*operatorv1alpha1.SnowflakeAccount, error) {
+	account := &operatorv1alpha1.SnowflakeAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: accountSet.Namespace,
+		},
+		Spec: *accountSet.Spec.Template.DeepCopy(),
+	}
+	if err := controllerutil.SetControllerReference(accountSet, account, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner of SnowflakeAccount %s: %w", name, err)
+	}
+
+	if err := r.Create(ctx, account); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create SnowflakeAccount %s: %w", name, err)
+		}
+		// A resource of the same name that the set does not control is left alone
+		return nil, fmt.Errorf("SnowflakeAccount %s already exists and is not part of the set", name)
+	}
+	return account, nil
+}
+
+// setMember returns how an account of the set is reported in its status
+func setMember(account *operatorv1alpha1.SnowflakeAccount) operatorv1alpha1.SnowflakeAccountSetMember {
+	return operatorv1alpha1.SnowflakeAccountSetMember{
+		Name:        account.Name,
+		AccountName: account.Status.AccountName,
+		Phase:       account.Status.Phase,
+	}
+}
+
+// updateSetStatus updates the status of the set, retrying on conflicts with the latest version
+func (r *SnowflakeAccountSetReconciler) updateSetStatus(ctx context.Context, accountSet *operatorv1alpha1.SnowflakeAccountSet) error {
+	status := accountSet.Status.DeepCopy()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Status().Update(ctx, accountSet)
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		latest := &operatorv1alpha1.SnowflakeAccountSet{}
+		if getErr := r.Get(ctx, client.ObjectKeyFromObject(accountSet), latest); getErr != nil {
+			return getErr
+		}
+		status.DeepCopyInto(&latest.Status)
+		*accountSet = *latest
+		return err
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnowflakeAccountSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorv1alpha1.SnowflakeAccountSet{}).
+		Owns(&operatorv1alpha1.SnowflakeAccount{}).
+		Named("snowflakeaccountset").
+		Complete(r)
+}
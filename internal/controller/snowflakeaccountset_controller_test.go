@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("SnowflakeAccountSet Controller", func() {
+	const resourceName = "test-set"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+
+	var controllerReconciler *SnowflakeAccountSetReconciler
+
+	BeforeEach(func() {
+		controllerReconciler = &SnowflakeAccountSetReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: operatorv1alpha1.SnowflakeAccountSetSpec{
+				Replicas: 3,
+				Template: operatorv1alpha1.SnowflakeAccountSpec{Comment: "test environment"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, accountSet)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, accountSet); err == nil {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, accountSet))).To(Succeed())
+		}
+
+		// Owned accounts are not garbage collected by the test API server
+		accounts := &operatorv1alpha1.SnowflakeAccountList{}
+		Expect(k8sClient.List(ctx, accounts, client.InNamespace("default"))).To(Succeed())
+		for i := range accounts.Items {
+			if metav1.IsControlledBy(&accounts.Items[i], accountSet) {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &accounts.Items[i]))).To(Succeed())
+			}
+		}
+	})
+
+	setAccountNames := func() []string {
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		accounts, err := controllerReconciler.setAccounts(ctx, accountSet)
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, account := range accounts {
+			names = append(names, account.Name)
+		}
+		return names
+	}
+
+	It("should create an account per replica from the template and remove those beyond the replicas", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(setAccountNames()).To(ConsistOf("test-set-0", "test-set-1", "test-set-2"))
+		account := &operatorv1alpha1.SnowflakeAccount{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-set-1", Namespace: "default"}, account)).To(Succeed())
+		Expect(account.Spec.Comment).To(Equal("test environment"))
+		Expect(metav1.GetControllerOf(account)).NotTo(BeNil())
+		Expect(metav1.GetControllerOf(account).Name).To(Equal(resourceName))
+
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		Expect(accountSet.Status.Replicas).To(Equal(int32(3)))
+		Expect(accountSet.Status.ReadyReplicas).To(BeZero())
+		Expect(accountSet.Status.Accounts).To(HaveLen(3))
+		Expect(accountSet.Status.Accounts[0].Name).To(Equal("test-set-0"))
+		Expect(meta.IsStatusConditionFalse(accountSet.Status.Conditions, conditionSetReady)).To(BeTrue())
+
+		By("scaling down")
+		accountSet.Spec.Replicas = 1
+		Expect(k8sClient.Update(ctx, accountSet)).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(setAccountNames()).To(ConsistOf("test-set-0"))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		Expect(accountSet.Status.Replicas).To(Equal(int32(1)))
+	})
+
+	It("should report the set as ready once every account is ready", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, name := range []string{"test-set-0", "test-set-1", "test-set-2"} {
+			account := &operatorv1alpha1.SnowflakeAccount{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, account)).To(Succeed())
+			account.Status.Phase = operatorv1alpha1.PhaseReady
+			account.Status.AccountName = "SF" + name[len(name)-1:]
+			Expect(k8sClient.Status().Update(ctx, account)).To(Succeed())
+		}
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		Expect(accountSet.Status.ReadyReplicas).To(Equal(int32(3)))
+		Expect(accountSet.Status.Accounts[2].AccountName).To(Equal("SF2"))
+		Expect(meta.IsStatusConditionTrue(accountSet.Status.Conditions, conditionSetReady)).To(BeTrue())
+	})
+
+	It("should not create accounts from a template that names an account", func() {
+		accountSet := &operatorv1alpha1.SnowflakeAccountSet{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		accountSet.Spec.Template.DesiredAccountName = "SHARED"
+		Expect(k8sClient.Update(ctx, accountSet)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(setAccountNames()).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		condition := meta.FindStatusCondition(accountSet.Status.Conditions, conditionSetReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("InvalidTemplate"))
+
+		By("rejecting a template whose accounts expire")
+		accountSet.Spec.Template.DesiredAccountName = ""
+		accountSet.Spec.Template.Duration = "2h"
+		Expect(k8sClient.Update(ctx, accountSet)).To(Succeed())
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(setAccountNames()).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, accountSet)).To(Succeed())
+		condition = meta.FindStatusCondition(accountSet.Status.Conditions, conditionSetReady)
+		Expect(condition.Reason).To(Equal("InvalidTemplate"))
+		Expect(condition.Message).To(ContainSubstring("must not set duration"))
+	})
+})
@@ -3,28 +3,62 @@ package controller
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"fmt"
 	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// updateStatus persists the status of the SnowflakeAccount. On a conflict the latest version of the
+// object is fetched, the status being written is reapplied to it and the update is retried, so a
+// concurrent modification does not lose the status write. snowflakeAccount is updated in place.
+func (r *SnowflakeAccountReconciler) updateStatus(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	status := snowflakeAccount.Status.DeepCopy()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Status().Update(ctx, snowflakeAccount)
+		if !errors.IsConflict(err) {
+			return err
+		}
+
+		latest := &operatorv1alpha1.SnowflakeAccount{}
+		if getErr := r.Get(ctx, client.ObjectKeyFromObject(snowflakeAccount), latest); getErr != nil {
+			return getErr
+		}
+		status.DeepCopyInto(&latest.Status)
+		*snowflakeAccount = *latest
+		return err
+	})
+}
+
 // updateStatusAfterCreation updates the SnowflakeAccount status after successful account creation
 func (r *SnowflakeAccountReconciler) updateStatusAfterCreation(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, details *accountDetails) error {
 	log := logf.FromContext(ctx)
 
 	// Update status fields
+	setAccountDetailsStatus(snowflakeAccount, details)
 	snowflakeAccount.Status.AccountCreated = true
-	snowflakeAccount.Status.AccountURL = fmt.Sprintf("https://%s.snowflakecomputing.com", details.accountName)
+	snowflakeAccount.Status.Phase = operatorv1alpha1.PhaseReady
 	snowflakeAccount.Status.Message = "Snowflake account created successfully"
 	now := metav1.Now()
 	snowflakeAccount.Status.CreationTime = &now
+	snowflakeAccount.Status.ExpiresAt = r.expiresAt(snowflakeAccount)
 
 	// Persist the status update
-	if err := r.Status().Update(ctx, snowflakeAccount); err != nil {
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
 		log.Error(err, "Failed to update status after account creation")
 		return err
 	}
@@ -32,9 +66,202 @@ func (r *SnowflakeAccountReconciler) updateStatusAfterCreation(ctx context.Conte
 	return nil
 }
 
-// generateRandomAccountName generates a random account name (8 uppercase alphanumeric characters)
-func generateRandomAccountName() string {
-	return "SF" + generateRandomString(6, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+// setPhase records the lifecycle phase in the status, persisting it only when it changed
+func (r *SnowflakeAccountReconciler) setPhase(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, phase operatorv1alpha1.Phase) error {
+	if snowflakeAccount.Status.Phase == phase {
+		return nil
+	}
+	snowflakeAccount.Status.Phase = phase
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to update phase", "phase", phase)
+		return err
+	}
+	return nil
+}
+
+// recordSnowflakeAccountName persists the name of a just-created Snowflake account so the
+// finalizer can drop it even if the remaining provisioning steps fail
+func (r *SnowflakeAccountReconciler) recordSnowflakeAccountName(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount, accountName string) error {
+	snowflakeAccount.Status.SnowflakeAccountName = accountName
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to record Snowflake account name", "accountName", accountName)
+		return err
+	}
+	return nil
+}
+
+// setAccountDetailsStatus copies the details of a provisioned account into the status
+func setAccountDetailsStatus(snowflakeAccount *operatorv1alpha1.SnowflakeAccount, details *accountDetails) {
+	snowflakeAccount.Status.AccountName = details.accountName
+	snowflakeAccount.Status.SnowflakeAccountName = details.accountName
+	snowflakeAccount.Status.AccountLocator = details.accountLocator
+	snowflakeAccount.Status.AdminName = details.adminName
+	snowflakeAccount.Status.AccountURL = details.accountURL
+	snowflakeAccount.Status.Tags = details.tags
+	snowflakeAccount.Status.OrgAccount = details.orgAccount
+	snowflakeAccount.Status.OrgRole = details.orgRole
+	snowflakeAccount.Status.Region = details.region
+	snowflakeAccount.Status.BillingEntity = details.billingEntity
+	clearUnavailableRegions(snowflakeAccount)
+	clearInterruption(snowflakeAccount)
+	clearFailures(snowflakeAccount)
+	if details.provisioningDuration > 0 {
+		snowflakeAccount.Status.ProvisioningDuration = &metav1.Duration{Duration: details.provisioningDuration}
+	}
+
+	// The admin is created with MUST_CHANGE_PASSWORD = TRUE, so the stored password is only good
+	// until the first interactive login
+	if details.adminName != "" && details.adminPassword != "" {
+		meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+			Type:   conditionPasswordChangePending,
+			Status: metav1.ConditionTrue,
+			Reason: "MustChangePassword",
+			Message: fmt.Sprintf("Admin user %s must change the initial password on first login; "+
+				"the password in the credentials secret stops working once it has been changed", details.adminName),
+			ObservedGeneration: snowflakeAccount.Generation,
+		})
+	}
+}
+
+// reconcileAccountName renames the Snowflake account when Spec.DesiredAccountName differs from the current name
+func (r *SnowflakeAccountReconciler) reconcileAccountName(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) error {
+	log := logf.FromContext(ctx)
+
+	desiredName := strings.ToUpper(snowflakeAccount.Spec.DesiredAccountName)
+	currentName := snowflakeAccount.Status.AccountName
+	if currentName == "" {
+		// Accounts created before AccountName was tracked only have the URL
+		currentName = extractAccountNameFromURL(snowflakeAccount.Status.AccountURL)
+	}
+
+	// Nothing to do if no name is requested or the names already match
+	if desiredName == "" || strings.EqualFold(desiredName, currentName) {
+		return nil
+	}
+
+	if currentName == "" {
+		log.Info("Current account name is unknown, skipping rename")
+		return nil
+	}
+
+	if !accountNamePattern.MatchString(desiredName) {
+		meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+			Type:               conditionRenaming,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidAccountName",
+			Message:            fmt.Sprintf("Desired account name %q is not a valid Snowflake identifier", desiredName),
+			ObservedGeneration: snowflakeAccount.Generation,
+		})
+		return r.updateStatus(ctx, snowflakeAccount)
+	}
+
+	// Record that the rename is in progress
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionRenaming,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RenameInProgress",
+		Message:            fmt.Sprintf("Renaming account %s to %s", currentName, desiredName),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		return err
+	}
+
+	accountURL, err := r.renameSnowflakeAccount(ctx, snowflakeAccount, currentName, desiredName)
+	if err != nil {
+		meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+			Type:               conditionRenaming,
+			Status:             metav1.ConditionFalse,
+			Reason:             "RenameFailed",
+			Message:            err.Error(),
+			ObservedGeneration: snowflakeAccount.Generation,
+		})
+		if statusErr := r.updateStatus(ctx, snowflakeAccount); statusErr != nil {
+			log.Error(statusErr, "Failed to update status")
+		}
+		return err
+	}
+
+	// Keep the credentials secret in sync with the new name
+	if err := r.updateCredentialsSecretAccount(ctx, snowflakeAccount, desiredName, accountURL); err != nil {
+		return err
+	}
+
+	snowflakeAccount.Status.AccountName = desiredName
+	snowflakeAccount.Status.SnowflakeAccountName = desiredName
+	snowflakeAccount.Status.AccountURL = accountURL
+	snowflakeAccount.Status.Message = fmt.Sprintf("Snowflake account renamed from %s to %s", currentName, desiredName)
+	meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, metav1.Condition{
+		Type:               conditionRenaming,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Renamed",
+		Message:            fmt.Sprintf("Account renamed from %s to %s", currentName, desiredName),
+		ObservedGeneration: snowflakeAccount.Generation,
+	})
+	if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+		log.Error(err, "Failed to update status after account rename")
+		return err
+	}
+
+	return nil
+}
+
+// accountNamePattern matches a valid unquoted Snowflake account identifier
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,254}$`)
+
+// escapeSQLString escapes a value for use inside a single-quoted Snowflake string literal
+func escapeSQLString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// Limits for generated account names; the "SF" prefix keeps them valid identifiers starting with a letter
+const (
+	accountNamePrefix        = "SF"
+	defaultAccountNameLength = 8
+	minAccountNameLength     = 4
+	maxAccountNameLength     = 255
+)
+
+// generateRandomAccountName generates a random account name of the given total length using the
+// given charset after the "SF" prefix. A zero length or empty charset uses the defaults (8, alnum).
+// Names are uppercase, as Snowflake stores them, so the status matches the account's actual name.
+func generateRandomAccountName(length int, charset operatorv1alpha1.AccountNameCharset) (string, error) {
+	if length == 0 {
+		length = defaultAccountNameLength
+	}
+	if length < minAccountNameLength || length > maxAccountNameLength {
+		return "", fmt.Errorf("account name length must be between %d and %d, got %d",
+			minAccountNameLength, maxAccountNameLength, length)
+	}
+
+	var characters string
+	switch charset {
+	case "", operatorv1alpha1.AccountNameCharsetAlnum:
+		characters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	case operatorv1alpha1.AccountNameCharsetAlpha, operatorv1alpha1.AccountNameCharsetUpper:
+		characters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	default:
+		return "", fmt.Errorf("unsupported account name charset %q", charset)
+	}
+
+	return accountNamePrefix + generateRandomString(length-len(accountNamePrefix), characters), nil
+}
+
+// deterministicAccountNameDigits is the number of base36 digits encoding the 128-bit hash prefix of a
+// deterministic account name, enough to make collisions between resources practically impossible
+const deterministicAccountNameDigits = 25
+
+// deterministicAccountName derives an account name from the resource UID: the "SF" prefix followed by
+// the first 128 bits of the UID's SHA-256 hash in uppercase base36, so the same resource always gets
+// the same name and the name is a valid identifier of fixed length
+func deterministicAccountName(uid types.UID) (string, error) {
+	if uid == "" {
+		return "", fmt.Errorf("a deterministic account name requires the resource UID")
+	}
+	sum := sha256.Sum256([]byte(uid))
+	digits := strings.ToUpper(new(big.Int).SetBytes(sum[:16]).Text(36))
+	return accountNamePrefix + strings.Repeat("0", deterministicAccountNameDigits-len(digits)) + digits, nil
 }
 
 // generateRandomUsername generates a random username
@@ -90,26 +317,156 @@ func shuffleString(s string) string {
 	return string(runes)
 }
 
+// orgNamePattern matches a Snowflake organization name
+var orgNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// organizationName returns the name of the organization: SNOWFLAKE_ORG_NAME if configured, otherwise
+// the organization part of an {orgName}-{accountName} account, or "" for an account locator
+func (c *snowflakeCredentials) organizationName() string {
+	if c.orgName != "" {
+		return c.orgName
+	}
+	if org, _, ok := strings.Cut(c.account, "-"); ok && org != "" {
+		return org
+	}
+	return ""
+}
+
+// accountIdentifier returns the identifier used to connect to an account of the organization:
+// {orgName}-{accountName}, or just the account name if the organization name is unknown
+func accountIdentifier(accountName string, creds *snowflakeCredentials) string {
+	if org := creds.organizationName(); org != "" {
+		return fmt.Sprintf("%s-%s", org, accountName)
+	}
+	return accountName
+}
+
+// buildAccountURL returns the URL of an account of the organization, under the organization's
+// domain (which may be a privatelink or other custom host). Underscores in the account name
+// are replaced by hyphens as Snowflake does for hostnames. extractAccountNameFromURL is its inverse.
+func buildAccountURL(accountName string, creds *snowflakeCredentials) string {
+	identifier := accountName
+	if org := creds.organizationName(); org != "" {
+		identifier = fmt.Sprintf("%s-%s", org, strings.ReplaceAll(accountName, "_", "-"))
+	}
+	return fmt.Sprintf("https://%s.%s", identifier, creds.accountDomain())
+}
+
 // extractAccountNameFromURL extracts the account name from a Snowflake account URL
-// Expected format: https://{accountName}.snowflakecomputing.com
-func extractAccountNameFromURL(url string) string {
-	if url == "" {
+// Supported formats (ports and paths are ignored):
+//   - https://{accountName}.snowflakecomputing.com
+//   - https://{accountLocator}.{region}.snowflakecomputing.com
+//   - https://{orgName}-{accountName}.snowflakecomputing.com
+//   - https://{orgName}-{accountName}.privatelink.snowflakecomputing.com
+func extractAccountNameFromURL(accountURL string) string {
+	if accountURL == "" {
+		return ""
+	}
+
+	// Add a scheme if missing so the host is parsed correctly
+	if !strings.Contains(accountURL, "://") {
+		accountURL = "https://" + accountURL
+	}
+
+	parsed, err := url.Parse(accountURL)
+	if err != nil {
 		return ""
 	}
 
-	// Remove https:// prefix if present
-	if len(url) > 8 && url[:8] == "https://" {
-		url = url[8:]
+	// The account identifier is the first label of the host
+	label, _, found := strings.Cut(parsed.Hostname(), ".")
+	if !found || label == "" {
+		return ""
+	}
+
+	// Organization-based URLs use {orgName}-{accountName}, with any underscores
+	// in the account name replaced by hyphens
+	if _, accountName, ok := strings.Cut(label, "-"); ok && accountName != "" {
+		return strings.ReplaceAll(accountName, "-", "_")
+	}
+
+	return label
+}
+
+// validateDuration ensures a duration is positive and does not exceed the configured ceiling
+func (r *SnowflakeAccountReconciler) validateDuration(duration time.Duration) error {
+	if duration <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", duration)
+	}
+	if r.MaxAccountDuration > 0 && duration > r.MaxAccountDuration {
+		return fmt.Errorf("duration %s exceeds the maximum allowed duration of %s", duration, r.MaxAccountDuration)
+	}
+	return nil
+}
+
+// reconcileDurationCondition sets the Error condition when Spec.Duration is invalid and clears it once fixed,
+// and the NoExpiry condition while Spec.Duration is unset
+// Returns whether the duration is valid
+func (r *SnowflakeAccountReconciler) reconcileDurationCondition(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, error) {
+	var durationErr error
+	if duration, err := time.ParseDuration(snowflakeAccount.Spec.Duration); err == nil {
+		durationErr = r.validateDuration(duration)
+	}
+
+	changed := false
+	condition := metav1.Condition{
+		Type:               conditionError,
+		Status:             metav1.ConditionFalse,
+		Reason:             "DurationValid",
+		Message:            "Duration is valid",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if durationErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InvalidDuration"
+		condition.Message = fmt.Sprintf("Account will not be deleted automatically: %v", durationErr)
+	}
+	// Only report the condition once a problem has been seen
+	if durationErr != nil || meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionError) != nil {
+		changed = meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, condition)
+	}
+
+	noExpiry := metav1.Condition{
+		Type:               conditionNoExpiry,
+		Status:             metav1.ConditionFalse,
+		Reason:             "DurationSet",
+		Message:            "The account is dropped once its duration expires",
+		ObservedGeneration: snowflakeAccount.Generation,
+	}
+	if snowflakeAccount.Spec.Duration == "" {
+		noExpiry.Status = metav1.ConditionTrue
+		noExpiry.Reason = "DurationUnset"
+		noExpiry.Message = "No duration set, the Snowflake account never expires and keeps running until the " +
+			"resource is deleted; set spec.duration to have it deleted automatically"
+	}
+	if noExpiry.Status == metav1.ConditionTrue || meta.FindStatusCondition(snowflakeAccount.Status.Conditions, conditionNoExpiry) != nil {
+		if meta.SetStatusCondition(&snowflakeAccount.Status.Conditions, noExpiry) {
+			changed = true
+			if noExpiry.Status == metav1.ConditionTrue {
+				logf.FromContext(ctx).Info(noExpiry.Message, "accountName", snowflakeAccount.Status.AccountName)
+			}
+		}
 	}
 
-	// Find the first dot to extract the account name
-	for i, ch := range url {
-		if ch == '.' {
-			return url[:i]
+	if changed {
+		if err := r.updateStatus(ctx, snowflakeAccount); err != nil {
+			return durationErr == nil, err
 		}
 	}
+	return durationErr == nil, nil
+}
 
-	return ""
+// accountDuration parses Spec.Duration, returning zero, which never expires, when it is unset or
+// cannot be parsed
+func accountDuration(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (time.Duration, error) {
+	if snowflakeAccount.Spec.Duration == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(snowflakeAccount.Spec.Duration)
+	if err != nil {
+		return 0, err
+	}
+	return duration, nil
 }
 
 // checkDuration checks if the account has exceeded its duration and should be deleted
@@ -117,22 +474,32 @@ func extractAccountNameFromURL(url string) string {
 func (r *SnowflakeAccountReconciler) checkDuration(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) (bool, time.Duration) {
 	log := logf.FromContext(ctx)
 
+	// Refresh the expiry reported in the status, which follows changes to the duration
+	snowflakeAccount.Status.ExpiresAt = r.expiresAt(snowflakeAccount)
+
 	// If no creation time is set, don't delete
 	if snowflakeAccount.Status.CreationTime == nil {
 		log.Info("No creation time set, skipping duration check")
 		return false, 0
 	}
 
-	// Parse the duration from spec (default to 2 minutes)
-	durationStr := snowflakeAccount.Spec.Duration
-	if durationStr == "" {
-		durationStr = "2m"
+	// Expiry is opt-in. Objects created while the CRD defaulted the duration to 2m still carry that
+	// value and keep expiring; only objects without a duration are kept indefinitely.
+	// The NoExpiry condition reports this, see reconcileDurationCondition
+	if snowflakeAccount.Spec.Duration == "" {
+		return false, 0
 	}
 
-	duration, err := time.ParseDuration(durationStr)
+	duration, err := accountDuration(snowflakeAccount)
 	if err != nil {
-		log.Error(err, "Failed to parse duration, using default 2m", "duration", durationStr)
-		duration = 2 * time.Minute
+		log.Error(err, "Failed to parse duration, skipping duration check", "duration", snowflakeAccount.Spec.Duration)
+		return false, 0
+	}
+
+	// Never delete an account because of a nonsensical duration
+	if err := r.validateDuration(duration); err != nil {
+		log.Error(err, "Invalid duration, skipping duration check", "duration", snowflakeAccount.Spec.Duration)
+		return false, 0
 	}
 
 	// Calculate when the account should be deleted
@@ -158,6 +525,54 @@ func (r *SnowflakeAccountReconciler) checkDuration(ctx context.Context, snowflak
 		"currentTime", currentTime,
 		"timeUntilExpiration", timeUntilExpiration)
 
+	// Re-check long-lived accounts periodically rather than only at expiration
+	requeueAfter := timeUntilExpiration
+	if r.MaxRequeueInterval > 0 && requeueAfter > r.MaxRequeueInterval {
+		requeueAfter = r.MaxRequeueInterval
+	}
+
 	// Return false but suggest requeue time
-	return false, timeUntilExpiration
+	return false, r.jitter(requeueAfter)
+}
+
+// expiresAt returns when the duration of the account expires, or nil if it has no creation time or no
+// valid duration and so never expires
+func (r *SnowflakeAccountReconciler) expiresAt(snowflakeAccount *operatorv1alpha1.SnowflakeAccount) *metav1.Time {
+	if snowflakeAccount.Status.CreationTime == nil {
+		return nil
+	}
+	duration, err := accountDuration(snowflakeAccount)
+	if err != nil || r.validateDuration(duration) != nil {
+		return nil
+	}
+	expiresAt := metav1.NewTime(snowflakeAccount.Status.CreationTime.Add(duration))
+	return &expiresAt
+}
+
+// expiryRequeue schedules the first duration check of an account that was just created, so the
+// expiry clock does not depend on an unrelated event triggering the next reconcile
+func (r *SnowflakeAccountReconciler) expiryRequeue(ctx context.Context, snowflakeAccount *operatorv1alpha1.SnowflakeAccount) ctrl.Result {
+	_, requeueAfter := r.checkDuration(ctx, snowflakeAccount)
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
+// ValidateRequeueJitter checks that a requeue jitter fraction is between 0 and 1
+func ValidateRequeueJitter(fraction float64) error {
+	if fraction < 0 || fraction > 1 {
+		return fmt.Errorf("requeue jitter %v must be between 0 and 1", fraction)
+	}
+	return nil
+}
+
+// jitter delays d by a random amount of up to RequeueJitter times d
+func (r *SnowflakeAccountReconciler) jitter(d time.Duration) time.Duration {
+	maxJitter := int64(float64(d) * r.RequeueJitter)
+	if maxJitter <= 0 {
+		return d
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(maxJitter+1))
+	if err != nil {
+		return d
+	}
+	return d + time.Duration(n.Int64())
 }
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("extractAccountNameFromURL", func() {
+	DescribeTable("extracting the account name",
+		func(accountURL, expected string) {
+			Expect(extractAccountNameFromURL(accountURL)).To(Equal(expected))
+		},
+		Entry("empty URL", "", ""),
+		Entry("account URL", "https://SFABC123.snowflakecomputing.com", "SFABC123"),
+		Entry("legacy account.region URL", "https://xy12345.us-east-2.aws.snowflakecomputing.com", "xy12345"),
+		Entry("org-account URL", "https://myorg-SFABC123.snowflakecomputing.com", "SFABC123"),
+		Entry("org-account URL with underscores", "https://myorg-my-account.snowflakecomputing.com", "my_account"),
+		Entry("privatelink URL", "https://myorg-SFABC123.privatelink.snowflakecomputing.com", "SFABC123"),
+		Entry("URL with port and path", "https://SFABC123.snowflakecomputing.com:443/console/login", "SFABC123"),
+		Entry("URL without scheme", "SFABC123.snowflakecomputing.com", "SFABC123"),
+		Entry("host without domain", "https://SFABC123", ""),
+		Entry("legacy privatelink URL", "https://xy12345.us-east-1.privatelink.snowflakecomputing.com", "xy12345"),
+	)
+})
+
+var _ = Describe("buildAccountURL", func() {
+	DescribeTable("round-tripping through extractAccountNameFromURL",
+		func(accountName string, creds *snowflakeCredentials, expectedURL string) {
+			accountURL := buildAccountURL(accountName, creds)
+			Expect(accountURL).To(Equal(expectedURL))
+			Expect(extractAccountNameFromURL(accountURL)).To(Equal(accountName))
+		},
+		Entry("organization account",
+			"SFABC123", &snowflakeCredentials{account: "myorg-admin"},
+			"https://myorg-SFABC123.snowflakecomputing.com"),
+		Entry("account name with underscores",
+			"MY_ACCOUNT", &snowflakeCredentials{account: "myorg-admin"},
+			"https://myorg-MY-ACCOUNT.snowflakecomputing.com"),
+		Entry("privatelink host",
+			"SFABC123", &snowflakeCredentials{account: "myorg-admin", host: "myorg-admin.privatelink.snowflakecomputing.com"},
+			"https://myorg-SFABC123.privatelink.snowflakecomputing.com"),
+		Entry("organization name unknown",
+			"SFABC123", &snowflakeCredentials{account: "xy12345"},
+			"https://SFABC123.snowflakecomputing.com"),
+		Entry("configured organization name with an account locator",
+			"SFABC123", &snowflakeCredentials{account: "xy12345", orgName: "MYORG"},
+			"https://MYORG-SFABC123.snowflakecomputing.com"),
+		Entry("configured organization name taking precedence",
+			"SFABC123", &snowflakeCredentials{account: "other-admin", orgName: "myorg"},
+			"https://myorg-SFABC123.snowflakecomputing.com"),
+	)
+
+	It("should reject an invalid SNOWFLAKE_ORG_NAME", func() {
+		lookup := func(orgName string) func(string) string {
+			return func(key string) string {
+				return map[string]string{
+					"SNOWFLAKE_ORG_USERNAME": "u",
+					"SNOWFLAKE_ORG_PASSWORD": "p",
+					"SNOWFLAKE_ORG_ACCOUNT":  "xy12345",
+					"SNOWFLAKE_ORG_NAME":     orgName,
+				}[key]
+			}
+		}
+		describe := func(key string) string { return key }
+
+		creds, err := parseSnowflakeCredentials(lookup("MYORG"), describe)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accountIdentifier("SFABC123", creds)).To(Equal("MYORG-SFABC123"))
+
+		_, err = parseSnowflakeCredentials(lookup("my-org"), describe)
+		Expect(err).To(MatchError(ContainSubstring("invalid SNOWFLAKE_ORG_NAME")))
+	})
+})
+
+var _ = Describe("checkDuration", func() {
+	var (
+		now        time.Time
+		reconciler *SnowflakeAccountReconciler
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		reconciler = &SnowflakeAccountReconciler{
+			Clock:              clocktesting.NewFakePassiveClock(now),
+			MaxRequeueInterval: 5 * time.Minute,
+		}
+	})
+
+	newAccount := func(duration string, createdAgo time.Duration) *operatorv1alpha1.SnowflakeAccount {
+		creationTime := metav1.NewTime(now.Add(-createdAgo))
+		return &operatorv1alpha1.SnowflakeAccount{
+			Spec:   operatorv1alpha1.SnowflakeAccountSpec{Duration: duration},
+			Status: operatorv1alpha1.SnowflakeAccountStatus{CreationTime: &creationTime},
+		}
+	}
+
+	It("should cap the requeue interval for long durations", func() {
+		shouldDelete, requeueAfter := reconciler.checkDuration(context.Background(), newAccount("24h", time.Hour))
+		Expect(shouldDelete).To(BeFalse())
+		Expect(requeueAfter).To(Equal(5 * time.Minute))
+	})
+
+	It("should keep short durations at their natural interval", func() {
+		shouldDelete, requeueAfter := reconciler.checkDuration(context.Background(), newAccount("2m", time.Minute))
+		Expect(shouldDelete).To(BeFalse())
+		Expect(requeueAfter).To(Equal(time.Minute))
+	})
+
+	It("should not delete accounts with a non-positive duration", func() {
+		shouldDelete, _ := reconciler.checkDuration(context.Background(), newAccount("0s", time.Hour))
+		Expect(shouldDelete).To(BeFalse())
+		shouldDelete, _ = reconciler.checkDuration(context.Background(), newAccount("-1h", time.Hour))
+		Expect(shouldDelete).To(BeFalse())
+	})
+
+	It("should reject durations above the configured ceiling", func() {
+		reconciler.MaxAccountDuration = 24 * time.Hour
+		Expect(reconciler.validateDuration(48 * time.Hour)).NotTo(Succeed())
+		Expect(reconciler.validateDuration(12 * time.Hour)).To(Succeed())
+	})
+
+	It("should delay re-checks by at most the configured jitter", func() {
+		reconciler.RequeueJitter = 0.1
+		for range 20 {
+			_, requeueAfter := reconciler.checkDuration(context.Background(), newAccount("24h", time.Hour))
+			Expect(requeueAfter).To(BeNumerically(">=", 5*time.Minute))
+			Expect(requeueAfter).To(BeNumerically("<=", 5*time.Minute+30*time.Second))
+		}
+
+		Expect(ValidateRequeueJitter(0.1)).To(Succeed())
+		Expect(ValidateRequeueJitter(-0.1)).NotTo(Succeed())
+		Expect(ValidateRequeueJitter(1.5)).NotTo(Succeed())
+	})
+
+	It("should never expire accounts without a duration", func() {
+		shouldDelete, requeueAfter := reconciler.checkDuration(context.Background(), newAccount("", 48*time.Hour))
+		Expect(shouldDelete).To(BeFalse())
+		Expect(requeueAfter).To(BeZero())
+		shouldDelete, _ = reconciler.checkDuration(context.Background(), newAccount("two minutes", 48*time.Hour))
+		Expect(shouldDelete).To(BeFalse())
+	})
+
+	It("should delete expired accounts", func() {
+		shouldDelete, _ := reconciler.checkDuration(context.Background(), newAccount("1h", 2*time.Hour))
+		Expect(shouldDelete).To(BeTrue())
+	})
+
+	It("should report when the account expires", func() {
+		account := newAccount("24h", time.Hour)
+		reconciler.checkDuration(context.Background(), account)
+		Expect(account.Status.ExpiresAt).NotTo(BeNil())
+		Expect(account.Status.ExpiresAt.Time).To(Equal(now.Add(23 * time.Hour)))
+
+		account.Spec.Duration = ""
+		reconciler.checkDuration(context.Background(), account)
+		Expect(account.Status.ExpiresAt).To(BeNil())
+
+		account.Spec.Duration = "-1h"
+		reconciler.checkDuration(context.Background(), account)
+		Expect(account.Status.ExpiresAt).To(BeNil())
+	})
+})
+
+var _ = Describe("generateRandomAccountName", func() {
+	DescribeTable("generating names",
+		func(length int, charset operatorv1alpha1.AccountNameCharset, expectedLength int, pattern string) {
+			name, err := generateRandomAccountName(length, charset)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(HaveLen(expectedLength))
+			Expect(name).To(MatchRegexp(pattern))
+			Expect(accountNamePattern.MatchString(name)).To(BeTrue())
+		},
+		Entry("defaults", 0, operatorv1alpha1.AccountNameCharset(""), 8, `^SF[A-Z0-9]{6}$`),
+		Entry("longer alphanumeric", 20, operatorv1alpha1.AccountNameCharsetAlnum, 20, `^SF[A-Z0-9]{18}$`),
+		Entry("letters only", 12, operatorv1alpha1.AccountNameCharsetAlpha, 12, `^SF[A-Z]{10}$`),
+		Entry("uppercase only", 10, operatorv1alpha1.AccountNameCharsetUpper, 10, `^SF[A-Z]{8}$`),
+	)
+
+	It("should reject lengths outside the limits", func() {
+		_, err := generateRandomAccountName(3, operatorv1alpha1.AccountNameCharsetAlnum)
+		Expect(err).To(HaveOccurred())
+		_, err = generateRandomAccountName(256, operatorv1alpha1.AccountNameCharsetAlnum)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("deterministicAccountName", func() {
+	It("should derive the same valid name from the same UID", func() {
+		name, err := deterministicAccountName("0b7f6a8e-3c1d-4f2a-9e5b-6d4c3b2a1f00")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(MatchRegexp(`^SF[A-Z0-9]{25}$`))
+		Expect(accountNamePattern.MatchString(name)).To(BeTrue())
+
+		again, err := deterministicAccountName("0b7f6a8e-3c1d-4f2a-9e5b-6d4c3b2a1f00")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(Equal(name))
+	})
+
+	It("should derive distinct names from distinct UIDs", func() {
+		names := map[string]bool{}
+		for i := range 1000 {
+			name, err := deterministicAccountName(types.UID(fmt.Sprintf("uid-%d", i)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(HaveLen(27))
+			names[name] = true
+		}
+		Expect(names).To(HaveLen(1000))
+	})
+
+	It("should require a UID", func() {
+		_, err := deterministicAccountName("")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var snowflakeaccountlog = logf.Log.WithName("snowflakeaccount-resource")
+
+// SetupSnowflakeAccountWebhookWithManager registers the webhook for SnowflakeAccount in the manager.
+// The defaulter and validator should be given the same defaults and allowed secret namespaces as the controller.
+func SetupSnowflakeAccountWebhookWithManager(mgr ctrl.Manager, defaulter *SnowflakeAccountCustomDefaulter,
+	validator *SnowflakeAccountCustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&operatorv1alpha1.SnowflakeAccount{}).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-operator-dataverse-redhat-com-v1alpha1-snowflakeaccount,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.dataverse.redhat.com,resources=snowflakeaccounts,verbs=create;update,versions=v1alpha1,name=msnowflakeaccount-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SnowflakeAccountCustomDefaulter sets the defaults of a SnowflakeAccount when it is created or updated,
+// so that the region and edition the operator will use are visible on the stored object
+type SnowflakeAccountCustomDefaulter struct {
+	// DefaultEdition is the edition set when Spec.Edition is unset. If empty, DefaultEdition is used.
+	DefaultEdition operatorv1alpha1.Edition
+
+	// DefaultRegion is the region set when Spec.Region is unset with the fixed region selection
+	// strategy. If empty, DefaultRegion is used.
+	DefaultRegion string
+}
+
+var _ webhook.CustomDefaulter = &SnowflakeAccountCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind SnowflakeAccount.
+func (d *SnowflakeAccountCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	snowflakeaccount, ok := obj.(*operatorv1alpha1.SnowflakeAccount)
+	if !ok {
+		return fmt.Errorf("expected a SnowflakeAccount object but got %T", obj)
+	}
+	snowflakeaccountlog.Info("Defaulting for SnowflakeAccount", "name", snowflakeaccount.GetName())
+
+	snowflakeaccount.DefaultTo(d.DefaultEdition, d.DefaultRegion)
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-operator-dataverse-redhat-com-v1alpha1-snowflakeaccount,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.dataverse.redhat.com,resources=snowflakeaccounts,verbs=create;update,versions=v1alpha1,name=vsnowflakeaccount-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SnowflakeAccountCustomValidator rejects changes to a SnowflakeAccount that cannot be applied to its
+// Snowflake account
+type SnowflakeAccountCustomValidator struct {
+	// AllowedSecretNamespaces are the namespaces other than its own that a SnowflakeAccount may write its
+	// secrets to through spec.secretNamespace. If empty, secrets stay in the namespace of the resource.
+	AllowedSecretNamespaces []string
+}
+
+var _ webhook.CustomValidator = &SnowflakeAccountCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator. A SnowflakeAccount valid by its schema can be created
+// unless it writes its secrets to a namespace the operator does not allow.
+func (v *SnowflakeAccountCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	account, ok := obj.(*operatorv1alpha1.SnowflakeAccount)
+	if !ok {
+		return nil, fmt.Errorf("expected a SnowflakeAccount object but got %T", obj)
+	}
+	if err := v.validateSecretNamespace(account); err != nil {
+		return nil, apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("SnowflakeAccount").GroupKind(),
+			account.Name, field.ErrorList{err})
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator. Snowflake cannot move an account to another region or
+// change its edition, so both are immutable once the account was created. A field that was unset may still
+// be filled in, as the defaulter does for resources created before it was installed. Whether the operator
+// manages the account is immutable too, as neither mode can take over the other's account. A changed secret
+// namespace must be allowed; an unchanged one is accepted so resources created before it was disallowed can
+// still be updated and finalized.
+func (v *SnowflakeAccountCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAccount, ok := oldObj.(*operatorv1alpha1.SnowflakeAccount)
+	if !ok {
+		return nil, fmt.Errorf("expected a SnowflakeAccount object for the old object but got %T", oldObj)
+	}
+	newAccount, ok := newObj.(*operatorv1alpha1.SnowflakeAccount)
+	if !ok {
+		return nil, fmt.Errorf("expected a SnowflakeAccount object for the new object but got %T", newObj)
+	}
+	snowflakeaccountlog.Info("Validating update of SnowflakeAccount", "name", newAccount.GetName())
+
+	var errs field.ErrorList
+	if newAccount.Spec.SecretNamespace != oldAccount.Spec.SecretNamespace {
+		if err := v.validateSecretNamespace(newAccount); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if oldAccount.Status.AccountCreated {
+		errs = append(errs, immutableFieldErrors(oldAccount, newAccount)...)
+	}
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(operatorv1alpha1.GroupVersion.WithKind("SnowflakeAccount").GroupKind(), newAccount.Name, errs)
+	}
+	return nil, nil
+}
+
+// immutableFieldErrors returns an error for each field changed that cannot change once the account exists
+func immutableFieldErrors(oldAccount, newAccount *operatorv1alpha1.SnowflakeAccount) field.ErrorList {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+	if old := oldAccount.Spec.Region; old != "" && newAccount.Spec.Region != old {
+		errs = append(errs, field.Forbidden(specPath.Child("region"),
+			fmt.Sprintf("cannot change from %s once the Snowflake account exists; Snowflake cannot move an account to another region", old)))
+	}
+	if old := oldAccount.Spec.Edition; old != "" && newAccount.Spec.Edition != old {
+		errs = append(errs, field.Forbidden(specPath.Child("edition"),
+			fmt.Sprintf("cannot change from %s once the Snowflake account exists", old)))
+	}
+	if managesAccount(newAccount) != managesAccount(oldAccount) {
+		errs = append(errs, field.Forbidden(specPath.Child("manageAccount"),
+			"cannot change once the Snowflake account exists; create a new SnowflakeAccount instead"))
+	}
+	return errs
+}
+
+// ValidateDelete implements webhook.CustomValidator; deletion is guarded by the controller's finalizer
+func (v *SnowflakeAccountCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if _, ok := obj.(*operatorv1alpha1.SnowflakeAccount); !ok {
+		return nil, fmt.Errorf("expected a SnowflakeAccount object but got %T", obj)
+	}
+	return nil, nil
+}
+
+// validateSecretNamespace rejects a spec.secretNamespace other than the namespace of the resource unless it
+// is one of the allowed secret namespaces, so a user cannot write secrets into namespaces they have no access to
+func (v *SnowflakeAccountCustomValidator) validateSecretNamespace(account *operatorv1alpha1.SnowflakeAccount) *field.Error {
+	namespace := account.Spec.SecretNamespace
+	if namespace == "" || namespace == account.Namespace || slices.Contains(v.AllowedSecretNamespaces, namespace) {
+		return nil
+	}
+	path := field.NewPath("spec", "secretNamespace")
+	if len(v.AllowedSecretNamespaces) == 0 {
+		return field.Forbidden(path, "must be the namespace of the resource; "+
+			"the operator is not configured with --allowed-secret-namespaces")
+	}
+	return field.NotSupported(path, namespace, append([]string{account.Namespace}, v.AllowedSecretNamespaces...))
+}
+
+// managesAccount reports whether the operator manages the account itself, which it does unless
+// Spec.ManageAccount is false
+func managesAccount(account *operatorv1alpha1.SnowflakeAccount) bool {
+	return account.Spec.ManageAccount == nil || *account.Spec.ManageAccount
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	operatorv1alpha1 "github.com/redhat-data-and-ai/speck/api/v1alpha1"
+)
+
+var _ = Describe("SnowflakeAccount Webhook", func() {
+	var (
+		obj       *operatorv1alpha1.SnowflakeAccount
+		defaulter SnowflakeAccountCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &operatorv1alpha1.SnowflakeAccount{}
+		defaulter = SnowflakeAccountCustomDefaulter{}
+	})
+
+	Context("When creating SnowflakeAccount under Defaulting Webhook", func() {
+		It("Should fill in the edition and region but leave the duration unset", func() {
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.Duration).To(BeEmpty())
+			Expect(obj.Spec.Edition).To(Equal(operatorv1alpha1.DefaultEdition))
+			Expect(obj.Spec.Region).To(Equal(operatorv1alpha1.DefaultRegion))
+			Expect(obj.Spec.MaxProvisioningDuration.Duration).To(Equal(operatorv1alpha1.DefaultMaxProvisioningDuration))
+			Expect(obj.Spec.DeletionPolicy).To(Equal(operatorv1alpha1.DeletionPolicyDelete))
+		})
+
+		It("Should retain adopted accounts by default", func() {
+			obj.Spec.AdoptExisting = true
+
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.DeletionPolicy).To(Equal(operatorv1alpha1.DeletionPolicyRetain))
+		})
+
+		It("Should fill in the operator's default edition and region", func() {
+			defaulter = SnowflakeAccountCustomDefaulter{
+				DefaultEdition: operatorv1alpha1.EditionStandard,
+				DefaultRegion:  "AWS_EU_CENTRAL_1",
+			}
+
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.Edition).To(Equal(operatorv1alpha1.EditionStandard))
+			Expect(obj.Spec.Region).To(Equal("AWS_EU_CENTRAL_1"))
+		})
+
+		It("Should keep values that are already set", func() {
+			obj.Spec.Duration = "1h"
+			obj.Spec.Edition = operatorv1alpha1.EditionBusinessCritical
+			obj.Spec.Region = "AWS_EU_WEST_1"
+
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.Duration).To(Equal("1h"))
+			Expect(obj.Spec.Edition).To(Equal(operatorv1alpha1.EditionBusinessCritical))
+			Expect(obj.Spec.Region).To(Equal("AWS_EU_WEST_1"))
+		})
+
+		It("Should not set a region when it is picked from the allowed regions", func() {
+			obj.Spec.RegionSelectionStrategy = operatorv1alpha1.RegionSelectionRoundRobin
+
+			Expect(defaulter.Default(context.Background(), obj)).To(Succeed())
+
+			Expect(obj.Spec.Region).To(BeEmpty())
+		})
+
+		It("Should reject objects of another kind", func() {
+			Expect(defaulter.Default(context.Background(), &corev1.Secret{})).NotTo(Succeed())
+		})
+	})
+
+	Context("When creating SnowflakeAccount under Validating Webhook", func() {
+		It("Should only allow secrets in another namespace that the operator allows", func() {
+			obj.Namespace = "team-a"
+			validator := SnowflakeAccountCustomValidator{}
+
+			obj.Spec.SecretNamespace = "team-a"
+			_, err := validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.SecretNamespace = "kube-system"
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.secretNamespace: Forbidden: must be the namespace of the resource"))
+
+			validator.AllowedSecretNamespaces = []string{"snowflake-creds"}
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`spec.secretNamespace: Unsupported value: "kube-system"`))
+
+			obj.Spec.SecretNamespace = "snowflake-creds"
+			_, err = validator.ValidateCreate(context.Background(), obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When updating SnowflakeAccount under Validating Webhook", func() {
+		var validator SnowflakeAccountCustomValidator
+
+		BeforeEach(func() {
+			obj.Spec.Region = "AWS_US_WEST_2"
+			obj.Spec.Edition = operatorv1alpha1.EditionEnterprise
+		})
+
+		It("Should allow changing the region and edition before the account is created", func() {
+			updated := obj.DeepCopy()
+			updated.Spec.Region = "AWS_EU_WEST_1"
+			updated.Spec.Edition = operatorv1alpha1.EditionStandard
+
+			_, err := validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject changing the region and edition once the account is created", func() {
+			obj.Status.AccountCreated = true
+			updated := obj.DeepCopy()
+			updated.Spec.Region = "AWS_EU_WEST_1"
+			updated.Spec.Edition = operatorv1alpha1.EditionStandard
+
+			_, err := validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.region: Forbidden: cannot change from AWS_US_WEST_2"))
+			Expect(err.Error()).To(ContainSubstring("spec.edition: Forbidden: cannot change from ENTERPRISE"))
+		})
+
+		It("Should reject changing whether the account is managed once the account is created", func() {
+			obj.Status.AccountCreated = true
+			updated := obj.DeepCopy()
+			manageAccount := false
+			updated.Spec.ManageAccount = &manageAccount
+
+			_, err := validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.manageAccount: Forbidden"))
+
+			By("treating an unset field like true")
+			manageAccount = true
+			_, err = validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject moving the secrets to a namespace that is not allowed", func() {
+			obj.Namespace = "team-a"
+			updated := obj.DeepCopy()
+			updated.Spec.SecretNamespace = "kube-system"
+
+			_, err := validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.secretNamespace: Forbidden"))
+
+			By("accepting other updates of a resource whose secret namespace is no longer allowed")
+			obj.Spec.SecretNamespace = "kube-system"
+			updated = obj.DeepCopy()
+			updated.Spec.Comment = "updated"
+			_, err = validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should allow other changes and filling in unset fields once the account is created", func() {
+			obj.Status.AccountCreated = true
+			obj.Spec.Edition = ""
+			updated := obj.DeepCopy()
+			updated.Spec.Comment = "updated"
+			updated.Spec.Edition = operatorv1alpha1.EditionEnterprise
+
+			_, err := validator.ValidateUpdate(context.Background(), obj, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})